@@ -13,3 +13,10 @@ type HashPasswordProvider interface {
 	Generate(password []byte, salt []byte) ([]byte, error)
 	Compare(hashedPassword, password []byte, salt []byte) error
 }
+
+// RehashPasswordProvider a PasswordProvider that can report whether a hashed password
+// is generated by a deprecated algorithm or weaker parameters than its own.
+type RehashPasswordProvider interface {
+	PasswordProvider
+	NeedsRehash(hashedPassword []byte) bool
+}
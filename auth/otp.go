@@ -0,0 +1,260 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidCode returned when a one-time password is not match
+	ErrInvalidCode = errors.New("auth: invalid one-time password")
+
+	// ErrCodeReused returned when a one-time password has been used
+	ErrCodeReused = errors.New("auth: one-time password has been used")
+)
+
+// OTPAlgorithm HMAC hash algorithm used by HOTP/TOTP
+type OTPAlgorithm string
+
+const (
+	OTPAlgorithmSHA1   OTPAlgorithm = "SHA1"
+	OTPAlgorithmSHA256 OTPAlgorithm = "SHA256"
+	OTPAlgorithmSHA512 OTPAlgorithm = "SHA512"
+)
+
+const (
+	_otpSecretSize = 20
+	_otpDigits     = 6
+	_otpPeriod     = 30 * time.Second
+)
+
+// _otpEncoding base32 encoding used by authenticator apps, no padding
+var _otpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var _otpPowers = [...]uint32{1, 10, 100, 1000, 10000, 100000, 1000000, 10000000, 100000000, 1000000000}
+
+func (a OTPAlgorithm) hashFactor() func() hash.Hash {
+	switch a {
+	case OTPAlgorithmSHA256:
+		return sha256.New
+	case OTPAlgorithmSHA512:
+		return sha512.New
+	default:
+		return sha1.New
+	}
+}
+
+// GenerateOTPSecret generate a random secret, use 20 bytes if size <= 0
+func GenerateOTPSecret(size int) ([]byte, error) {
+	if size <= 0 {
+		size = _otpSecretSize
+	}
+	secret := make([]byte, size)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// EncodeOTPSecret encode secret to base32 string without padding used by authenticator apps
+func EncodeOTPSecret(secret []byte) string {
+	return _otpEncoding.EncodeToString(secret)
+}
+
+// DecodeOTPSecret decode base32 secret, spaces and padding are ignored and case insensitive
+func DecodeOTPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	return _otpEncoding.DecodeString(strings.TrimRight(secret, "="))
+}
+
+// HOTP HMAC-based one-time password of RFC 4226
+type HOTP struct {
+	// Digits length of code, use 6 if not in [6, 9]
+	Digits int
+	// Algorithm use SHA1 if empty
+	Algorithm OTPAlgorithm
+}
+
+// Generate generate code of counter
+func (h *HOTP) Generate(secret []byte, counter uint64) string {
+	digits := h.digits()
+	code := strconv.FormatUint(uint64(otpValue(h.Algorithm, secret, counter, digits)), 10)
+	if len(code) < digits {
+		code = strings.Repeat("0", digits-len(code)) + code
+	}
+	return code
+}
+
+// Verify check code in [counter, counter+lookahead] and return the next counter
+// that should be persisted if matched.
+func (h *HOTP) Verify(secret []byte, code string, counter uint64, lookahead uint) (uint64, error) {
+	if len(code) != h.digits() {
+		return counter, ErrInvalidCode
+	}
+	for i := uint64(0); i <= uint64(lookahead); i++ {
+		if subtle.ConstantTimeCompare([]byte(h.Generate(secret, counter+i)), []byte(code)) == 1 {
+			return counter + i + 1, nil
+		}
+	}
+	return counter, ErrInvalidCode
+}
+
+// ProvisioningURI return `otpauth://hotp/...` uri used to generate QR code
+func (h *HOTP) ProvisioningURI(issuer, account string, secret []byte, counter uint64) string {
+	params := otpParams(issuer, secret, h.Algorithm, h.digits())
+	params.Set("counter", strconv.FormatUint(counter, 10))
+	return otpURI("hotp", issuer, account, params)
+}
+
+func (h *HOTP) digits() int {
+	if h.Digits < 6 || h.Digits > 9 {
+		return _otpDigits
+	}
+	return h.Digits
+}
+
+// TOTP time-based one-time password of RFC 6238
+type TOTP struct {
+	HOTP
+
+	// Issuer the provider or service name showed in authenticator apps
+	Issuer string
+	// Period time step, use 30s if less than 1s
+	Period time.Duration
+	// Skew count of time steps before and after current step that accepted
+	Skew uint
+	// Store used to reject the reused code, no replay protection if nil
+	Store UsedCodeStore
+	// Now return current time, use time.Now if nil
+	Now func() time.Time
+}
+
+// Generate generate code at the time t
+func (t *TOTP) Generate(secret []byte, at time.Time) string {
+	return t.HOTP.Generate(secret, t.counter(at))
+}
+
+// Verify check code of account in current time with skew window, a code can only
+// be used once if Store is not nil.
+func (t *TOTP) Verify(account string, secret []byte, code string) error {
+	now := time.Now()
+	if t.Now != nil {
+		now = t.Now()
+	}
+	if len(code) != t.digits() {
+		return ErrInvalidCode
+	}
+	current, skew := t.counter(now), uint64(t.Skew)
+	// check current step first, then previous and next steps one by one
+	for i := uint64(0); i <= 2*skew; i++ {
+		counter := current + (i+1)/2
+		if i%2 == 1 {
+			if current < (i+1)/2 {
+				continue
+			}
+			counter = current - (i+1)/2
+		}
+		if subtle.ConstantTimeCompare([]byte(t.HOTP.Generate(secret, counter)), []byte(code)) != 1 {
+			continue
+		}
+		if t.Store != nil {
+			// the code could not be accepted after the skew window of its time step
+			ttl := time.Unix(0, int64(counter+skew+1)*int64(t.period())).Sub(now)
+			if !t.Store.MarkUsed(account+"/"+strconv.FormatUint(counter, 10), ttl) {
+				return ErrCodeReused
+			}
+		}
+		return nil
+	}
+	return ErrInvalidCode
+}
+
+// ProvisioningURI return `otpauth://totp/...` uri used to generate QR code
+func (t *TOTP) ProvisioningURI(account string, secret []byte) string {
+	params := otpParams(t.Issuer, secret, t.Algorithm, t.digits())
+	params.Set("period", strconv.FormatInt(int64(t.period()/time.Second), 10))
+	return otpURI("totp", t.Issuer, account, params)
+}
+
+func (t *TOTP) period() time.Duration {
+	if t.Period < time.Second {
+		return _otpPeriod
+	}
+	return t.Period
+}
+
+func (t *TOTP) counter(at time.Time) uint64 {
+	return uint64(at.UnixNano() / int64(t.period()))
+}
+
+// NewTOTP create a TOTP with 6 digits, 30s period, SHA1 and one step skew that
+// compatible with most authenticator apps, codes are remembered in memory to
+// prevent replay.
+func NewTOTP(issuer string) *TOTP {
+	return &TOTP{
+		HOTP: HOTP{
+			Digits:    _otpDigits,
+			Algorithm: OTPAlgorithmSHA1,
+		},
+		Issuer: issuer,
+		Period: _otpPeriod,
+		Skew:   1,
+		Store:  NewMemoryUsedCodeStore(),
+	}
+}
+
+// otpValue dynamic truncation of RFC 4226 section 5.3
+func otpValue(algorithm OTPAlgorithm, secret []byte, counter uint64, digits int) uint32 {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(algorithm.hashFactor(), secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+	return value % _otpPowers[digits]
+}
+
+func otpParams(issuer string, secret []byte, algorithm OTPAlgorithm, digits int) url.Values {
+	if len(algorithm) == 0 {
+		algorithm = OTPAlgorithmSHA1
+	}
+	params := url.Values{}
+	params.Set("secret", EncodeOTPSecret(secret))
+	if len(issuer) > 0 {
+		params.Set("issuer", issuer)
+	}
+	params.Set("algorithm", string(algorithm))
+	params.Set("digits", strconv.Itoa(digits))
+	return params
+}
+
+func otpURI(kind string, issuer, account string, params url.Values) string {
+	label := account
+	if len(issuer) > 0 {
+		label = issuer + ":" + account
+	}
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     kind,
+		Path:     "/" + label,
+		RawQuery: params.Encode(),
+	}
+	return u.String()
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package auth
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/alimy/tryst/container/skipmap"
+)
+
+var _ UsedCodeStore = (*memoryUsedCodeStore)(nil)
+
+const _purgeInterval = time.Minute
+
+// UsedCodeStore remember the used one-time passwords to prevent replay
+type UsedCodeStore interface {
+	// MarkUsed mark key used in ttl, return false if key is already used
+	MarkUsed(key string, ttl time.Duration) bool
+}
+
+type memoryUsedCodeStore struct {
+	codes     *skipmap.Map[string, int64]
+	lastPurge atomic.Int64
+}
+
+func (s *memoryUsedCodeStore) MarkUsed(key string, ttl time.Duration) bool {
+	now := time.Now().UnixNano()
+	s.purge(now)
+	expiration := now + int64(ttl)
+	actual, loaded := s.codes.LoadOrStore(key, expiration)
+	if !loaded {
+		return true
+	}
+	if actual > now {
+		return false
+	}
+	// an expired key will be never used by verifier, just renew it
+	s.codes.Store(key, expiration)
+	return true
+}
+
+// purge delete expired keys at most once per interval
+func (s *memoryUsedCodeStore) purge(now int64) {
+	last := s.lastPurge.Load()
+	if now-last < int64(_purgeInterval) || !s.lastPurge.CompareAndSwap(last, now) {
+		return
+	}
+	s.codes.Range(func(key string, value int64) bool {
+		if value <= now {
+			s.codes.Delete(key)
+		}
+		return true
+	})
+}
+
+// NewMemoryUsedCodeStore create an in-memory UsedCodeStore, expired keys are purged lazily
+func NewMemoryUsedCodeStore() UsedCodeStore {
+	s := &memoryUsedCodeStore{
+		codes: skipmap.New[string, int64](),
+	}
+	s.lastPurge.Store(time.Now().UnixNano())
+	return s
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package auth
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestHOTP(t *testing.T) {
+	// test vectors of RFC 4226 appendix D
+	secret := []byte("12345678901234567890")
+	h := &HOTP{}
+	for counter, code := range []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	} {
+		if res := h.Generate(secret, uint64(counter)); res != code {
+			t.Errorf("Generate(%d) want %s got %s", counter, code, res)
+		}
+	}
+	next, err := h.Verify(secret, "969429", 1, 2)
+	if err != nil || next != 4 {
+		t.Errorf("Verify() want next counter 4 got %d err: %v", next, err)
+	}
+	if _, err = h.Verify(secret, "338314", 1, 2); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Verify() out of lookahead want ErrInvalidCode got %v", err)
+	}
+}
+
+func TestTOTP(t *testing.T) {
+	// test vectors of RFC 6238 appendix B
+	for _, data := range []struct {
+		algorithm OTPAlgorithm
+		secret    string
+		at        int64
+		code      string
+	}{
+		{OTPAlgorithmSHA1, "12345678901234567890", 59, "94287082"},
+		{OTPAlgorithmSHA256, "12345678901234567890123456789012", 59, "46119246"},
+		{OTPAlgorithmSHA512, "1234567890123456789012345678901234567890123456789012345678901234", 59, "90693936"},
+		{OTPAlgorithmSHA1, "12345678901234567890", 1111111109, "07081804"},
+		{OTPAlgorithmSHA1, "12345678901234567890", 2000000000, "69279037"},
+	} {
+		totp := &TOTP{HOTP: HOTP{Digits: 8, Algorithm: data.algorithm}}
+		if res := totp.Generate([]byte(data.secret), time.Unix(data.at, 0)); res != data.code {
+			t.Errorf("Generate(%s, %d) want %s got %s", data.algorithm, data.at, data.code, res)
+		}
+	}
+}
+
+func TestTOTPVerify(t *testing.T) {
+	secret, err := GenerateOTPSecret(0)
+	if err != nil {
+		t.Fatalf("GenerateOTPSecret() error: %s", err)
+	}
+	now := time.Unix(1700000000, 0)
+	totp := NewTOTP("tryst")
+	totp.Now = func() time.Time { return now }
+
+	previous := totp.Generate(secret, now.Add(-30*time.Second))
+	if err = totp.Verify("alimy", secret, previous); err != nil {
+		t.Errorf("Verify(previous) error: %s", err)
+	}
+	if err = totp.Verify("alimy", secret, previous); !errors.Is(err, ErrCodeReused) {
+		t.Errorf("Verify(previous) again want ErrCodeReused got %v", err)
+	}
+	if err = totp.Verify("other", secret, previous); err != nil {
+		t.Errorf("Verify(previous) by other account error: %s", err)
+	}
+	if err = totp.Verify("alimy", secret, totp.Generate(secret, now.Add(time.Minute))); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Verify(out of skew) want ErrInvalidCode got %v", err)
+	}
+	if err = totp.Verify("alimy", secret, "12345"); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Verify(short code) want ErrInvalidCode got %v", err)
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	totp := NewTOTP("Tryst Inc")
+	u, err := url.Parse(totp.ProvisioningURI("alimy@niubiu.com", secret))
+	if err != nil {
+		t.Fatalf("ProvisioningURI() got invalid uri: %s", err)
+	}
+	query := u.Query()
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/Tryst Inc:alimy@niubiu.com" {
+		t.Errorf("ProvisioningURI() got unexpected uri: %s", u)
+	}
+	if query.Get("secret") != "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" || query.Get("issuer") != "Tryst Inc" ||
+		query.Get("period") != "30" || query.Get("digits") != "6" || query.Get("algorithm") != "SHA1" {
+		t.Errorf("ProvisioningURI() got unexpected query: %s", u.RawQuery)
+	}
+	res, err := DecodeOTPSecret("gezd gnbv gy3t qojq gezd gnbv gy3t qojq")
+	if err != nil || string(res) != string(secret) {
+		t.Errorf("DecodeOTPSecret() got %q err: %v", res, err)
+	}
+}
+
+func TestMemoryUsedCodeStore(t *testing.T) {
+	s := NewMemoryUsedCodeStore()
+	if !s.MarkUsed("key", time.Minute) || s.MarkUsed("key", time.Minute) {
+		t.Errorf("MarkUsed() want true then false")
+	}
+	if !s.MarkUsed("expired", -time.Second) || !s.MarkUsed("expired", time.Minute) {
+		t.Errorf("MarkUsed() want expired key can be renewed")
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"strconv"
+
+	"golang.org/x/crypto/argon2"
+)
+
+var _ RehashPasswordProvider = (*argon2idPasswordProvider)(nil)
+
+const (
+	argon2idID = "argon2id"
+
+	_argon2SaltLen = 16
+	_argon2KeyLen  = 32
+)
+
+type argon2idPasswordProvider struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+// Generate return a PHC string like `$argon2id$v=19$m=65536,t=1,p=4$<salt>$<hash>`
+func (p *argon2idPasswordProvider) Generate(password []byte) ([]byte, error) {
+	salt := make([]byte, _argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	h := &phcHash{
+		id:      argon2idID,
+		version: argon2.Version,
+		params: []phcParam{
+			{name: "m", value: strconv.FormatUint(uint64(p.memory), 10)},
+			{name: "t", value: strconv.FormatUint(uint64(p.time), 10)},
+			{name: "p", value: strconv.FormatUint(uint64(p.threads), 10)},
+		},
+		salt: salt,
+		hash: argon2.IDKey(password, salt, p.time, p.memory, p.threads, _argon2KeyLen),
+	}
+	return []byte(h.String()), nil
+}
+
+// Compare use the parameters and salt in hashedPassword to compare with password
+func (p *argon2idPasswordProvider) Compare(hashedPassword, password []byte) error {
+	h, err := parseArgon2id(hashedPassword)
+	if err != nil {
+		return err
+	}
+	time, _ := h.uintParam("t", 32)
+	memory, _ := h.uintParam("m", 32)
+	threads, _ := h.uintParam("p", 8)
+	key := argon2.IDKey(password, h.salt, uint32(time), uint32(memory), uint8(threads), uint32(len(h.hash)))
+	if subtle.ConstantTimeCompare(h.hash, key) != 1 {
+		return ErrMismatchedPassword
+	}
+	return nil
+}
+
+// NeedsRehash return true if hashedPassword is not an Argon2id hash or its parameters are weaker than provider's
+func (p *argon2idPasswordProvider) NeedsRehash(hashedPassword []byte) bool {
+	h, err := parseArgon2id(hashedPassword)
+	if err != nil {
+		return true
+	}
+	time, _ := h.uintParam("t", 32)
+	memory, _ := h.uintParam("m", 32)
+	threads, _ := h.uintParam("p", 8)
+	return uint32(time) < p.time || uint32(memory) < p.memory || uint8(threads) < p.threads ||
+		len(h.salt) < _argon2SaltLen || len(h.hash) < _argon2KeyLen
+}
+
+func parseArgon2id(hashedPassword []byte) (*phcHash, error) {
+	h, err := parsePHC(hashedPassword)
+	if err != nil {
+		return nil, err
+	}
+	if h.id != argon2idID {
+		return nil, ErrInvalidHash
+	}
+	if h.version != argon2.Version {
+		return nil, ErrIncompatibleVersion
+	}
+	for _, item := range []struct {
+		name    string
+		bitSize int
+	}{{"m", 32}, {"t", 32}, {"p", 8}} {
+		if v, err := h.uintParam(item.name, item.bitSize); err != nil || v == 0 {
+			return nil, ErrInvalidHash
+		}
+	}
+	return h, nil
+}
+
+// NewArgon2idPasswordProvider create an Argon2id PasswordProvider, memory is in KiB.
+func NewArgon2idPasswordProvider(time, memory uint32, threads uint8) *argon2idPasswordProvider {
+	return &argon2idPasswordProvider{
+		time:    max(time, 1),
+		memory:  max(memory, 8*uint32(max(threads, 1))),
+		threads: max(threads, 1),
+	}
+}
@@ -5,10 +5,18 @@
 package auth
 
 import (
+	"errors"
+	"fmt"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
-var _ PasswordProvider = (*bcryptPasswordProvider)(nil)
+var (
+	_ RehashPasswordProvider = (*bcryptPasswordProvider)(nil)
+
+	// _errBcryptMismatched is both ErrMismatchedPassword and bcrypt.ErrMismatchedHashAndPassword
+	_errBcryptMismatched = fmt.Errorf("%w: %w", ErrMismatchedPassword, bcrypt.ErrMismatchedHashAndPassword)
+)
 
 type bcryptPasswordProvider struct {
 	cost int
@@ -18,8 +26,25 @@ func (p *bcryptPasswordProvider) Generate(password []byte) ([]byte, error) {
 	return bcrypt.GenerateFromPassword(password, p.cost)
 }
 
+// Compare return ErrMismatchedPassword like the other providers if password mismatched,
+// the error is bcrypt.ErrMismatchedHashAndPassword too.
 func (p *bcryptPasswordProvider) Compare(hashedPassword, password []byte) error {
-	return bcrypt.CompareHashAndPassword(hashedPassword, password)
+	err := bcrypt.CompareHashAndPassword(hashedPassword, password)
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return _errBcryptMismatched
+	}
+	return err
+}
+
+// NeedsRehash return true if hashedPassword is not a bcrypt hash or its cost is lower than provider's
+func (p *bcryptPasswordProvider) NeedsRehash(hashedPassword []byte) bool {
+	want := p.cost
+	if want < bcrypt.MinCost {
+		// same as bcrypt.GenerateFromPassword does
+		want = bcrypt.DefaultCost
+	}
+	cost, err := bcrypt.Cost(hashedPassword)
+	return err != nil || cost < want
 }
 
 func NewBcryptPasswordProvider(cost int) *bcryptPasswordProvider {
@@ -0,0 +1,79 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package auth
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var _ BreachSource = (*fsBreachSource)(nil)
+
+// BreachSource k-anonymity source of breached passwords like the range API of
+// Have I Been Pwned, it only knows the first 5 hex characters of SHA-1 hash.
+type BreachSource interface {
+	// Range return the lines like `SUFFIX:COUNT` whose SHA-1 hash start with prefix,
+	// prefix is 5 upper case hex characters and SUFFIX is the rest 35 characters.
+	Range(prefix string) (io.ReadCloser, error)
+}
+
+type fsBreachSource struct {
+	fsys fs.FS
+}
+
+// Range open file `<PREFIX>.txt`, a not exist file means no breached hash
+func (s *fsBreachSource) Range(prefix string) (io.ReadCloser, error) {
+	f, err := s.fsys.Open(prefix + ".txt")
+	if errors.Is(err, fs.ErrNotExist) {
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+	return f, err
+}
+
+// BreachCount return how many times the password appears in breaches of src
+func BreachCount(src BreachSource, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := digest[:5], digest[5:]
+	r, err := src.Range(prefix)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		hash, count, found := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !found || !strings.EqualFold(hash, suffix) {
+			continue
+		}
+		res, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil {
+			return 0, err
+		}
+		return res, nil
+	}
+	return 0, scanner.Err()
+}
+
+// NewFSBreachSource create a BreachSource that read `<PREFIX>.txt` files in fsys,
+// the files are same as the responses of Have I Been Pwned range API.
+func NewFSBreachSource(fsys fs.FS) BreachSource {
+	return &fsBreachSource{
+		fsys: fsys,
+	}
+}
+
+// NewDirBreachSource create a BreachSource that read `<PREFIX>.txt` files in dir
+func NewDirBreachSource(dir string) BreachSource {
+	return NewFSBreachSource(os.DirFS(dir))
+}
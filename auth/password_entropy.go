@@ -0,0 +1,259 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package auth
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// _commonWords common passwords and words ordered by frequency, the index is used as rank
+var _commonWords = rankWords(strings.Fields(`
+	password qwerty dragon monkey letmein football baseball iloveyou master sunshine
+	shadow princess welcome login admin abc passw0rd starwars trustno1 superman
+	hello freedom whatever michael charlie jordan jennifer hunter batman thomas
+	soccer hockey killer george andrew summer love ashley buster daniel secret
+	computer internet cookie orange pepper matrix ginger flower access root
+	test guest user default changeme china india tiger dream angel lovely
+	family server system google apple qazwsx zaq1 asdf pass
+`))
+
+const (
+	// _maxEntropyRunes max runes of password scanned for patterns, the rest are
+	// counted as brute-force characters
+	_maxEntropyRunes = 256
+	// _maxPatternRunes max runes of a pattern, it's raised to the length of the
+	// longest userInputs but no more than _maxInputRunes
+	_maxPatternRunes = 32
+	_maxInputRunes   = 64
+)
+
+// _keyboardRows rows of qwerty keyboard, used to detect keyboard walk
+var _keyboardRows = []string{"1234567890", "qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+// passwordEntropy estimate bits of entropy of password like zxcvbn does. The password
+// is split into patterns of dictionary word, repeat, sequence, keyboard walk, year or
+// brute-force character, and the split with minimum bits is used. userInputs like
+// user name or email are treated as the most common words.
+//
+// Only the first _maxEntropyRunes runes are scanned for patterns no longer than
+// _maxPatternRunes, so the estimate is linear in the length of password.
+func passwordEntropy(password string, userInputs ...string) float64 {
+	raw := []rune(password)
+	if len(raw) == 0 {
+		return 0
+	}
+	charBits := math.Log2(float64(cardinality(raw)))
+	var restBits float64
+	if len(raw) > _maxEntropyRunes {
+		restBits = float64(len(raw)-_maxEntropyRunes) * charBits
+		raw = raw[:_maxEntropyRunes]
+		password = string(raw)
+	}
+	lower := []rune(strings.ToLower(password))
+	if len(lower) != len(raw) {
+		// some runes change size when lower, just fold rune by rune
+		lower = make([]rune, len(raw))
+		for i, r := range raw {
+			lower[i] = unicode.ToLower(r)
+		}
+	}
+	words := make(map[string]int, len(userInputs))
+	maxPattern := _maxPatternRunes
+	for _, input := range userInputs {
+		if input = strings.ToLower(strings.TrimSpace(input)); len(input) > 2 {
+			words[input] = 1
+			maxPattern = max(maxPattern, min(utf8.RuneCountInString(input), _maxInputRunes))
+		}
+	}
+
+	// bits[j] is the minimum bits of password[:j]
+	bits := make([]float64, len(raw)+1)
+	for j := 1; j <= len(raw); j++ {
+		bits[j] = bits[j-1] + charBits
+		for i := j - 3; i >= max(j-maxPattern, 0); i-- {
+			if cost, ok := patternBits(raw[i:j], lower[i:j], words); ok && bits[i]+cost+1 < bits[j] {
+				// one more bit for each pattern to count the way of split
+				bits[j] = bits[i] + cost + 1
+			}
+		}
+	}
+	return bits[len(raw)] + restBits
+}
+
+// patternBits return the minimum bits of patterns that match s whose length >= 3
+func patternBits(raw, lower []rune, words map[string]int) (float64, bool) {
+	res, matched := math.MaxFloat64, false
+	try := func(bits float64, ok bool) {
+		if ok && bits < res {
+			res, matched = bits, true
+		}
+	}
+	try(dictionaryBits(raw, lower, words))
+	try(repeatBits(lower))
+	try(sequenceBits(lower))
+	try(keyboardBits(lower))
+	try(yearBits(lower))
+	return res, matched
+}
+
+func dictionaryBits(raw, lower []rune, words map[string]int) (float64, bool) {
+	word, reversed := string(lower), false
+	rank, exist := words[word]
+	if !exist {
+		rank, exist = _commonWords[word]
+	}
+	if !exist {
+		reversed = true
+		word = reverseRunes(lower)
+		if rank, exist = words[word]; !exist {
+			rank, exist = _commonWords[word]
+		}
+	}
+	if !exist {
+		return 0, false
+	}
+	bits := math.Log2(float64(rank))
+	if reversed {
+		bits++
+	}
+	upper := 0
+	for _, r := range raw {
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+	switch {
+	case upper == 0:
+	case upper == len(raw) || (upper == 1 && unicode.IsUpper(raw[0])):
+		bits++
+	default:
+		bits += float64(upper)
+	}
+	return bits, true
+}
+
+func repeatBits(lower []rune) (float64, bool) {
+	// the smallest base that repeat to s, like `aaa` or `abcabc`
+	for size := 1; size <= len(lower)/2; size++ {
+		if len(lower)%size != 0 {
+			continue
+		}
+		repeated := true
+		for i := size; i < len(lower) && repeated; i++ {
+			repeated = lower[i] == lower[i-size]
+		}
+		if repeated {
+			base := lower[:size]
+			return float64(size)*math.Log2(float64(cardinality(base))) + math.Log2(float64(len(lower)/size)), true
+		}
+	}
+	return 0, false
+}
+
+func sequenceBits(lower []rune) (float64, bool) {
+	delta := lower[1] - lower[0]
+	if delta != 1 && delta != -1 {
+		return 0, false
+	}
+	for i := 2; i < len(lower); i++ {
+		if lower[i]-lower[i-1] != delta {
+			return 0, false
+		}
+	}
+	var start float64
+	switch first := lower[0]; {
+	case strings.ContainsRune("a1z90", first):
+		start = 2
+	case unicode.IsDigit(first):
+		start = math.Log2(10)
+	case unicode.IsLetter(first):
+		start = math.Log2(26)
+	default:
+		start = math.Log2(95)
+	}
+	bits := start + math.Log2(float64(len(lower)))
+	if delta < 0 {
+		bits++
+	}
+	return bits, true
+}
+
+func keyboardBits(lower []rune) (float64, bool) {
+	s := string(lower)
+	for _, row := range _keyboardRows {
+		if strings.Contains(row, s) {
+			return math.Log2(float64(len(row))) + math.Log2(float64(len(lower))), true
+		}
+		if strings.Contains(row, reverseRunes(lower)) {
+			return math.Log2(float64(len(row))) + math.Log2(float64(len(lower))) + 1, true
+		}
+	}
+	return 0, false
+}
+
+func yearBits(lower []rune) (float64, bool) {
+	if len(lower) != 4 {
+		return 0, false
+	}
+	year := 0
+	for _, r := range lower {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+		year = year*10 + int(r-'0')
+	}
+	if year < 1900 || year > 2099 {
+		return 0, false
+	}
+	return math.Log2(200), true
+}
+
+// cardinality return the size of character space of s
+func cardinality(s []rune) int {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	res := 0
+	for _, item := range []struct {
+		exist bool
+		size  int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if item.exist {
+			res += item.size
+		}
+	}
+	return max(res, 1)
+}
+
+func reverseRunes(s []rune) string {
+	res := make([]rune, len(s))
+	for i, r := range s {
+		res[len(s)-1-i] = r
+	}
+	return string(res)
+}
+
+func rankWords(words []string) map[string]int {
+	res := make(map[string]int, len(words))
+	for i, word := range words {
+		res[word] = i + 1
+	}
+	return res
+}
@@ -5,44 +5,139 @@
 package auth
 
 import (
-	"crypto"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
 	"errors"
 	"hash"
-
-	"github.com/alimy/tryst/utils"
+	"strings"
 )
 
 var _ HashPasswordProvider = (*hashPasswordProvider)(nil)
 
+// ErrUnknownPepper returned when the key id of peppered hash is not found
+var ErrUnknownPepper = errors.New("auth: unknown pepper key id of hashed password")
+
+// Pepper server-side secret used as HMAC key, ID will be stored in hashed password
+// so peppers can be rotated while old hashed passwords still verify.
+type Pepper struct {
+	ID  string
+	Key []byte
+}
+
 type hashPasswordProvider struct {
 	hashFactor func() hash.Hash
+	// current pepper used to generate, nil if no pepper
+	current *Pepper
+	peppers map[string][]byte
+	// accept hashed password without pepper when use pepper
+	unpeppered bool
 }
 
+// Generate return the digest of salt+password, or `$<id>$<hmac>` if use pepper
 func (p *hashPasswordProvider) Generate(password []byte, salt []byte) ([]byte, error) {
-	hashFn := p.hashFactor()
-	hashFn.Write(salt)
-	hashFn.Write(password)
-	return hashFn.Sum(nil), nil
+	if p.current == nil {
+		return p.sum(nil, password, salt), nil
+	}
+	prefix := "$" + p.current.ID + "$"
+	res := make([]byte, 0, len(prefix)+p.hashFactor().Size())
+	res = append(res, prefix...)
+	res = append(res, p.sum(p.current.Key, password, salt)...)
+	return res, nil
 }
 
+// Compare compare hashedPassword with password in constant time, hashed password
+// without pepper is rejected if use pepper unless AcceptUnpeppered is called.
 func (p *hashPasswordProvider) Compare(hashedPassword, password []byte, salt []byte) error {
-	hashFn := p.hashFactor()
+	var key []byte
+	digest := hashedPassword
+	if id, rest, peppered := p.cutPepper(hashedPassword); peppered {
+		var exist bool
+		if key, exist = p.peppers[id]; !exist {
+			return ErrUnknownPepper
+		}
+		digest = rest
+	} else if p.current != nil && !p.unpeppered {
+		return ErrMismatchedPassword
+	}
+	if subtle.ConstantTimeCompare(digest, p.sum(key, password, salt)) != 1 {
+		return ErrMismatchedPassword
+	}
+	return nil
+}
+
+// NeedsRehash return true if hashedPassword is not peppered by current pepper
+func (p *hashPasswordProvider) NeedsRehash(hashedPassword []byte) bool {
+	id, _, peppered := p.cutPepper(hashedPassword)
+	if p.current == nil {
+		return peppered
+	}
+	return !peppered || id != p.current.ID
+}
+
+// AcceptUnpeppered make Compare accept hashed password without pepper, it's used
+// to migrate hashed passwords generated before peppers are introduced and should
+// be turned off once all of them are rehashed.
+func (p *hashPasswordProvider) AcceptUnpeppered() *hashPasswordProvider {
+	p.unpeppered = true
+	return p
+}
+
+func (p *hashPasswordProvider) sum(key []byte, password []byte, salt []byte) []byte {
+	var hashFn hash.Hash
+	if key != nil {
+		hashFn = hmac.New(p.hashFactor, key)
+	} else {
+		hashFn = p.hashFactor()
+	}
 	hashFn.Write(salt)
 	hashFn.Write(password)
-	if !utils.EqualBytes(hashedPassword, hashFn.Sum(nil)) {
-		return errors.New("incorrect password")
+	return hashFn.Sum(nil)
+}
+
+// cutPepper split `$<id>$<digest>` to id and digest, a digest without pepper has the
+// fixed size of hash so it will never be treated as peppered.
+func (p *hashPasswordProvider) cutPepper(hashedPassword []byte) (id string, digest []byte, peppered bool) {
+	size := p.hashFactor().Size()
+	if len(hashedPassword) <= size+2 || hashedPassword[0] != '$' {
+		return
 	}
-	return nil
+	idx := bytes.IndexByte(hashedPassword[1:], '$')
+	if idx < 1 || len(hashedPassword)-idx-2 != size {
+		return
+	}
+	return string(hashedPassword[1 : idx+1]), hashedPassword[idx+2:], true
 }
 
-func NewHashPasswordProvider(hashFactor func() hash.Hash) *hashPasswordProvider {
-	return &hashPasswordProvider{
+// NewHashPasswordProvider create a HashPasswordProvider, the first pepper is used to
+// generate hashed password and all peppers are used to compare.
+func NewHashPasswordProvider(hashFactor func() hash.Hash, peppers ...Pepper) *hashPasswordProvider {
+	p := &hashPasswordProvider{
 		hashFactor: hashFactor,
+		peppers:    make(map[string][]byte, len(peppers)),
 	}
+	for i := range peppers {
+		if len(peppers[i].ID) == 0 || strings.IndexByte(peppers[i].ID, '$') >= 0 {
+			panic("auth: invalid pepper id " + peppers[i].ID)
+		}
+		if len(peppers[i].Key) == 0 {
+			panic("auth: empty pepper key for id " + peppers[i].ID)
+		}
+		if _, exist := p.peppers[peppers[i].ID]; exist {
+			panic("auth: multiple peppers for id " + peppers[i].ID)
+		}
+		p.peppers[peppers[i].ID] = bytes.Clone(peppers[i].Key)
+	}
+	if len(peppers) > 0 {
+		p.current = &Pepper{
+			ID:  peppers[0].ID,
+			Key: p.peppers[peppers[0].ID],
+		}
+	}
+	return p
 }
 
-func NewSha1PasswordProvider() *hashPasswordProvider {
-	return &hashPasswordProvider{
-		hashFactor: crypto.SHA1.New,
-	}
+func NewSha1PasswordProvider(peppers ...Pepper) *hashPasswordProvider {
+	return NewHashPasswordProvider(sha1.New, peppers...)
 }
@@ -0,0 +1,96 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package auth
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var _ RehashPasswordProvider = (*multiPasswordProvider)(nil)
+
+// ErrUnknownAlgorithm returned when the algorithm of hashed password can not be detected
+var ErrUnknownAlgorithm = errors.New("auth: unknown algorithm of hashed password")
+
+// _verifiers verifiers for hashed password detected by PHC id or bcrypt prefix, the
+// parameters used to verify are stored in hashed password, so any instance is ok.
+var _verifiers = map[string]PasswordProvider{
+	"2a":       NewBcryptPasswordProvider(bcrypt.DefaultCost),
+	"2b":       NewBcryptPasswordProvider(bcrypt.DefaultCost),
+	"2y":       NewBcryptPasswordProvider(bcrypt.DefaultCost),
+	argon2idID: NewArgon2idPasswordProvider(1, 64*1024, 4),
+	scryptID:   NewScryptPasswordProvider(15, 8, 1),
+}
+
+type multiPasswordProvider struct {
+	current RehashPasswordProvider
+	legacy  []HashPasswordProvider
+}
+
+// Generate generate hashed password by current provider
+func (p *multiPasswordProvider) Generate(password []byte) ([]byte, error) {
+	return p.current.Generate(password)
+}
+
+// Compare detect the algorithm from hashedPassword prefix and compare with password.
+// Legacy hashes are compared with empty salt, use CompareAndRehash if salt is needed.
+func (p *multiPasswordProvider) Compare(hashedPassword, password []byte) error {
+	_, err := p.compare(hashedPassword, password, nil)
+	return err
+}
+
+// NeedsRehash return true if hashedPassword use a deprecated algorithm or weaker
+// parameters than current provider.
+func (p *multiPasswordProvider) NeedsRehash(hashedPassword []byte) bool {
+	return p.current.NeedsRehash(hashedPassword)
+}
+
+// CompareAndRehash compare hashedPassword with password, salt is only used by legacy
+// HashPasswordProvider. It return a new hashed password generated by current provider
+// if hashedPassword needs rehash, otherwise return nil, so the caller can upgrade the
+// stored hash in place after login success.
+func (p *multiPasswordProvider) CompareAndRehash(hashedPassword, password []byte, salt []byte) ([]byte, error) {
+	isLegacy, err := p.compare(hashedPassword, password, salt)
+	if err != nil {
+		return nil, err
+	}
+	if !isLegacy && !p.current.NeedsRehash(hashedPassword) {
+		return nil, nil
+	}
+	return p.current.Generate(password)
+}
+
+func (p *multiPasswordProvider) compare(hashedPassword, password []byte, salt []byte) (isLegacy bool, err error) {
+	if verifier, exist := _verifiers[phcID(hashedPassword)]; exist {
+		return false, verifier.Compare(hashedPassword, password)
+	}
+	// current may be a custom provider that is not detected by id
+	if err = p.current.Compare(hashedPassword, password); err == nil || errors.Is(err, ErrMismatchedPassword) {
+		return false, err
+	}
+	if len(p.legacy) == 0 {
+		return false, ErrUnknownAlgorithm
+	}
+	for _, legacy := range p.legacy {
+		if err = legacy.Compare(hashedPassword, password, salt); err == nil {
+			return true, nil
+		}
+	}
+	return true, err
+}
+
+// NewMultiPasswordProvider create a PasswordProvider that generate hashed password by
+// current and verify bcrypt, Argon2id, scrypt hashed password by the detected algorithm.
+// Hashed password that can not be detected will be compared by current, then by legacy
+// providers in order and always needs rehash if it's matched by a legacy provider.
+// A custom current provider should return ErrMismatchedPassword for its own hashes that
+// not match, so they are not compared by legacy providers.
+func NewMultiPasswordProvider(current RehashPasswordProvider, legacy ...HashPasswordProvider) *multiPasswordProvider {
+	return &multiPasswordProvider{
+		current: current,
+		legacy:  legacy,
+	}
+}
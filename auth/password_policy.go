@@ -0,0 +1,201 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package auth
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alimy/tryst/i18n"
+	"github.com/alimy/tryst/lang/stringx"
+)
+
+// Violation codes of PasswordPolicy, used as i18n keys
+const (
+	ViolationTooShort     = "auth.password.too_short"
+	ViolationTooLong      = "auth.password.too_long"
+	ViolationNoUpper      = "auth.password.no_upper"
+	ViolationNoLower      = "auth.password.no_lower"
+	ViolationNoDigit      = "auth.password.no_digit"
+	ViolationNoSymbol     = "auth.password.no_symbol"
+	ViolationUserInput    = "auth.password.user_input"
+	ViolationTooWeak      = "auth.password.too_weak"
+	ViolationBreached     = "auth.password.breached"
+	ViolationBreachFailed = "auth.password.breach_failed"
+)
+
+// _violationMessages default message templates of violations, `{name}` is replaced by Args
+var _violationMessages = map[string]string{
+	ViolationTooShort:     "password must be at least {min} characters",
+	ViolationTooLong:      "password must be at most {max} characters",
+	ViolationNoUpper:      "password must contain an upper case letter",
+	ViolationNoLower:      "password must contain a lower case letter",
+	ViolationNoDigit:      "password must contain a digit",
+	ViolationNoSymbol:     "password must contain a symbol",
+	ViolationUserInput:    "password must not contain personal information",
+	ViolationTooWeak:      "password is too easy to guess, about {entropy} bits of entropy but {min} required",
+	ViolationBreached:     "password has appeared in {count} data breaches",
+	ViolationBreachFailed: "password could not be checked against data breaches",
+}
+
+// Violation a rule of PasswordPolicy that password violated
+type Violation struct {
+	// Code the rule code, also used as i18n key
+	Code string
+	// Args arguments used to format message like `min` of ViolationTooShort
+	Args map[string]string
+}
+
+// Error return default English message
+func (v Violation) Error() string {
+	return v.format(_violationMessages[v.Code])
+}
+
+// Localize return message translated by i18n assets of name like `zh` or `en`,
+// the message template use `{arg}` placeholder.
+func (v Violation) Localize(name string) string {
+	return v.format(i18n.Get(name, v.Code, _violationMessages[v.Code]))
+}
+
+func (v Violation) format(tmpl string) string {
+	for name, value := range v.Args {
+		tmpl = strings.ReplaceAll(tmpl, "{"+name+"}", value)
+	}
+	return tmpl
+}
+
+// PolicyError error contains all violations of password
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.Error())
+	}
+	return "auth: " + strings.Join(msgs, "; ")
+}
+
+// Has check whether violation of code exist
+func (e *PolicyError) Has(code string) bool {
+	for _, v := range e.Violations {
+		if v.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// PasswordPolicy rules checked before PasswordProvider.Generate, zero value means no rule
+type PasswordPolicy struct {
+	// MinLength min count of characters
+	MinLength int
+	// MaxLength max count of characters, like 72 for bcrypt
+	MaxLength int
+	// RequireUpper require an upper case letter
+	RequireUpper bool
+	// RequireLower require a lower case letter
+	RequireLower bool
+	// RequireDigit require a digit
+	RequireDigit bool
+	// RequireSymbol require a character neither letter nor digit
+	RequireSymbol bool
+	// MinEntropy min estimated bits of entropy, about 10 bits per a guess of 1024 times
+	MinEntropy float64
+	// Breaches check password whether in breaches if not nil
+	Breaches BreachSource
+	// MaxBreachCount max times password allow appear in breaches
+	MaxBreachCount int
+}
+
+// Check check password and return all violations, userInputs like user name or
+// email must not be contained in password and are treated as weak words.
+func (p *PasswordPolicy) Check(password string, userInputs ...string) []Violation {
+	var res []Violation
+	size := utf8.RuneCountInString(password)
+	if p.MinLength > 0 && size < p.MinLength {
+		res = append(res, newViolation(ViolationTooShort, "min", strconv.Itoa(p.MinLength)))
+	}
+	tooLong := p.MaxLength > 0 && size > p.MaxLength
+	if tooLong {
+		res = append(res, newViolation(ViolationTooLong, "max", strconv.Itoa(p.MaxLength)))
+	}
+	for _, rule := range []struct {
+		required bool
+		has      func(string) bool
+		code     string
+	}{
+		{p.RequireUpper, stringx.HasUpper, ViolationNoUpper},
+		{p.RequireLower, stringx.HasLower, ViolationNoLower},
+		{p.RequireDigit, stringx.HasNumeric, ViolationNoDigit},
+		{p.RequireSymbol, stringx.HasSymbol, ViolationNoSymbol},
+	} {
+		if rule.required && !rule.has(password) {
+			res = append(res, newViolation(rule.code))
+		}
+	}
+	lower := strings.ToLower(password)
+	for _, input := range userInputs {
+		if input = strings.ToLower(strings.TrimSpace(input)); len(input) > 2 && strings.Contains(lower, input) {
+			res = append(res, newViolation(ViolationUserInput))
+			break
+		}
+	}
+	// too long password is rejected anyway, don't spend time to estimate it
+	if p.MinEntropy > 0 && !tooLong {
+		if bits := passwordEntropy(password, userInputs...); bits < p.MinEntropy {
+			res = append(res, newViolation(ViolationTooWeak,
+				"entropy", strconv.Itoa(int(math.Floor(bits))), "min", strconv.Itoa(int(p.MinEntropy))))
+		}
+	}
+	if p.Breaches != nil {
+		if count, err := BreachCount(p.Breaches, password); err != nil {
+			res = append(res, newViolation(ViolationBreachFailed))
+		} else if count > p.MaxBreachCount {
+			res = append(res, newViolation(ViolationBreached, "count", strconv.Itoa(count)))
+		}
+	}
+	return res
+}
+
+// Validate check password and return *PolicyError if any violation
+func (p *PasswordPolicy) Validate(password string, userInputs ...string) error {
+	if violations := p.Check(password, userInputs...); len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// PasswordEntropy return the estimated bits of entropy of password
+func PasswordEntropy(password string, userInputs ...string) float64 {
+	return passwordEntropy(password, userInputs...)
+}
+
+// NewPasswordPolicy create a PasswordPolicy that require at least 8 characters with
+// upper case, lower case letter and digit, and about 40 bits entropy.
+func NewPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:    8,
+		MaxLength:    128,
+		RequireUpper: true,
+		RequireLower: true,
+		RequireDigit: true,
+		MinEntropy:   40,
+	}
+}
+
+func newViolation(code string, kv ...string) Violation {
+	v := Violation{Code: code}
+	if len(kv) > 0 {
+		v.Args = make(map[string]string, len(kv)/2)
+		for i := 0; i+1 < len(kv); i += 2 {
+			v.Args[kv[i]] = kv[i+1]
+		}
+	}
+	return v
+}
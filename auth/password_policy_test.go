@@ -0,0 +1,103 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/alimy/tryst/i18n"
+)
+
+func TestPasswordEntropy(t *testing.T) {
+	for _, data := range []struct {
+		weak   string
+		strong string
+	}{
+		{"password", "xkcd-Tr0ub4dor"},
+		{"aaaaaaaaaaaa", "a8#kQ2!mZ0pL"},
+		{"abcdefghijkl", "hqzwmxbtrlkj"},
+		{"qwertyuiop", "wqyertiuop"},
+		{"Password1990", "Pzssqkrd1849x"},
+	} {
+		weak, strong := PasswordEntropy(data.weak), PasswordEntropy(data.strong)
+		if weak >= strong {
+			t.Errorf("PasswordEntropy(%s)=%.1f want less than PasswordEntropy(%s)=%.1f", data.weak, weak, data.strong, strong)
+		}
+	}
+	if bits := PasswordEntropy("alimy2024", "alimy"); bits >= PasswordEntropy("alimy2024") {
+		t.Errorf("PasswordEntropy() want user input reduce entropy got %.1f", bits)
+	}
+	if bits := PasswordEntropy(""); bits != 0 {
+		t.Errorf("PasswordEntropy(\"\") want 0 got %.1f", bits)
+	}
+	// long password is estimated in linear time
+	long := strings.Repeat("x7#Kq", 1<<14)
+	if bits := PasswordEntropy(long); bits <= PasswordEntropy(long[:_maxEntropyRunes]) {
+		t.Errorf("PasswordEntropy() want runes beyond %d counted got %.1f", _maxEntropyRunes, bits)
+	}
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	p := NewPasswordPolicy()
+	for _, data := range []struct {
+		password string
+		codes    []string
+	}{
+		{"Sh0rt", []string{ViolationTooShort, ViolationTooWeak}},
+		{"alllowercase", []string{ViolationNoUpper, ViolationNoDigit}},
+		{"Password1", []string{ViolationTooWeak}},
+		{"Alimy-Zq8wr!xK", []string{ViolationUserInput}},
+		{"Vq7#mZ2pLk9x", nil},
+		{strings.Repeat("a", 200), []string{ViolationTooLong, ViolationNoUpper, ViolationNoDigit}},
+	} {
+		violations := p.Check(data.password, "alimy")
+		codes := make([]string, 0, len(violations))
+		for _, v := range violations {
+			codes = append(codes, v.Code)
+		}
+		if strings.Join(codes, ",") != strings.Join(data.codes, ",") {
+			t.Errorf("Check(%s) want %v got %v", data.password, data.codes, codes)
+		}
+	}
+	err := p.Validate("short")
+	var pe *PolicyError
+	if !errors.As(err, &pe) || !pe.Has(ViolationTooShort) {
+		t.Errorf("Validate() want PolicyError with too short got %v", err)
+	}
+	if v := p.Check("Password1"); len(v) != 1 || !strings.Contains(v[0].Error(), "but 40 required") {
+		t.Errorf("Check() want too weak message with min entropy got %v", v)
+	}
+}
+
+func TestPasswordBreach(t *testing.T) {
+	sum := sha1.Sum([]byte("P@ssw0rd"))
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+	src := NewFSBreachSource(fstest.MapFS{
+		digest[:5] + ".txt": {Data: []byte("0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n" + digest[5:] + ":52000\r\n")},
+	})
+	if count, err := BreachCount(src, "P@ssw0rd"); err != nil || count != 52000 {
+		t.Errorf("BreachCount() want 52000 got %d err: %v", count, err)
+	}
+	if count, err := BreachCount(src, "Vq7#mZ2pLk9x"); err != nil || count != 0 {
+		t.Errorf("BreachCount() want 0 got %d err: %v", count, err)
+	}
+	p := &PasswordPolicy{Breaches: src}
+	violations := p.Check("P@ssw0rd")
+	if len(violations) != 1 || violations[0].Code != ViolationBreached {
+		t.Fatalf("Check() want breached violation got %v", violations)
+	}
+	if msg := violations[0].Error(); msg != "password has appeared in 52000 data breaches" {
+		t.Errorf("Error() got %s", msg)
+	}
+	i18n.Add("zh", map[string]string{ViolationBreached: "密码已在 {count} 次数据泄露中出现"})
+	if msg := violations[0].Localize("zh"); msg != "密码已在 52000 次数据泄露中出现" {
+		t.Errorf("Localize(zh) got %s", msg)
+	}
+}
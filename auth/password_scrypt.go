@@ -0,0 +1,113 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"strconv"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+var _ RehashPasswordProvider = (*scryptPasswordProvider)(nil)
+
+const (
+	scryptID = "scrypt"
+
+	_scryptSaltLen = 16
+	_scryptKeyLen  = 32
+)
+
+type scryptPasswordProvider struct {
+	// logN is log2 of the CPU/memory cost parameter N
+	logN uint8
+	r    int
+	p    int
+}
+
+// Generate return a PHC string like `$scrypt$ln=15,r=8,p=1$<salt>$<hash>`
+func (p *scryptPasswordProvider) Generate(password []byte) ([]byte, error) {
+	salt := make([]byte, _scryptSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(password, salt, 1<<p.logN, p.r, p.p, _scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	h := &phcHash{
+		id: scryptID,
+		params: []phcParam{
+			{name: "ln", value: strconv.FormatUint(uint64(p.logN), 10)},
+			{name: "r", value: strconv.Itoa(p.r)},
+			{name: "p", value: strconv.Itoa(p.p)},
+		},
+		salt: salt,
+		hash: key,
+	}
+	return []byte(h.String()), nil
+}
+
+// Compare use the parameters and salt in hashedPassword to compare with password
+func (p *scryptPasswordProvider) Compare(hashedPassword, password []byte) error {
+	h, err := parseScrypt(hashedPassword)
+	if err != nil {
+		return err
+	}
+	logN, _ := h.uintParam("ln", 6)
+	r, _ := h.uintParam("r", 31)
+	pp, _ := h.uintParam("p", 31)
+	key, err := scrypt.Key(password, h.salt, 1<<logN, int(r), int(pp), len(h.hash))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(h.hash, key) != 1 {
+		return ErrMismatchedPassword
+	}
+	return nil
+}
+
+// NeedsRehash return true if hashedPassword is not a scrypt hash or its parameters are weaker than provider's
+func (p *scryptPasswordProvider) NeedsRehash(hashedPassword []byte) bool {
+	h, err := parseScrypt(hashedPassword)
+	if err != nil {
+		return true
+	}
+	logN, _ := h.uintParam("ln", 6)
+	r, _ := h.uintParam("r", 31)
+	pp, _ := h.uintParam("p", 31)
+	return uint8(logN) < p.logN || int(r) < p.r || int(pp) < p.p ||
+		len(h.salt) < _scryptSaltLen || len(h.hash) < _scryptKeyLen
+}
+
+func parseScrypt(hashedPassword []byte) (*phcHash, error) {
+	h, err := parsePHC(hashedPassword)
+	if err != nil {
+		return nil, err
+	}
+	if h.id != scryptID {
+		return nil, ErrInvalidHash
+	}
+	for _, item := range []struct {
+		name    string
+		bitSize int
+	}{{"ln", 6}, {"r", 31}, {"p", 31}} {
+		if v, err := h.uintParam(item.name, item.bitSize); err != nil || v == 0 {
+			return nil, ErrInvalidHash
+		}
+	}
+	return h, nil
+}
+
+// NewScryptPasswordProvider create a scrypt PasswordProvider, the CPU/memory cost
+// parameter N is 1<<logN.
+func NewScryptPasswordProvider(logN uint8, r, p int) *scryptPasswordProvider {
+	return &scryptPasswordProvider{
+		logN: min(max(logN, 1), 62),
+		r:    max(r, 1),
+		p:    max(p, 1),
+	}
+}
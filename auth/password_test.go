@@ -0,0 +1,229 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package auth
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPHCPasswordProvider(t *testing.T) {
+	for _, data := range []struct {
+		name     string
+		prefix   string
+		provider PasswordProvider
+	}{
+		{"argon2id", "$argon2id$v=19$m=1024,t=1,p=1$", NewArgon2idPasswordProvider(1, 1024, 1)},
+		{"scrypt", "$scrypt$ln=10,r=8,p=1$", NewScryptPasswordProvider(10, 8, 1)},
+	} {
+		password := []byte("correct horse battery staple")
+		hashed, err := data.provider.Generate(password)
+		if err != nil {
+			t.Fatalf("%s: Generate() error: %s", data.name, err)
+		}
+		if !bytes.HasPrefix(hashed, []byte(data.prefix)) {
+			t.Errorf("%s: Generate() want prefix %s got %s", data.name, data.prefix, hashed)
+		}
+		if err = data.provider.Compare(hashed, password); err != nil {
+			t.Errorf("%s: Compare() want nil got %s", data.name, err)
+		}
+		if err = data.provider.Compare(hashed, []byte("wrong password")); !errors.Is(err, ErrMismatchedPassword) {
+			t.Errorf("%s: Compare() want ErrMismatchedPassword got %v", data.name, err)
+		}
+		other, _ := data.provider.Generate(password)
+		if bytes.Equal(hashed, other) {
+			t.Errorf("%s: Generate() want random salt but got same hash twice", data.name)
+		}
+		for _, bad := range []string{"", "$", string(hashed[:bytes.LastIndexByte(hashed, '$')]), "$bcrypt$xx$yy$zz"} {
+			if err = data.provider.Compare([]byte(bad), password); !errors.Is(err, ErrInvalidHash) {
+				t.Errorf("%s: Compare(%q) want ErrInvalidHash got %v", data.name, bad, err)
+			}
+		}
+	}
+}
+
+func TestParsePHC(t *testing.T) {
+	s := "$argon2id$v=19$m=65536,t=3,p=4$c29tZXNhbHQ$aGFzaA"
+	h, err := parsePHC([]byte(s))
+	if err != nil {
+		t.Fatalf("parsePHC(%s) error: %s", s, err)
+	}
+	if h.id != "argon2id" || h.version != 19 || string(h.salt) != "somesalt" || string(h.hash) != "hash" {
+		t.Errorf("parsePHC(%s) got unexpected result: %+v", s, h)
+	}
+	if v, _ := h.param("t"); v != "3" {
+		t.Errorf("param(t) want 3 got %s", v)
+	}
+	if res := h.String(); res != s {
+		t.Errorf("String() want %s got %s", s, res)
+	}
+	if id := phcID([]byte(s)); id != "argon2id" {
+		t.Errorf("phcID() want argon2id got %s", id)
+	}
+}
+
+func TestMultiPasswordProvider(t *testing.T) {
+	password := []byte("correct horse battery staple")
+	current := NewArgon2idPasswordProvider(1, 1024, 1)
+	sha1 := NewSha1PasswordProvider()
+	p := NewMultiPasswordProvider(current, sha1)
+
+	salt := []byte("salt")
+	legacyHash, _ := sha1.Generate(password, salt)
+	bcryptHash, _ := NewBcryptPasswordProvider(bcrypt.MinCost).Generate(password)
+	scryptHash, _ := NewScryptPasswordProvider(10, 8, 1).Generate(password)
+	weakHash, _ := NewArgon2idPasswordProvider(1, 512, 1).Generate(password)
+	currentHash, _ := p.Generate(password)
+
+	for _, data := range []struct {
+		name   string
+		hashed []byte
+		rehash bool
+	}{
+		{"sha1", legacyHash, true},
+		{"bcrypt", bcryptHash, true},
+		{"scrypt", scryptHash, true},
+		{"weak argon2id", weakHash, true},
+		{"current", currentHash, false},
+	} {
+		if res := p.NeedsRehash(data.hashed); res != data.rehash {
+			t.Errorf("%s: NeedsRehash() want %t got %t", data.name, data.rehash, res)
+		}
+		newHash, err := p.CompareAndRehash(data.hashed, password, salt)
+		if err != nil {
+			t.Errorf("%s: CompareAndRehash() error: %s", data.name, err)
+			continue
+		}
+		if (newHash != nil) != data.rehash {
+			t.Errorf("%s: CompareAndRehash() want rehash %t got %q", data.name, data.rehash, newHash)
+		}
+		if newHash != nil && (p.NeedsRehash(newHash) || current.Compare(newHash, password) != nil) {
+			t.Errorf("%s: CompareAndRehash() got an unexpected new hash %q", data.name, newHash)
+		}
+		if _, err = p.CompareAndRehash(data.hashed, []byte("wrong password"), salt); !errors.Is(err, ErrMismatchedPassword) {
+			t.Errorf("%s: CompareAndRehash() with wrong password want ErrMismatchedPassword got %v", data.name, err)
+		}
+	}
+	if err := NewMultiPasswordProvider(current).Compare(legacyHash, password); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("Compare() want ErrUnknownAlgorithm got %v", err)
+	}
+	// bcrypt mismatch is still reported as the bcrypt error for existing callers
+	if err := p.Compare(bcryptHash, []byte("wrong password")); !errors.Is(err, ErrMismatchedPassword) || !errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		t.Errorf("Compare() of bcrypt hash want ErrMismatchedPassword and bcrypt.ErrMismatchedHashAndPassword got %v", err)
+	}
+}
+
+// customPasswordProvider a provider with an id unknown to multiPasswordProvider
+type customPasswordProvider struct{}
+
+func (customPasswordProvider) Generate(password []byte) ([]byte, error) {
+	sum := sha256.Sum256(password)
+	return []byte("$custom$" + hex.EncodeToString(sum[:])), nil
+}
+
+func (p customPasswordProvider) Compare(hashedPassword, password []byte) error {
+	if !bytes.HasPrefix(hashedPassword, []byte("$custom$")) {
+		return ErrUnknownAlgorithm
+	}
+	if expect, _ := p.Generate(password); !bytes.Equal(expect, hashedPassword) {
+		return ErrMismatchedPassword
+	}
+	return nil
+}
+
+func (customPasswordProvider) NeedsRehash(hashedPassword []byte) bool {
+	return !bytes.HasPrefix(hashedPassword, []byte("$custom$"))
+}
+
+func TestMultiPasswordProviderCustom(t *testing.T) {
+	password := []byte("correct horse battery staple")
+	sha1 := NewSha1PasswordProvider()
+	p := NewMultiPasswordProvider(customPasswordProvider{}, sha1)
+	hashed, _ := p.Generate(password)
+	if newHash, err := p.CompareAndRehash(hashed, password, nil); err != nil || newHash != nil {
+		t.Errorf("CompareAndRehash() want nil, nil got %q, %v", newHash, err)
+	}
+	if err := p.Compare(hashed, []byte("wrong password")); !errors.Is(err, ErrMismatchedPassword) {
+		t.Errorf("Compare() want ErrMismatchedPassword got %v", err)
+	}
+	legacyHash, _ := sha1.Generate(password, nil)
+	if newHash, err := p.CompareAndRehash(legacyHash, password, nil); err != nil || newHash == nil {
+		t.Errorf("CompareAndRehash() of legacy hash want new hash got %q, %v", newHash, err)
+	}
+	if err := NewMultiPasswordProvider(customPasswordProvider{}).Compare(legacyHash, password); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("Compare() want ErrUnknownAlgorithm got %v", err)
+	}
+}
+
+func TestBcryptNeedsRehash(t *testing.T) {
+	p := NewBcryptPasswordProvider(bcrypt.MinCost + 1)
+	weak, _ := NewBcryptPasswordProvider(bcrypt.MinCost).Generate([]byte("password"))
+	strong, _ := p.Generate([]byte("password"))
+	if !p.NeedsRehash(weak) || p.NeedsRehash(strong) || !p.NeedsRehash([]byte("$argon2id$")) {
+		t.Errorf("NeedsRehash() got unexpected result")
+	}
+}
+
+func TestHashPasswordProviderPepper(t *testing.T) {
+	password, salt := []byte("password"), []byte("salt")
+	plain := NewSha1PasswordProvider()
+	v1 := NewSha1PasswordProvider(Pepper{ID: "v1", Key: []byte("secret-v1")})
+	v2Peppers := []Pepper{{ID: "v2", Key: []byte("secret-v2")}, {ID: "v1", Key: []byte("secret-v1")}}
+	v2 := NewSha1PasswordProvider(v2Peppers...)
+
+	plainHash, _ := plain.Generate(password, salt)
+	v1Hash, _ := v1.Generate(password, salt)
+	v2Hash, _ := v2.Generate(password, salt)
+	if !bytes.HasPrefix(v1Hash, []byte("$v1$")) || !bytes.HasPrefix(v2Hash, []byte("$v2$")) {
+		t.Fatalf("Generate() want key id prefix got %q and %q", v1Hash, v2Hash)
+	}
+	for _, data := range []struct {
+		name     string
+		provider *hashPasswordProvider
+		hashed   []byte
+		err      bool
+		rehash   bool
+	}{
+		{"plain by plain", plain, plainHash, false, false},
+		{"v1 by plain", plain, v1Hash, true, true},
+		{"v1 by v1", v1, v1Hash, false, false},
+		{"v2 by v1", v1, v2Hash, true, true},
+		{"plain by v2", v2, plainHash, true, true},
+		{"plain by v2 migration", NewSha1PasswordProvider(v2Peppers...).AcceptUnpeppered(), plainHash, false, true},
+		{"v1 by v2", v2, v1Hash, false, true},
+		{"v2 by v2", v2, v2Hash, false, false},
+	} {
+		if err := data.provider.Compare(data.hashed, password, salt); (err != nil) != data.err {
+			t.Errorf("%s: Compare() want error %t got %v", data.name, data.err, err)
+		}
+		if err := data.provider.Compare(data.hashed, []byte("wrong"), salt); err == nil {
+			t.Errorf("%s: Compare() with wrong password want error got nil", data.name)
+		}
+		if res := data.provider.NeedsRehash(data.hashed); res != data.rehash {
+			t.Errorf("%s: NeedsRehash() want %t got %t", data.name, data.rehash, res)
+		}
+	}
+	if err := v1.Compare(v2Hash, password, salt); !errors.Is(err, ErrUnknownPepper) {
+		t.Errorf("Compare() want ErrUnknownPepper got %v", err)
+	}
+	if err := v2.Compare(v2Hash, []byte("wrong"), salt); !errors.Is(err, ErrMismatchedPassword) {
+		t.Errorf("Compare() want ErrMismatchedPassword got %v", err)
+	}
+	if err := v2.Compare(plainHash, password, salt); !errors.Is(err, ErrMismatchedPassword) {
+		t.Errorf("Compare() of unpeppered hash want ErrMismatchedPassword got %v", err)
+	}
+	v2Peppers[0].ID, v2Peppers[0].Key[0] = "v3", 'x'
+	if err := v2.Compare(v2Hash, password, salt); err != nil {
+		t.Errorf("Compare() after peppers modified by caller want nil got %v", err)
+	}
+	if hashed, _ := v2.Generate(password, salt); !bytes.Equal(hashed, v2Hash) {
+		t.Errorf("Generate() after peppers modified by caller want %q got %q", v2Hash, hashed)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrMismatchedPassword returned when a password not match the hashed password
+	ErrMismatchedPassword = errors.New("auth: hashed password is not the hash of the given password")
+
+	// ErrInvalidHash returned when the hashed password is not a valid PHC string
+	ErrInvalidHash = errors.New("auth: hashed password is not in the correct format")
+
+	// ErrIncompatibleVersion returned when the hashed password use an unsupported version
+	ErrIncompatibleVersion = errors.New("auth: incompatible version of hashing algorithm")
+)
+
+// phcEncoding base64 encoding used by PHC string format, no padding
+var phcEncoding = base64.RawStdEncoding
+
+// phcParam a name=value parameter of PHC string
+type phcParam struct {
+	name  string
+	value string
+}
+
+// phcHash PHC string format like `$<id>[$v=<version>][$<param>=<value>(,<param>=<value>)*][$<salt>[$<hash>]]`
+type phcHash struct {
+	id      string
+	version int
+	params  []phcParam
+	salt    []byte
+	hash    []byte
+}
+
+// String encode phcHash to PHC string format
+func (h *phcHash) String() string {
+	var b strings.Builder
+	b.WriteByte('$')
+	b.WriteString(h.id)
+	if h.version > 0 {
+		b.WriteString("$v=")
+		b.WriteString(strconv.Itoa(h.version))
+	}
+	if len(h.params) > 0 {
+		b.WriteByte('$')
+		for i, p := range h.params {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(p.name)
+			b.WriteByte('=')
+			b.WriteString(p.value)
+		}
+	}
+	b.WriteByte('$')
+	b.WriteString(phcEncoding.EncodeToString(h.salt))
+	b.WriteByte('$')
+	b.WriteString(phcEncoding.EncodeToString(h.hash))
+	return b.String()
+}
+
+// param get parameter value by name
+func (h *phcHash) param(name string) (string, bool) {
+	for _, p := range h.params {
+		if p.name == name {
+			return p.value, true
+		}
+	}
+	return "", false
+}
+
+// uintParam get parameter value by name and parse it to uint
+func (h *phcHash) uintParam(name string, bitSize int) (uint64, error) {
+	v, exist := h.param(name)
+	if !exist {
+		return 0, ErrInvalidHash
+	}
+	res, err := strconv.ParseUint(v, 10, bitSize)
+	if err != nil {
+		return 0, ErrInvalidHash
+	}
+	return res, nil
+}
+
+// phcID get the algorithm id of a PHC string, return empty string if not a PHC string
+func phcID(hashedPassword []byte) string {
+	if len(hashedPassword) < 2 || hashedPassword[0] != '$' {
+		return ""
+	}
+	s := string(hashedPassword[1:])
+	if idx := strings.IndexByte(s, '$'); idx >= 0 {
+		s = s[:idx]
+	}
+	return s
+}
+
+// parsePHC decode PHC string that must contain salt and hash.
+func parsePHC(hashedPassword []byte) (*phcHash, error) {
+	fields := strings.Split(string(hashedPassword), "$")
+	// fields[0] is empty for the leading '$'
+	if len(fields) < 4 || len(fields[0]) != 0 || len(fields[1]) == 0 {
+		return nil, ErrInvalidHash
+	}
+	h := &phcHash{id: fields[1]}
+	fields = fields[2:]
+	if v, found := strings.CutPrefix(fields[0], "v="); found {
+		version, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, ErrInvalidHash
+		}
+		h.version, fields = version, fields[1:]
+	}
+	if len(fields) == 3 {
+		for _, item := range strings.Split(fields[0], ",") {
+			name, value, found := strings.Cut(item, "=")
+			if !found || len(name) == 0 {
+				return nil, ErrInvalidHash
+			}
+			h.params = append(h.params, phcParam{name: name, value: value})
+		}
+		fields = fields[1:]
+	}
+	if len(fields) != 2 {
+		return nil, ErrInvalidHash
+	}
+	var err error
+	if h.salt, err = phcEncoding.DecodeString(fields[0]); err != nil {
+		return nil, ErrInvalidHash
+	}
+	if h.hash, err = phcEncoding.DecodeString(fields[1]); err != nil || len(h.hash) == 0 {
+		return nil, ErrInvalidHash
+	}
+	return h, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package token
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// Audience the `aud` claim, encoded as a string if only one audience
+type Audience []string
+
+// MarshalJSON encode single audience as string and multiple as array
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON decode audience from string or array of string
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*a = Audience{s}
+		return nil
+	}
+	var res []string
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+	*a = res
+	return nil
+}
+
+// Contains check whether aud is in audience
+func (a Audience) Contains(aud string) bool {
+	return slices.Contains(a, aud)
+}
+
+// RegisteredClaims the registered claims of RFC 7519, times are unix seconds
+type RegisteredClaims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+}
+
+// Claims registered claims with typed custom claims, custom claims are flatten into
+// the payload object beside registered claims, so T should be encoded as an object.
+type Claims[T any] struct {
+	RegisteredClaims
+	Custom T
+}
+
+// MarshalJSON encode registered claims and custom claims as one object, registered
+// claims win if custom claims use the same name.
+func (c Claims[T]) MarshalJSON() ([]byte, error) {
+	registered, err := json.Marshal(c.RegisteredClaims)
+	if err != nil {
+		return nil, err
+	}
+	custom, err := json.Marshal(c.Custom)
+	if err != nil {
+		return nil, err
+	}
+	custom = bytes.TrimSpace(custom)
+	if bytes.Equal(custom, []byte("null")) || bytes.Equal(custom, []byte("{}")) {
+		return registered, nil
+	}
+	if len(custom) < 2 || custom[0] != '{' {
+		return nil, ErrCustomClaims
+	}
+	if bytes.Equal(registered, []byte("{}")) {
+		return custom, nil
+	}
+	res := make([]byte, 0, len(custom)+len(registered))
+	res = append(res, custom[:len(custom)-1]...)
+	res = append(res, ',')
+	res = append(res, registered[1:]...)
+	return res, nil
+}
+
+// UnmarshalJSON decode registered claims and custom claims from one object
+func (c *Claims[T]) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &c.RegisteredClaims); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &c.Custom)
+}
+
+// SetLifetime set `iat` and `nbf` to now and `exp` to now+ttl
+func (c *RegisteredClaims) SetLifetime(now time.Time, ttl time.Duration) {
+	c.IssuedAt = now.Unix()
+	c.NotBefore = c.IssuedAt
+	c.ExpiresAt = now.Add(ttl).Unix()
+}
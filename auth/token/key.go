@@ -0,0 +1,244 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package token
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"math/big"
+	"sync"
+)
+
+// Algorithm signing algorithm of token, used as `alg` of header
+type Algorithm string
+
+const (
+	HS256 Algorithm = "HS256"
+	EdDSA Algorithm = "EdDSA"
+	ES256 Algorithm = "ES256"
+)
+
+const (
+	_es256KeySize = 32
+
+	// _maxSignatureSize max size of signature of all supported algorithms
+	_maxSignatureSize = 64
+)
+
+// Key signing and verifying key identified by ID, used as `kid` of header.
+// A key created by public key only can verify but not sign.
+type Key struct {
+	ID  string
+	Alg Algorithm
+
+	secret    []byte
+	edPrivate ed25519.PrivateKey
+	edPublic  ed25519.PublicKey
+	ecPrivate *ecdsa.PrivateKey
+	ecPublic  *ecdsa.PublicKey
+}
+
+// CanSign return whether the key can sign token
+func (k *Key) CanSign() bool {
+	switch k.Alg {
+	case HS256:
+		return len(k.secret) > 0
+	case EdDSA:
+		return k.edPrivate != nil
+	case ES256:
+		return k.ecPrivate != nil
+	}
+	return false
+}
+
+func (k *Key) sign(data []byte) ([]byte, error) {
+	if !k.CanSign() {
+		return nil, ErrKeyCannotSign
+	}
+	switch k.Alg {
+	case HS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(data)
+		return mac.Sum(nil), nil
+	case EdDSA:
+		return ed25519.Sign(k.edPrivate, data), nil
+	default:
+		digest := sha256.Sum256(data)
+		r, s, err := ecdsa.Sign(rand.Reader, k.ecPrivate, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		// JWS use fixed size big-endian R || S rather than ASN.1 DER
+		sig := make([]byte, 2*_es256KeySize)
+		r.FillBytes(sig[:_es256KeySize])
+		s.FillBytes(sig[_es256KeySize:])
+		return sig, nil
+	}
+}
+
+func (k *Key) verify(data []byte, sig []byte) bool {
+	switch k.Alg {
+	case HS256:
+		if len(k.secret) == 0 {
+			return false
+		}
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(data)
+		return subtle.ConstantTimeCompare(mac.Sum(nil), sig) == 1
+	case EdDSA:
+		return len(sig) == ed25519.SignatureSize && ed25519.Verify(k.edPublic, data, sig)
+	case ES256:
+		if len(sig) != 2*_es256KeySize {
+			return false
+		}
+		digest := sha256.Sum256(data)
+		r := new(big.Int).SetBytes(sig[:_es256KeySize])
+		s := new(big.Int).SetBytes(sig[_es256KeySize:])
+		return ecdsa.Verify(k.ecPublic, digest[:], r, s)
+	}
+	return false
+}
+
+// NewHS256Key create a HMAC-SHA256 key
+func NewHS256Key(kid string, secret []byte) *Key {
+	return &Key{
+		ID:     kid,
+		Alg:    HS256,
+		secret: secret,
+	}
+}
+
+// NewEdDSAKey create an Ed25519 key that can sign and verify
+func NewEdDSAKey(kid string, privateKey ed25519.PrivateKey) *Key {
+	return &Key{
+		ID:        kid,
+		Alg:       EdDSA,
+		edPrivate: privateKey,
+		edPublic:  privateKey.Public().(ed25519.PublicKey),
+	}
+}
+
+// NewEdDSAPublicKey create an Ed25519 key that can only verify
+func NewEdDSAPublicKey(kid string, publicKey ed25519.PublicKey) *Key {
+	return &Key{
+		ID:       kid,
+		Alg:      EdDSA,
+		edPublic: publicKey,
+	}
+}
+
+// NewES256Key create an ECDSA P-256 key that can sign and verify, panic if the
+// curve of privateKey is not P-256.
+func NewES256Key(kid string, privateKey *ecdsa.PrivateKey) *Key {
+	if privateKey.Curve != elliptic.P256() {
+		panic("token: ES256 key must use P-256 curve")
+	}
+	return &Key{
+		ID:        kid,
+		Alg:       ES256,
+		ecPrivate: privateKey,
+		ecPublic:  &privateKey.PublicKey,
+	}
+}
+
+// NewES256PublicKey create an ECDSA P-256 key that can only verify, panic if the
+// curve of publicKey is not P-256.
+func NewES256PublicKey(kid string, publicKey *ecdsa.PublicKey) *Key {
+	if publicKey.Curve != elliptic.P256() {
+		panic("token: ES256 key must use P-256 curve")
+	}
+	return &Key{
+		ID:       kid,
+		Alg:      ES256,
+		ecPublic: publicKey,
+	}
+}
+
+// KeySet keys indexed by kid for rotation, the current key is used to sign and
+// all keys are used to verify. It's safe for concurrent use.
+type KeySet struct {
+	mu      sync.RWMutex
+	keys    map[string]*Key
+	current *Key
+}
+
+// Add add or replace key by its ID, the key will be current key if no current key
+func (s *KeySet) Add(key *Key) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[key.ID] = key
+	if s.current == nil || s.current.ID == key.ID {
+		if key.CanSign() {
+			s.current = key
+		} else {
+			s.current = nil
+		}
+	}
+}
+
+// Remove remove key by kid, the current key can not be removed
+func (s *KeySet) Remove(kid string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exist := s.keys[kid]; !exist || (s.current != nil && s.current.ID == kid) {
+		return false
+	}
+	delete(s.keys, kid)
+	return true
+}
+
+// Use set the key of kid as current signing key
+func (s *KeySet) Use(kid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, exist := s.keys[kid]
+	if !exist {
+		return ErrUnknownKey
+	}
+	if !key.CanSign() {
+		return ErrKeyCannotSign
+	}
+	s.current = key
+	return nil
+}
+
+// Get get key by kid
+func (s *KeySet) Get(kid string) (*Key, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, exist := s.keys[kid]
+	return key, exist
+}
+
+// Current get current signing key, nil if not exist
+func (s *KeySet) Current() *Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.current
+}
+
+// NewKeySet create a KeySet, the first key that can sign is used as current key
+func NewKeySet(keys ...*Key) *KeySet {
+	s := &KeySet{
+		keys: make(map[string]*Key, len(keys)),
+	}
+	for _, key := range keys {
+		s.keys[key.ID] = key
+		if s.current == nil && key.CanSign() {
+			s.current = key
+		}
+	}
+	return s
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+// Package token issue and verify signed compact JWT (RFC 7519) with HS256, EdDSA
+// and ES256 algorithms. Headers and claims are encoded by encoding/json instead of
+// the json facade module, custom claims could implement json.Marshaler to use
+// another codec.
+package token
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMalformed       = errors.New("token: malformed token")
+	ErrUnknownKey      = errors.New("token: unknown key id")
+	ErrKeyCannotSign   = errors.New("token: key can not sign")
+	ErrAlgorithm       = errors.New("token: algorithm mismatch with key")
+	ErrSignature       = errors.New("token: signature is invalid")
+	ErrExpired         = errors.New("token: token is expired")
+	ErrNotValidYet     = errors.New("token: token is not valid yet")
+	ErrIssuedInFuture  = errors.New("token: token used before issued")
+	ErrInvalidIssuer   = errors.New("token: token has invalid issuer")
+	ErrInvalidAudience = errors.New("token: token has invalid audience")
+	ErrMissingExpires  = errors.New("token: token has no expiration")
+	ErrCustomClaims    = errors.New("token: custom claims must be encoded as an object")
+)
+
+var _encoding = base64.RawURLEncoding
+
+// Header the JOSE header of token
+type Header struct {
+	Alg Algorithm `json:"alg"`
+	Typ string    `json:"typ,omitempty"`
+	Kid string    `json:"kid,omitempty"`
+}
+
+// Validator validate the registered claims after signature verified. The zero
+// value check `exp`, `nbf` and `iat` without leeway.
+type Validator struct {
+	// Leeway allow clock skew between issuer and verifier
+	Leeway time.Duration
+	// Issuer expected `iss` if not empty
+	Issuer string
+	// Audience expected value contained in `aud` if not empty
+	Audience string
+	// RequireExpires reject token without `exp`
+	RequireExpires bool
+	// Now return current time, use time.Now if nil
+	Now func() time.Time
+}
+
+// Validate validate registered claims
+func (v *Validator) Validate(c *RegisteredClaims) error {
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	leeway := int64(v.Leeway / time.Second)
+	ts := now.Unix()
+	if c.ExpiresAt != 0 && ts > c.ExpiresAt+leeway {
+		return ErrExpired
+	} else if c.ExpiresAt == 0 && v.RequireExpires {
+		return ErrMissingExpires
+	}
+	if c.NotBefore != 0 && ts < c.NotBefore-leeway {
+		return ErrNotValidYet
+	}
+	if c.IssuedAt != 0 && ts < c.IssuedAt-leeway {
+		return ErrIssuedInFuture
+	}
+	if len(v.Issuer) > 0 && c.Issuer != v.Issuer {
+		return ErrInvalidIssuer
+	}
+	if len(v.Audience) > 0 && !c.Audience.Contains(v.Audience) {
+		return ErrInvalidAudience
+	}
+	return nil
+}
+
+// Issue sign claims with the current key of ks and return a compact JWT
+func Issue[T any](ks *KeySet, claims *Claims[T]) (string, error) {
+	key := ks.Current()
+	if key == nil {
+		return "", ErrKeyCannotSign
+	}
+	header, err := json.Marshal(&Header{Alg: key.Alg, Typ: "JWT", Kid: key.ID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	hsize, psize := _encoding.EncodedLen(len(header)), _encoding.EncodedLen(len(payload))
+	buf := make([]byte, hsize+psize+1, hsize+psize+_encoding.EncodedLen(_maxSignatureSize)+2)
+	_encoding.Encode(buf, header)
+	buf[hsize] = '.'
+	_encoding.Encode(buf[hsize+1:], payload)
+	sig, err := key.sign(buf)
+	if err != nil {
+		return "", err
+	}
+	buf = append(buf, '.')
+	buf = _encoding.AppendEncode(buf, sig)
+	return string(buf), nil
+}
+
+// Verify verify the signature of token by the key of `kid` in ks, then decode
+// claims and validate registered claims by v. Use zero Validator if v is nil.
+func Verify[T any](ks *KeySet, token string, v *Validator) (*Claims[T], error) {
+	header, payload, err := verify(ks, token)
+	if err != nil {
+		return nil, err
+	}
+	if header.Typ != "" && !strings.EqualFold(header.Typ, "JWT") {
+		return nil, ErrMalformed
+	}
+	claims := &Claims[T]{}
+	if err = json.Unmarshal(payload, claims); err != nil {
+		return nil, errors.Join(ErrMalformed, err)
+	}
+	if v == nil {
+		v = &Validator{}
+	}
+	if err = v.Validate(&claims.RegisteredClaims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// ParseUnverified decode header and claims without verify signature, only used
+// to inspect a token whose signature will be verified in other way.
+func ParseUnverified[T any](token string) (*Header, *Claims[T], error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, ErrMalformed
+	}
+	header := &Header{}
+	if err := decodeSegment(parts[0], header); err != nil {
+		return nil, nil, err
+	}
+	claims := &Claims[T]{}
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return nil, nil, err
+	}
+	return header, claims, nil
+}
+
+func verify(ks *KeySet, token string) (*Header, []byte, error) {
+	idx := strings.LastIndexByte(token, '.')
+	if idx < 0 {
+		return nil, nil, ErrMalformed
+	}
+	signed, encodedSig := token[:idx], token[idx+1:]
+	encodedHeader, encodedPayload, found := strings.Cut(signed, ".")
+	if !found || strings.IndexByte(encodedPayload, '.') >= 0 {
+		return nil, nil, ErrMalformed
+	}
+	header := &Header{}
+	if err := decodeSegment(encodedHeader, header); err != nil {
+		return nil, nil, err
+	}
+	var key *Key
+	if len(header.Kid) > 0 {
+		var exist bool
+		if key, exist = ks.Get(header.Kid); !exist {
+			return nil, nil, ErrUnknownKey
+		}
+	} else if key = ks.Current(); key == nil {
+		return nil, nil, ErrUnknownKey
+	}
+	// never trust the alg of header, it must be same as the key's
+	if header.Alg != key.Alg {
+		return nil, nil, ErrAlgorithm
+	}
+	sig, err := _encoding.DecodeString(encodedSig)
+	if err != nil {
+		return nil, nil, ErrMalformed
+	}
+	if !key.verify([]byte(signed), sig) {
+		return nil, nil, ErrSignature
+	}
+	payload, err := _encoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, nil, ErrMalformed
+	}
+	return header, bytes.TrimSpace(payload), nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := _encoding.DecodeString(segment)
+	if err != nil {
+		return ErrMalformed
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return errors.Join(ErrMalformed, err)
+	}
+	return nil
+}
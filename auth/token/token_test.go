@@ -0,0 +1,160 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package token
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type userClaims struct {
+	Role  string   `json:"role"`
+	Scope []string `json:"scope,omitempty"`
+}
+
+func TestIssueVerify(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	now := time.Unix(1700000000, 0)
+	for _, key := range []*Key{
+		NewHS256Key("hs", []byte("secret")),
+		NewEdDSAKey("ed", edKey),
+		NewES256Key("es", ecKey),
+	} {
+		ks := NewKeySet(key)
+		claims := &Claims[userClaims]{
+			RegisteredClaims: RegisteredClaims{
+				Issuer:   "tryst",
+				Subject:  "alimy",
+				Audience: Audience{"api"},
+			},
+			Custom: userClaims{Role: "admin", Scope: []string{"read", "write"}},
+		}
+		claims.SetLifetime(now, time.Hour)
+		token, err := Issue(ks, claims)
+		if err != nil {
+			t.Fatalf("%s: Issue() error: %s", key.Alg, err)
+		}
+		v := &Validator{Issuer: "tryst", Audience: "api", Now: func() time.Time { return now }}
+		res, err := Verify[userClaims](ks, token, v)
+		if err != nil {
+			t.Fatalf("%s: Verify() error: %s", key.Alg, err)
+		}
+		if res.Subject != "alimy" || res.Custom.Role != "admin" || len(res.Custom.Scope) != 2 {
+			t.Errorf("%s: Verify() got unexpected claims: %+v", key.Alg, res)
+		}
+		header, _, err := ParseUnverified[userClaims](token)
+		if err != nil || header.Alg != key.Alg || header.Kid != key.ID {
+			t.Errorf("%s: ParseUnverified() got header: %+v err: %v", key.Alg, header, err)
+		}
+		tampered := token[:len(token)-4] + "AAAA"
+		if _, err = Verify[userClaims](ks, tampered, v); !errors.Is(err, ErrSignature) && !errors.Is(err, ErrMalformed) {
+			t.Errorf("%s: Verify(tampered) want ErrSignature got %v", key.Alg, err)
+		}
+	}
+}
+
+func TestValidator(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	c := &RegisteredClaims{Issuer: "tryst", Audience: Audience{"api", "web"}}
+	c.SetLifetime(now, time.Minute)
+	for _, data := range []struct {
+		name string
+		v    Validator
+		err  error
+	}{
+		{"valid", Validator{Issuer: "tryst", Audience: "web"}, nil},
+		{"expired", Validator{Now: func() time.Time { return now.Add(2 * time.Minute) }}, ErrExpired},
+		{"leeway", Validator{Leeway: 2 * time.Minute, Now: func() time.Time { return now.Add(2 * time.Minute) }}, nil},
+		{"not before", Validator{Now: func() time.Time { return now.Add(-time.Second) }}, ErrNotValidYet},
+		{"issuer", Validator{Issuer: "other"}, ErrInvalidIssuer},
+		{"audience", Validator{Audience: "other"}, ErrInvalidAudience},
+	} {
+		if data.v.Now == nil {
+			data.v.Now = func() time.Time { return now }
+		}
+		if err := data.v.Validate(c); !errors.Is(err, data.err) {
+			t.Errorf("%s: Validate() want %v got %v", data.name, data.err, err)
+		}
+	}
+	if err := (&Validator{RequireExpires: true}).Validate(&RegisteredClaims{}); !errors.Is(err, ErrMissingExpires) {
+		t.Errorf("Validate() want ErrMissingExpires got %v", err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey, newKey := NewHS256Key("v1", []byte("secret-v1")), NewHS256Key("v2", []byte("secret-v2"))
+	ks := NewKeySet(oldKey)
+	oldToken, _ := Issue(ks, &Claims[struct{}]{})
+
+	ks.Add(newKey)
+	if err := ks.Use("v2"); err != nil {
+		t.Fatalf("Use(v2) error: %s", err)
+	}
+	newToken, _ := Issue(ks, &Claims[struct{}]{})
+	if !strings.Contains(mustHeader(t, newToken), `"kid":"v2"`) {
+		t.Errorf("Issue() want kid v2 in header")
+	}
+	for _, token := range []string{oldToken, newToken} {
+		if _, err := Verify[struct{}](ks, token, nil); err != nil {
+			t.Errorf("Verify() error: %s", err)
+		}
+	}
+	if ks.Remove("v2") || !ks.Remove("v1") {
+		t.Errorf("Remove() want current key kept and old key removed")
+	}
+	if _, err := Verify[struct{}](ks, oldToken, nil); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Verify() want ErrUnknownKey got %v", err)
+	}
+}
+
+func TestAlgorithmConfusion(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	hs := NewKeySet(NewHS256Key("k", []byte("secret")))
+	ed := NewKeySet(NewEdDSAKey("k", edKey))
+	token, _ := Issue(hs, &Claims[struct{}]{})
+	if _, err := Verify[struct{}](ed, token, nil); !errors.Is(err, ErrAlgorithm) {
+		t.Errorf("Verify() want ErrAlgorithm got %v", err)
+	}
+	if _, err := Issue(NewKeySet(NewEdDSAPublicKey("k", edKey.Public().(ed25519.PublicKey))), &Claims[struct{}]{}); !errors.Is(err, ErrKeyCannotSign) {
+		t.Errorf("Issue() want ErrKeyCannotSign got %v", err)
+	}
+}
+
+func TestClaimsJSON(t *testing.T) {
+	c := Claims[map[string]any]{
+		RegisteredClaims: RegisteredClaims{Subject: "alimy", Audience: Audience{"api"}},
+		Custom:           map[string]any{"sub": "fake", "role": "admin"},
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Marshal() error: %s", err)
+	}
+	res := Claims[map[string]any]{}
+	if err = json.Unmarshal(data, &res); err != nil {
+		t.Fatalf("Unmarshal() error: %s", err)
+	}
+	if res.Subject != "alimy" || res.Audience[0] != "api" || res.Custom["role"] != "admin" {
+		t.Errorf("Unmarshal(%s) got unexpected claims: %+v", data, res)
+	}
+	if _, err = json.Marshal(Claims[int]{Custom: 1}); !errors.Is(err, ErrCustomClaims) {
+		t.Errorf("Marshal() want ErrCustomClaims got %v", err)
+	}
+}
+
+func mustHeader(t *testing.T, token string) string {
+	data, err := _encoding.DecodeString(token[:strings.IndexByte(token, '.')])
+	if err != nil {
+		t.Fatalf("decode header error: %s", err)
+	}
+	return string(data)
+}
@@ -15,65 +15,144 @@
 package asynccache
 
 import (
-	"fmt"
+	"context"
+	"io"
 	"log"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	sf "golang.org/x/sync/singleflight"
+	"github.com/alimy/tryst/cache"
+	"github.com/alimy/tryst/internal/singleflight"
+	"github.com/alimy/tryst/lang/fastrand"
 )
 
 // Options controls the behavior of AsyncCache.
-type Options struct {
+type Options[K comparable, V any] struct {
+	// RefreshDuration is the default time-to-live of a value before it is
+	// refreshed, and the period of the shared refresh ticker. Keys are
+	// refreshed at the tick closest to their own deadline.
 	RefreshDuration time.Duration
-	Fetcher         func(key string) (any, error)
+	// RefreshJitter adds a random duration in [0, RefreshJitter) to the
+	// deadline of each key, so that keys are not refreshed all at once.
+	RefreshJitter time.Duration
+	// StaleWhileRevalidate serves the stale value of a key whose deadline has
+	// passed and refreshes it in the background on access.
+	StaleWhileRevalidate bool
+	// MaxConcurrentRefresh limits the concurrent refresh fetches of the
+	// cache, it is 1 if not set.
+	MaxConcurrentRefresh int
+
+	// Fetcher fetch the value of key, the ctx is not canceled when the caller
+	// of Get gives up waiting, so the fetching continues for other waiters.
+	Fetcher func(ctx context.Context, key K) (V, error)
+	// FetcherWithTTL is used instead of Fetcher if it is set. A positive ttl
+	// overrides RefreshDuration for the fetched value.
+	FetcherWithTTL func(ctx context.Context, key K) (val V, ttl time.Duration, err error)
 
 	// If EnableExpire is true, ExpireDuration MUST be set.
 	EnableExpire   bool
 	ExpireDuration time.Duration
 
-	ErrorHandler  func(key string, err error)
-	ChangeHandler func(key string, oldData, newData any)
-	DeleteHandler func(key string, oldData any)
+	ErrorHandler  func(key K, err error)
+	ChangeHandler func(key K, oldData, newData V)
+	DeleteHandler func(key K, oldData V)
 
-	IsSame     func(key string, oldData, newData any) bool
+	IsSame     func(key K, oldData, newData V) bool
 	ErrLogFunc func(str string)
+
+	// MaxEntries and MaxCost bound the cache, zero means no limit. Entries
+	// chosen by EvictionPolicy are evicted through DeleteHandler when the
+	// cache is full. Every hit of a bounded cache is recorded under a mutex
+	// shared by all keys.
+	MaxEntries     int
+	MaxCost        int64
+	EvictionPolicy EvictionPolicy
+	// Cost returns the cost of an entry, every entry costs 1 if it is nil.
+	Cost func(key K, val V) int64
+
+	// MetricsHook is called on cache events besides the counting of Stats.
+	MetricsHook cache.MetricsHook
+
+	// Codec encodes and decodes snapshot, encoding/json is used if it is nil.
+	Codec Codec
 }
 
+var (
+	_ cache.StatsProvider = AsyncCache[int, int](nil)
+	_ Store[int, int]     = (*asyncCache[int, int])(nil)
+)
+
 // AsyncCache .
-type AsyncCache interface {
+type AsyncCache[K comparable, V any] interface {
 	// SetDefault sets the default value of given key if it is new to the cache.
 	// It is useful for cache warming up.
-	// Param val should not be nil.
-	SetDefault(key string, val any) (exist bool)
+	SetDefault(key K, val V) (exist bool)
 
 	// Get tries to fetch a value corresponding to the given key from the cache.
 	// If error occurs during the first time fetching, it will be cached until the
 	// sequential fetching triggered by the refresh goroutine succeed.
-	Get(key string) (val any, err error)
+	// It returns ctx.Err() if ctx is done before the first time fetching completed,
+	// but the fetching continues for other waiters.
+	Get(ctx context.Context, key K) (val V, err error)
 
 	// GetOrSet tries to fetch a value corresponding to the given key from the cache.
 	// If the key is not yet cached or error occurs, the default value will be set.
-	GetOrSet(key string, defaultVal any) (val any)
+	// The default value is returned but not set if ctx is done before fetching completed.
+	GetOrSet(ctx context.Context, key K, defaultVal V) (val V)
 
 	// Dump dumps all cache entries.
 	// This will not cause expire to refresh.
-	Dump() map[string]any
+	Dump() map[K]V
 
 	// DeleteIf deletes cached entries that match the `shouldDelete` predicate.
-	DeleteIf(shouldDelete func(key string) bool)
+	DeleteIf(shouldDelete func(key K) bool)
+
+	// Stats returns a snapshot of the cache statistics.
+	Stats() cache.Stats
+
+	// Snapshot writes all cached entries without error to w.
+	Snapshot(w io.Writer) error
+
+	// Restore reads a snapshot from r and sets its entries that are new to
+	// the cache, with their ages and deadlines preserved.
+	// It is useful for cache warming up from disk.
+	Restore(r io.Reader) error
 
 	// Close closes the async cache.
 	// This should be called when the cache is no longer needed, or may lead to resource leak.
 	Close()
 }
 
+// Store reads and writes cached entries directly without fetching, e.g. to use
+// the cache as the L1 of a layered cache. AsyncCache instances created by
+// NewAsyncCache implement it.
+type Store[K comparable, V any] interface {
+	// Peek returns the cached value of key without fetching, ok is false if the
+	// key is not cached or the fetching of it failed.
+	Peek(key K) (val V, ok bool)
+
+	// Set sets the value of given key, the cached one is replaced.
+	Set(key K, val V)
+
+	// Delete deletes the cached entry of given key.
+	Delete(key K)
+}
+
 // asyncCache .
-type asyncCache struct {
-	sfg  sf.Group
-	opt  Options
+type asyncCache[K comparable, V any] struct {
+	sfg  singleflight.Group[K, V]
+	opt  Options[K, V]
 	data sync.Map
+
+	// mu guards evictor and keeps it consistent with data, evictor is
+	// nil if the cache is unbounded.
+	mu      sync.Mutex
+	evictor evictor[K]
+
+	// refreshSem limits the concurrent refresh fetches
+	refreshSem chan struct{}
+	stats      *cache.StatsRecorder
 }
 
 type tickerType int
@@ -83,53 +162,108 @@ const (
 	expireTicker
 )
 
+// tickable cache that driven by shared ticker
+type tickable interface {
+	expire()
+	refresh()
+}
+
 type sharedTicker struct {
 	sync.Mutex
 	started  bool
 	stopChan chan bool
 	ticker   *time.Ticker
-	caches   map[*asyncCache]struct{}
+	caches   map[tickable]struct{}
 }
 
 // 共用 ticker
 var refreshTickerMap, expireTickerMap sync.Map
 
-type entry struct {
-	val    atomic.Value
+type entry[V any] struct {
+	val    atomic.Pointer[V]
 	expire int32 // 0 means useful, 1 will expire
 	err    Error
+
+	updated    atomic.Int64 // unix nano when the value is stored
+	deadline   atomic.Int64 // unix nano after which the value is stale
+	refreshing atomic.Bool
 }
 
-func (e *entry) Store(x any, err error) {
-	if x != nil {
-		e.val.Store(x)
-	} else {
-		e.val = atomic.Value{}
-	}
+func (e *entry[V]) Store(x V, err error) {
+	e.val.Store(&x)
 	e.err.Store(err)
+	e.updated.Store(time.Now().UnixNano())
+}
+
+func (e *entry[V]) Load() (V, error) {
+	return *e.val.Load(), e.err.Load()
 }
 
-func (e *entry) Touch() {
+func (e *entry[V]) Touch() {
 	atomic.StoreInt32(&e.expire, 0)
 }
 
+func (c *asyncCache[K, V]) newEntry(x V, ttl time.Duration, err error) *entry[V] {
+	ety := &entry[V]{}
+	ety.Store(x, err)
+	ety.deadline.Store(c.deadline(ttl))
+	return ety
+}
+
+// deadline returns the jittered deadline of a value with ttl, RefreshDuration
+// is used if ttl is not positive.
+func (c *asyncCache[K, V]) deadline(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		ttl = c.opt.RefreshDuration
+	}
+	if c.opt.RefreshJitter > 0 {
+		ttl += time.Duration(fastrand.Int63n(int64(c.opt.RefreshJitter)))
+	}
+	return time.Now().Add(ttl).UnixNano()
+}
+
 // NewAsyncCache creates an AsyncCache.
-func NewAsyncCache(opt Options) AsyncCache {
-	c := &asyncCache{
-		sfg: sf.Group{},
-		opt: opt,
+func NewAsyncCache[K comparable, V any](opt Options[K, V]) AsyncCache[K, V] {
+	c := &asyncCache[K, V]{
+		opt:   opt,
+		stats: cache.NewStatsRecorder(opt.MetricsHook),
 	}
 	if c.opt.ErrLogFunc == nil {
 		c.opt.ErrLogFunc = func(str string) {
 			log.Println(str)
 		}
 	}
+	if c.opt.Codec == nil {
+		c.opt.Codec = stdCodec{}
+	}
+	if c.opt.FetcherWithTTL == nil {
+		fetcher := c.opt.Fetcher
+		c.opt.FetcherWithTTL = func(ctx context.Context, key K) (V, time.Duration, error) {
+			val, err := fetcher(ctx, key)
+			return val, 0, err
+		}
+	}
+	if c.opt.RefreshJitter < 0 || c.opt.MaxConcurrentRefresh < 0 {
+		panic("asynccache: invalid RefreshJitter or MaxConcurrentRefresh")
+	}
+	c.refreshSem = make(chan struct{}, max(c.opt.MaxConcurrentRefresh, 1))
+	if c.opt.MaxEntries < 0 || c.opt.MaxCost < 0 {
+		panic("asynccache: invalid MaxEntries or MaxCost")
+	}
+	if c.opt.MaxEntries > 0 || c.opt.MaxCost > 0 {
+		if c.opt.Cost == nil {
+			c.opt.Cost = func(K, V) int64 {
+				return 1
+			}
+		}
+		c.evictor = newEvictor[K](c.opt.EvictionPolicy, c.opt.MaxEntries, c.opt.MaxCost)
+	}
 	if c.opt.EnableExpire {
 		if c.opt.ExpireDuration == 0 {
 			panic("asynccache: invalid ExpireDuration")
 		}
 		ti, _ := expireTickerMap.LoadOrStore(c.opt.ExpireDuration,
-			&sharedTicker{caches: make(map[*asyncCache]struct{}), stopChan: make(chan bool, 1)})
+			&sharedTicker{caches: make(map[tickable]struct{}), stopChan: make(chan bool, 1)})
 		et := ti.(*sharedTicker)
 		et.Lock()
 		et.caches[c] = struct{}{}
@@ -142,7 +276,7 @@ func NewAsyncCache(opt Options) AsyncCache {
 	}
 
 	ti, _ := refreshTickerMap.LoadOrStore(c.opt.RefreshDuration,
-		&sharedTicker{caches: make(map[*asyncCache]struct{}), stopChan: make(chan bool, 1)})
+		&sharedTicker{caches: make(map[tickable]struct{}), stopChan: make(chan bool, 1)})
 	rt := ti.(*sharedTicker)
 	rt.Lock()
 	rt.caches[c] = struct{}{}
@@ -156,12 +290,17 @@ func NewAsyncCache(opt Options) AsyncCache {
 }
 
 // SetDefault sets the default value of given key if it is new to the cache.
-func (c *asyncCache) SetDefault(key string, val any) bool {
-	ety := &entry{}
-	ety.Store(val, nil)
-	actual, exist := c.data.LoadOrStore(key, ety)
+func (c *asyncCache[K, V]) SetDefault(key K, val V) bool {
+	if c.evictor != nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	}
+	actual, exist := c.data.LoadOrStore(key, c.newEntry(val, 0, nil))
 	if exist {
-		actual.(*entry).Touch()
+		actual.(*entry[V]).Touch()
+		c.access(key)
+	} else {
+		c.admit(key, val)
 	}
 	return exist
 }
@@ -169,85 +308,120 @@ func (c *asyncCache) SetDefault(key string, val any) bool {
 // Get tries to fetch a value corresponding to the given key from the cache.
 // If error occurs during in the first time fetching, it will be cached until the
 // sequential fetchings triggered by the refresh goroutine succeed.
-func (c *asyncCache) Get(key string) (val any, err error) {
-	var ok bool
-	val, ok = c.data.Load(key)
-	if ok {
-		e := val.(*entry)
+func (c *asyncCache[K, V]) Get(ctx context.Context, key K) (val V, err error) {
+	if v, ok := c.data.Load(key); ok {
+		e := v.(*entry[V])
 		e.Touch()
-		return e.val.Load(), e.err.Load()
+		c.stats.OnHit()
+		c.lockedAccess(key)
+		c.revalidate(ctx, key, e)
+		return e.Load()
 	}
 
-	val, err, _ = c.sfg.Do(key, func() (v any, e error) {
-		v, e = c.opt.Fetcher(key)
-		ety := &entry{}
-		ety.Store(v, e)
-		c.data.Store(key, ety)
-		return
+	c.stats.OnMiss()
+	return c.sfg.Do(ctx, key, func() (V, error) {
+		v, ttl, e := c.fetch(context.WithoutCancel(ctx), key)
+		c.store(key, c.newEntry(v, ttl, e))
+		return v, e
 	})
-	return
 }
 
 // GetOrSet tries to fetch a value corresponding to the given key from the cache.
 // If the key is not yet cached or fetching failed, the default value will be set.
-func (c *asyncCache) GetOrSet(key string, def any) (val any) {
+func (c *asyncCache[K, V]) GetOrSet(ctx context.Context, key K, def V) V {
 	if v, ok := c.data.Load(key); ok {
-		e := v.(*entry)
+		e := v.(*entry[V])
 		if e.err.Load() != nil {
-			ety := &entry{}
-			ety.Store(def, nil)
-			c.data.Store(key, ety)
+			c.stats.OnMiss()
+			c.store(key, c.newEntry(def, 0, nil))
 			return def
 		}
 		e.Touch()
-		return e.val.Load()
+		c.stats.OnHit()
+		c.lockedAccess(key)
+		c.revalidate(ctx, key, e)
+		return *e.val.Load()
 	}
 
-	val, _, _ = c.sfg.Do(key, func() (any, error) {
-		v, e := c.opt.Fetcher(key)
+	c.stats.OnMiss()
+	val, err := c.sfg.Do(ctx, key, func() (V, error) {
+		v, ttl, e := c.fetch(context.WithoutCancel(ctx), key)
 		if e != nil {
-			v = def
+			v, ttl = def, 0
 		}
-		ety := &entry{}
-		ety.Store(v, nil)
-		c.data.Store(key, ety)
+		c.store(key, c.newEntry(v, ttl, nil))
 		return v, nil
 	})
-	return
+	if err != nil {
+		return def
+	}
+	return val
+}
+
+// Peek returns the cached value of key without fetching.
+func (c *asyncCache[K, V]) Peek(key K) (val V, ok bool) {
+	v, exist := c.data.Load(key)
+	if !exist {
+		c.stats.OnMiss()
+		return val, false
+	}
+	e := v.(*entry[V])
+	if e.err.Load() != nil {
+		c.stats.OnMiss()
+		return val, false
+	}
+	e.Touch()
+	c.stats.OnHit()
+	c.lockedAccess(key)
+	c.revalidate(context.Background(), key, e)
+	return *e.val.Load(), true
+}
+
+// Set sets the value of given key, the cached one is replaced.
+func (c *asyncCache[K, V]) Set(key K, val V) {
+	c.store(key, c.newEntry(val, 0, nil))
+}
+
+// Delete deletes the cached entry of given key.
+func (c *asyncCache[K, V]) Delete(key K) {
+	if v, ok := c.data.Load(key); ok {
+		c.delete(key, v.(*entry[V]))
+	}
 }
 
 // Dump dumps all cached entries.
-func (c *asyncCache) Dump() map[string]any {
-	data := make(map[string]any)
+func (c *asyncCache[K, V]) Dump() map[K]V {
+	data := make(map[K]V)
 	c.data.Range(func(key, val any) bool {
-		k, ok := key.(string)
-		if !ok {
-			c.opt.ErrLogFunc(fmt.Sprintf("invalid key: %v, type: %T is not string", k, k))
-			c.data.Delete(key)
-			return true
-		}
-		data[k] = val.(*entry).val.Load()
+		data[key.(K)] = *val.(*entry[V]).val.Load()
 		return true
 	})
 	return data
 }
 
 // DeleteIf deletes cached entries that match the `shouldDelete` predicate.
-func (c *asyncCache) DeleteIf(shouldDelete func(key string) bool) {
+func (c *asyncCache[K, V]) DeleteIf(shouldDelete func(key K) bool) {
 	c.data.Range(func(key, value any) bool {
-		s := key.(string)
-		if shouldDelete(s) {
-			if c.opt.DeleteHandler != nil {
-				go c.opt.DeleteHandler(s, value)
-			}
-			c.data.Delete(key)
+		k := key.(K)
+		if shouldDelete(k) {
+			c.delete(k, value.(*entry[V]))
 		}
 		return true
 	})
 }
 
+// Stats returns a snapshot of the cache statistics.
+func (c *asyncCache[K, V]) Stats() cache.Stats {
+	entries := 0
+	c.data.Range(func(_, _ any) bool {
+		entries++
+		return true
+	})
+	return c.stats.Stats(entries)
+}
+
 // Close stops the background goroutine.
-func (c *asyncCache) Close() {
+func (c *asyncCache[K, V]) Close() {
 	// close refresh ticker
 	ti, _ := refreshTickerMap.Load(c.opt.RefreshDuration)
 	rt := ti.(*sharedTicker)
@@ -284,7 +458,7 @@ func (t *sharedTicker) tick(ticker *time.Ticker, tt tickerType) {
 			t.Lock()
 			for c := range t.caches {
 				wg.Add(1)
-				go func(c *asyncCache) {
+				go func(c tickable) {
 					defer wg.Done()
 					if tt == expireTicker {
 						c.expire()
@@ -303,64 +477,160 @@ func (t *sharedTicker) tick(ticker *time.Ticker, tt tickerType) {
 	}
 }
 
-func (c *asyncCache) expire() {
+func (c *asyncCache[K, V]) expire() {
 	c.data.Range(func(key, value any) bool {
-		k, ok := key.(string)
-		if !ok {
-			c.opt.ErrLogFunc(fmt.Sprintf("invalid key: %v, type: %T is not string", k, k))
-			c.data.Delete(key)
-			return true
-		}
-		e, ok := value.(*entry)
-		if !ok {
-			c.opt.ErrLogFunc(fmt.Sprintf("invalid key: %v, type: %T is not entry", k, value))
-			c.data.Delete(key)
-			return true
-		}
+		e := value.(*entry[V])
 		if !atomic.CompareAndSwapInt32(&e.expire, 0, 1) {
-			if c.opt.DeleteHandler != nil {
-				go c.opt.DeleteHandler(k, value)
-			}
-			c.data.Delete(key)
+			c.delete(key.(K), e)
 		}
-
 		return true
 	})
 }
 
-func (c *asyncCache) refresh() {
+// refresh refreshes the keys whose deadline is closer to this tick than to
+// the next one.
+func (c *asyncCache[K, V]) refresh() {
+	var wg sync.WaitGroup
+	ctx, due := context.Background(), time.Now().Add(c.opt.RefreshDuration/2).UnixNano()
 	c.data.Range(func(key, value any) bool {
-		k, ok := key.(string)
-		if !ok {
-			c.opt.ErrLogFunc(fmt.Sprintf("invalid key: %v, type: %T is not string", k, k))
-			c.data.Delete(key)
+		k, e := key.(K), value.(*entry[V])
+		if e.deadline.Load() >= due || !e.refreshing.CompareAndSwap(false, true) {
 			return true
 		}
-		e, ok := value.(*entry)
-		if !ok {
-			c.opt.ErrLogFunc(fmt.Sprintf("invalid key: %v, type: %T is not entry", k, value))
-			c.data.Delete(key)
-			return true
+		c.refreshSem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.refreshEntry(ctx, k, e)
+		}()
+		return true
+	})
+	wg.Wait()
+}
+
+// revalidate refreshes the stale entry e in the background if
+// StaleWhileRevalidate is enabled.
+func (c *asyncCache[K, V]) revalidate(ctx context.Context, key K, e *entry[V]) {
+	if !c.opt.StaleWhileRevalidate || e.deadline.Load() > time.Now().UnixNano() {
+		return
+	}
+	if e.refreshing.CompareAndSwap(false, true) {
+		go func() {
+			c.refreshSem <- struct{}{}
+			c.refreshEntry(context.WithoutCancel(ctx), key, e)
+		}()
+	}
+}
+
+// refreshEntry fetches the value of entry e, the caller must have marked e
+// as refreshing and acquired refreshSem.
+func (c *asyncCache[K, V]) refreshEntry(ctx context.Context, key K, e *entry[V]) {
+	defer func() {
+		<-c.refreshSem
+		e.refreshing.Store(false)
+	}()
+
+	newVal, ttl, err := c.fetch(ctx, key)
+	c.stats.OnRefresh()
+	if err != nil {
+		if c.opt.ErrorHandler != nil {
+			go c.opt.ErrorHandler(key, err)
+		}
+		if e.err.Load() != nil {
+			e.err.Store(err)
 		}
+		e.deadline.Store(c.deadline(0))
+		return
+	}
 
-		newVal, err := c.opt.Fetcher(k)
-		if err != nil {
-			if c.opt.ErrorHandler != nil {
-				go c.opt.ErrorHandler(k, err)
-			}
-			if e.err.Load() != nil {
-				e.err.Store(err)
-			}
-			return true
+	if c.opt.IsSame != nil && !c.opt.IsSame(key, *e.val.Load(), newVal) {
+		if c.opt.ChangeHandler != nil {
+			go c.opt.ChangeHandler(key, *e.val.Load(), newVal)
 		}
+	}
 
-		if c.opt.IsSame != nil && !c.opt.IsSame(k, e.val.Load(), newVal) {
-			if c.opt.ChangeHandler != nil {
-				go c.opt.ChangeHandler(k, e.val.Load(), newVal)
-			}
+	e.Store(newVal, err)
+	e.deadline.Store(c.deadline(ttl))
+	c.resize(key, e, newVal)
+}
+
+// fetch fetches the value of key and records the fetching.
+func (c *asyncCache[K, V]) fetch(ctx context.Context, key K) (V, time.Duration, error) {
+	start := time.Now()
+	val, ttl, err := c.opt.FetcherWithTTL(ctx, key)
+	c.stats.OnFetch(time.Since(start), err)
+	return val, ttl, err
+}
+
+// store stores the entry of key and evicts entries if the cache is full.
+func (c *asyncCache[K, V]) store(key K, e *entry[V]) {
+	if c.evictor == nil {
+		c.data.Store(key, e)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data.Store(key, e)
+	c.admit(key, *e.val.Load())
+}
+
+// admit records key in evictor and evicts the victims, c.mu must be held.
+func (c *asyncCache[K, V]) admit(key K, val V) {
+	if c.evictor == nil {
+		return
+	}
+	for _, victim := range c.evictor.add(key, c.opt.Cost(key, val)) {
+		v, ok := c.data.LoadAndDelete(victim)
+		if !ok {
+			continue
 		}
+		c.stats.OnEvict()
+		if c.opt.DeleteHandler != nil {
+			go c.opt.DeleteHandler(victim, *v.(*entry[V]).val.Load())
+		}
+	}
+}
 
-		e.Store(newVal, err)
-		return true
-	})
+// resize updates the cost of refreshed entry e if it is still cached.
+func (c *asyncCache[K, V]) resize(key K, e *entry[V], val V) {
+	if c.evictor == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.data.Load(key); ok && v.(*entry[V]) == e {
+		c.admit(key, val)
+	}
+}
+
+// access records a hit of key, c.mu must be held.
+func (c *asyncCache[K, V]) access(key K) {
+	if c.evictor != nil {
+		c.evictor.access(key)
+	}
+}
+
+func (c *asyncCache[K, V]) lockedAccess(key K) {
+	if c.evictor != nil {
+		c.mu.Lock()
+		c.evictor.access(key)
+		c.mu.Unlock()
+	}
+}
+
+// delete deletes entry e of key and fires DeleteHandler if it is still cached.
+func (c *asyncCache[K, V]) delete(key K, e *entry[V]) {
+	if c.evictor != nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	}
+	if !c.data.CompareAndDelete(key, e) {
+		return
+	}
+	if c.evictor != nil {
+		c.evictor.remove(key)
+	}
+	if c.opt.DeleteHandler != nil {
+		go c.opt.DeleteHandler(key, *e.val.Load())
+	}
 }
@@ -15,38 +15,44 @@
 package asynccache
 
 import (
+	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+var ctx = context.Background()
+
 func TestGetOK(t *testing.T) {
 	key, ret := "key", "ret"
-	op := Options{
+	op := Options[string, any]{
 		RefreshDuration: time.Second,
 		IsSame: func(key string, oldData, newData any) bool {
 			return false
 		},
-		Fetcher: func(key string) (any, error) {
+		Fetcher: func(ctx context.Context, key string) (any, error) {
 			return ret, nil
 		},
 	}
 	c := NewAsyncCache(op)
 
-	v, err := c.Get(key)
+	v, err := c.Get(ctx, key)
 	assert.NoError(t, err)
 	assert.Equal(t, v.(string), ret)
 
 	time.Sleep(time.Second / 2)
 	ret = "change"
-	v, err = c.Get(key)
+	v, err = c.Get(ctx, key)
 	assert.NoError(t, err)
 	assert.NotEqual(t, v.(string), ret)
 
 	time.Sleep(time.Second)
-	v, err = c.Get(key)
+	v, err = c.Get(ctx, key)
 	assert.NoError(t, err)
 	assert.Equal(t, v.(string), ret)
 }
@@ -54,12 +60,12 @@ func TestGetOK(t *testing.T) {
 func TestGetErr(t *testing.T) {
 	key, ret := "key", "ret"
 	first := true
-	op := Options{
+	op := Options[string, any]{
 		RefreshDuration: time.Second + 100*time.Millisecond,
 		IsSame: func(key string, oldData, newData any) bool {
 			return false
 		},
-		Fetcher: func(key string) (any, error) {
+		Fetcher: func(ctx context.Context, key string) (any, error) {
 			if first {
 				first = false
 				return nil, errors.New("error")
@@ -69,55 +75,55 @@ func TestGetErr(t *testing.T) {
 	}
 	c := NewAsyncCache(op)
 
-	v, err := c.Get(key)
+	v, err := c.Get(ctx, key)
 	assert.Error(t, err)
 	assert.Nil(t, v)
 
 	time.Sleep(time.Second / 2)
-	_, err2 := c.Get(key)
+	_, err2 := c.Get(ctx, key)
 	assert.Equal(t, err, err2)
 
 	time.Sleep(time.Second + 10*time.Millisecond)
-	v, err = c.Get(key)
+	v, err = c.Get(ctx, key)
 	assert.NoError(t, err)
 	assert.Equal(t, v.(string), ret)
 }
 
 func TestGetOrSetOK(t *testing.T) {
 	key, ret, def := "key", "ret", "def"
-	op := Options{
+	op := Options[string, any]{
 		RefreshDuration: time.Second,
 		IsSame: func(key string, oldData, newData any) bool {
 			return false
 		},
-		Fetcher: func(key string) (any, error) {
+		Fetcher: func(ctx context.Context, key string) (any, error) {
 			return ret, nil
 		},
 	}
 	c := NewAsyncCache(op)
 
-	v := c.GetOrSet(key, def)
+	v := c.GetOrSet(ctx, key, def)
 	assert.Equal(t, v.(string), ret)
 
 	time.Sleep(time.Second / 2)
 	ret = "change"
-	v = c.GetOrSet(key, def)
+	v = c.GetOrSet(ctx, key, def)
 	assert.NotEqual(t, v.(string), ret)
 
 	time.Sleep(time.Second)
-	v = c.GetOrSet(key, def)
+	v = c.GetOrSet(ctx, key, def)
 	assert.Equal(t, v.(string), ret)
 }
 
 func TestGetOrSetErr(t *testing.T) {
 	key, ret, def := "key", "ret", "def"
 	first := true
-	op := Options{
+	op := Options[string, any]{
 		RefreshDuration: time.Second + 500*time.Millisecond,
 		IsSame: func(key string, oldData, newData any) bool {
 			return false
 		},
-		Fetcher: func(key string) (any, error) {
+		Fetcher: func(ctx context.Context, key string) (any, error) {
 			if first {
 				first = false
 				return nil, errors.New("error")
@@ -127,77 +133,114 @@ func TestGetOrSetErr(t *testing.T) {
 	}
 	c := NewAsyncCache(op)
 
-	v := c.GetOrSet(key, def)
+	v := c.GetOrSet(ctx, key, def)
 	assert.Equal(t, v.(string), def)
 
 	time.Sleep(time.Second / 2)
-	v = c.GetOrSet(key, ret)
+	v = c.GetOrSet(ctx, key, ret)
 	assert.NotEqual(t, v.(string), ret)
 	assert.Equal(t, v.(string), def)
 
 	time.Sleep(time.Second + 500*time.Millisecond)
-	v = c.GetOrSet(key, def)
+	v = c.GetOrSet(ctx, key, def)
 	assert.Equal(t, v.(string), ret)
 }
 
 func TestSetDefault(t *testing.T) {
-	op := Options{
+	op := Options[string, any]{
 		RefreshDuration: time.Second,
 		IsSame: func(key string, oldData, newData any) bool {
 			return false
 		},
-		Fetcher: func(key string) (any, error) {
+		Fetcher: func(ctx context.Context, key string) (any, error) {
 			return nil, errors.New("error")
 		},
 	}
 	c := NewAsyncCache(op)
 
-	v := c.GetOrSet("key1", "def1")
+	v := c.GetOrSet(ctx, "key1", "def1")
 	assert.Equal(t, v.(string), "def1")
 
 	exist := c.SetDefault("key2", "val2")
 	assert.False(t, exist)
-	v = c.GetOrSet("key2", "def2")
+	v = c.GetOrSet(ctx, "key2", "def2")
 	assert.Equal(t, v.(string), "val2")
 
 	exist = c.SetDefault("key2", "val3")
 	assert.True(t, exist)
-	v = c.GetOrSet("key2", "def2")
+	v = c.GetOrSet(ctx, "key2", "def2")
 	assert.Equal(t, v.(string), "val2")
 }
 
 func TestDeleteIf(t *testing.T) {
-	op := Options{
+	op := Options[string, any]{
 		RefreshDuration: time.Second,
 		IsSame: func(key string, oldData, newData any) bool {
 			return false
 		},
-		Fetcher: func(key string) (any, error) {
+		Fetcher: func(ctx context.Context, key string) (any, error) {
 			return nil, errors.New("error")
 		},
 	}
 	c := NewAsyncCache(op)
 
 	c.SetDefault("key", "val")
-	v := c.GetOrSet("key", "def")
+	v := c.GetOrSet(ctx, "key", "def")
 	assert.Equal(t, v.(string), "val")
 
 	d, _ := c.(interface{ DeleteIf(func(key string) bool) })
 	d.DeleteIf(func(string) bool { return true })
 
-	v = c.GetOrSet("key", "def")
+	v = c.GetOrSet(ctx, "key", "def")
 	assert.Equal(t, v.(string), "def")
 }
 
+func TestStore(t *testing.T) {
+	var fetched atomic.Int32
+	op := Options[string, string]{
+		RefreshDuration: time.Hour,
+		Fetcher: func(ctx context.Context, key string) (string, error) {
+			fetched.Add(1)
+			if key == "err" {
+				return "", errors.New("error")
+			}
+			return "fetched", nil
+		},
+		MaxEntries: 2,
+	}
+	c := NewAsyncCache(op)
+	defer c.Close()
+	s, ok := c.(Store[string, string])
+	require.True(t, ok)
+
+	_, ok = s.Peek("key")
+	assert.False(t, ok)
+	s.Set("key", "val")
+	v, ok := s.Peek("key")
+	assert.True(t, ok)
+	assert.Equal(t, "val", v)
+	s.Set("key", "new")
+	v, _ = c.Get(ctx, "key")
+	assert.Equal(t, "new", v)
+	s.Delete("key")
+	_, ok = s.Peek("key")
+	assert.False(t, ok)
+	assert.Equal(t, int32(0), fetched.Load())
+
+	c.Get(ctx, "err")
+	_, ok = s.Peek("err")
+	assert.False(t, ok)
+}
+
 func TestClose(t *testing.T) {
 	dur := time.Second / 10
 	var cnt int
-	op := Options{
+	op := Options[string, any]{
 		RefreshDuration: dur - 10*time.Millisecond,
 		IsSame: func(key string, oldData, newData any) bool {
 			return false
 		},
-		Fetcher: func(key string) (any, error) {
+		Fetcher: func(ctx context.Context, key string) (any, error) {
 			cnt++
 			return cnt, nil
 		},
@@ -206,79 +249,253 @@ func TestClose(t *testing.T) {
 	}
 	c := NewAsyncCache(op)
 
-	v := c.GetOrSet("key", 10)
+	v := c.GetOrSet(ctx, "key", 10)
 	assert.Equal(t, v.(int), 1)
 
 	time.Sleep(dur)
-	v = c.GetOrSet("key", 10)
+	v = c.GetOrSet(ctx, "key", 10)
 	assert.Equal(t, v.(int), 2)
 
 	time.Sleep(dur)
-	v = c.GetOrSet("key", 10)
+	v = c.GetOrSet(ctx, "key", 10)
 	assert.Equal(t, v.(int), 3)
 
 	c.Close()
 
 	time.Sleep(dur)
-	v = c.GetOrSet("key", 10)
+	v = c.GetOrSet(ctx, "key", 10)
 	assert.Equal(t, v.(int), 3)
 }
 
 func TestExpire(t *testing.T) {
 	// trigger is used to mark whether fetcher is called
 	trigger := false
-	op := Options{
+	op := Options[string, any]{
 		EnableExpire:    true,
 		ExpireDuration:  3 * time.Minute,
 		RefreshDuration: time.Minute,
 		IsSame: func(key string, oldData, newData any) bool {
 			return true
 		},
-		Fetcher: func(key string) (any, error) {
+		Fetcher: func(ctx context.Context, key string) (any, error) {
 			trigger = true
 			return "", nil
 		},
 	}
-	c := NewAsyncCache(op).(*asyncCache)
+	c := NewAsyncCache(op).(*asyncCache[string, any])
 
 	// GetOrSet cannot trigger fetcher when SetDefault before
 	c.SetDefault("key-default", "")
 	c.SetDefault("key-alive", "")
-	c.GetOrSet("key-alive", "")
+	c.GetOrSet(ctx, "key-alive", "")
 	assert.False(t, trigger)
 
-	c.Get("key-expire")
+	c.Get(ctx, "key-expire")
 	assert.True(t, trigger)
 
 	// first expire set tag
 	c.expire()
 
 	trigger = false
-	c.Get("key-alive")
+	c.Get(ctx, "key-alive")
 	assert.False(t, trigger)
 	// second expire, both key-default & key-expire have been removed
 	c.expire()
 	c.refresh() // prove refresh does not affect expire
 
 	trigger = false
-	c.Get("key-alive")
+	c.Get(ctx, "key-alive")
 	assert.False(t, trigger)
 	trigger = false
-	c.Get("key-default")
+	c.Get(ctx, "key-default")
 	assert.True(t, trigger)
 	trigger = false
-	c.Get("key-expire")
+	c.Get(ctx, "key-expire")
 	assert.True(t, trigger)
 }
 
+func TestGetDeadline(t *testing.T) {
+	release := make(chan struct{})
+	var deleted []int
+	op := Options[int, string]{
+		RefreshDuration: time.Minute,
+		Fetcher: func(ctx context.Context, key int) (string, error) {
+			<-release
+			assert.NoError(t, ctx.Err())
+			return "val", nil
+		},
+		DeleteHandler: func(key int, oldData string) {
+			assert.Equal(t, "val", oldData)
+		},
+	}
+	c := NewAsyncCache(op)
+	defer c.Close()
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err := c.Get(timeoutCtx, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, "def", c.GetOrSet(timeoutCtx, 1, "def"))
+
+	// the fetching continues for other waiters
+	done := make(chan string)
+	go func() {
+		v, err := c.Get(ctx, 1)
+		assert.NoError(t, err)
+		done <- v
+	}()
+	close(release)
+	assert.Equal(t, "val", <-done)
+	assert.Equal(t, map[int]string{1: "val"}, c.Dump())
+
+	c.DeleteIf(func(key int) bool {
+		deleted = append(deleted, key)
+		return true
+	})
+	assert.Equal(t, []int{1}, deleted)
+	assert.Empty(t, c.Dump())
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	var cnt atomic.Int32
+	op := Options[string, int32]{
+		RefreshDuration:      time.Hour,
+		StaleWhileRevalidate: true,
+		FetcherWithTTL: func(ctx context.Context, key string) (int32, time.Duration, error) {
+			// the first value is stale at once
+			n := cnt.Add(1)
+			if n == 1 {
+				return n, time.Nanosecond, nil
+			}
+			return n, 0, nil
+		},
+	}
+	c := NewAsyncCache(op)
+	defer c.Close()
+
+	v, err := c.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), v)
+
+	// the stale value is served while refreshing in background
+	time.Sleep(time.Millisecond)
+	v, err = c.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), v)
+	assert.Eventually(t, func() bool {
+		v, _ := c.Get(ctx, "key")
+		return v == 2
+	}, time.Second, time.Millisecond)
+
+	// the refreshed value is fresh for RefreshDuration
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int32(2), c.GetOrSet(ctx, "key", 0))
+	assert.Equal(t, int32(2), cnt.Load())
+}
+
+func TestRefreshDeadline(t *testing.T) {
+	var fetched sync.Map
+	op := Options[string, string]{
+		RefreshDuration: time.Minute,
+		RefreshJitter:   10 * time.Second,
+		FetcherWithTTL: func(ctx context.Context, key string) (string, time.Duration, error) {
+			n, _ := fetched.LoadOrStore(key, new(atomic.Int32))
+			n.(*atomic.Int32).Add(1)
+			if key == "short" {
+				return key, time.Nanosecond, nil
+			}
+			return key, 0, nil
+		},
+	}
+	c := NewAsyncCache(op).(*asyncCache[string, string])
+	defer c.Close()
+
+	c.Get(ctx, "short")
+	c.Get(ctx, "long")
+	e, _ := c.data.Load("long")
+	deadline := time.Until(time.Unix(0, e.(*entry[string]).deadline.Load()))
+	assert.True(t, deadline > 59*time.Second && deadline < 70*time.Second)
+
+	// only the key with short ttl is due
+	c.refresh()
+	n, _ := fetched.Load("short")
+	assert.Equal(t, int32(2), n.(*atomic.Int32).Load())
+	n, _ = fetched.Load("long")
+	assert.Equal(t, int32(1), n.(*atomic.Int32).Load())
+}
+
+func TestMaxConcurrentRefresh(t *testing.T) {
+	var running, peak atomic.Int32
+	op := Options[int, int]{
+		RefreshDuration:      time.Hour,
+		MaxConcurrentRefresh: 2,
+		Fetcher: func(ctx context.Context, key int) (int, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			time.Sleep(10 * time.Millisecond)
+			return key, nil
+		},
+	}
+	c := NewAsyncCache(op).(*asyncCache[int, int])
+	defer c.Close()
+
+	for i := range 10 {
+		c.SetDefault(i, 0)
+	}
+	c.data.Range(func(_, value any) bool {
+		value.(*entry[int]).deadline.Store(0)
+		return true
+	})
+	peak.Store(0)
+	c.refresh()
+	assert.Equal(t, int32(2), peak.Load())
+	assert.Equal(t, 9, c.Dump()[9])
+}
+
+func TestStats(t *testing.T) {
+	op := Options[string, string]{
+		RefreshDuration: time.Hour,
+		Fetcher: func(ctx context.Context, key string) (string, error) {
+			if key == "err" {
+				return "", errors.New("error")
+			}
+			return key, nil
+		},
+		MaxEntries: 2,
+	}
+	c := NewAsyncCache(op).(*asyncCache[string, string])
+	defer c.Close()
+
+	c.Get(ctx, "a")
+	c.Get(ctx, "a")
+	c.Get(ctx, "err")
+	c.Get(ctx, "b")
+	c.data.Range(func(_, value any) bool {
+		value.(*entry[string]).deadline.Store(0)
+		return true
+	})
+	c.refresh()
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(3), stats.Misses)
+	assert.Equal(t, uint64(5), stats.Fetches)
+	assert.Equal(t, uint64(2), stats.FetchErrors)
+	assert.Equal(t, uint64(2), stats.Refreshes)
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, 2, stats.Entries)
+}
+
 func BenchmarkGet(b *testing.B) {
 	key := "key"
-	op := Options{
+	op := Options[string, any]{
 		RefreshDuration: time.Second,
 		IsSame: func(key string, oldData, newData any) bool {
 			return false
 		},
-		Fetcher: func(key string) (any, error) {
+		Fetcher: func(ctx context.Context, key string) (any, error) {
 			return "", nil
 		},
 	}
@@ -287,18 +504,18 @@ func BenchmarkGet(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = c.Get(key)
+		_, _ = c.Get(ctx, key)
 	}
 }
 
 func BenchmarkGetParallel(b *testing.B) {
 	key := "key"
-	op := Options{
+	op := Options[string, any]{
 		RefreshDuration: time.Second,
 		IsSame: func(key string, oldData, newData any) bool {
 			return false
 		},
-		Fetcher: func(key string) (any, error) {
+		Fetcher: func(ctx context.Context, key string) (any, error) {
 			return "", nil
 		},
 	}
@@ -308,19 +525,19 @@ func BenchmarkGetParallel(b *testing.B) {
 	b.ResetTimer()
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
-			_, _ = c.Get(key)
+			_, _ = c.Get(ctx, key)
 		}
 	})
 }
 
 func BenchmarkGetOrSet(b *testing.B) {
 	key, def := "key", "def"
-	op := Options{
+	op := Options[string, any]{
 		RefreshDuration: time.Second,
 		IsSame: func(key string, oldData, newData any) bool {
 			return false
 		},
-		Fetcher: func(key string) (any, error) {
+		Fetcher: func(ctx context.Context, key string) (any, error) {
 			return "", nil
 		},
 	}
@@ -329,18 +546,18 @@ func BenchmarkGetOrSet(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = c.GetOrSet(key, def)
+		_ = c.GetOrSet(ctx, key, def)
 	}
 }
 
 func BenchmarkGetOrSetParallel(b *testing.B) {
 	key, def := "key", "def"
-	op := Options{
+	op := Options[string, any]{
 		RefreshDuration: time.Second,
 		IsSame: func(key string, oldData, newData any) bool {
 			return false
 		},
-		Fetcher: func(key string) (any, error) {
+		Fetcher: func(ctx context.Context, key string) (any, error) {
 			return "", nil
 		},
 	}
@@ -350,23 +567,23 @@ func BenchmarkGetOrSetParallel(b *testing.B) {
 	b.ResetTimer()
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
-			_ = c.GetOrSet(key, def)
+			_ = c.GetOrSet(ctx, key, def)
 		}
 	})
 }
 
 func BenchmarkRefresh(b *testing.B) {
 	key, def := "key", "def"
-	op := Options{
+	op := Options[string, any]{
 		RefreshDuration: time.Second,
 		IsSame: func(key string, oldData, newData any) bool {
 			return false
 		},
-		Fetcher: func(key string) (any, error) {
+		Fetcher: func(ctx context.Context, key string) (any, error) {
 			return "", nil
 		},
 	}
-	c := NewAsyncCache(op).(*asyncCache)
+	c := NewAsyncCache(op).(*asyncCache[string, any])
 	c.SetDefault(key, def)
 
 	b.ReportAllocs()
@@ -378,16 +595,16 @@ func BenchmarkRefresh(b *testing.B) {
 
 func BenchmarkRefreshParallel(b *testing.B) {
 	key, def := "key", "def"
-	op := Options{
+	op := Options[string, any]{
 		RefreshDuration: time.Second,
 		IsSame: func(key string, oldData, newData any) bool {
 			return false
 		},
-		Fetcher: func(key string) (any, error) {
+		Fetcher: func(ctx context.Context, key string) (any, error) {
 			return "", nil
 		},
 	}
-	c := NewAsyncCache(op).(*asyncCache)
+	c := NewAsyncCache(op).(*asyncCache[string, any])
 	c.SetDefault(key, def)
 
 	b.ReportAllocs()
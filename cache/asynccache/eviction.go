@@ -0,0 +1,391 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package asynccache
+
+import (
+	"container/list"
+	"hash/maphash"
+)
+
+// EvictionPolicy policy used to evict entries when the cache is full
+type EvictionPolicy int
+
+const (
+	// EvictLRU evict the least recently used entry
+	EvictLRU EvictionPolicy = iota
+	// EvictLFU evict the least frequently used entry, ties broken by recency
+	EvictLFU
+	// EvictTinyLFU W-TinyLFU that admit new entry only if it's more frequently
+	// used than the entry to be evicted, good for scan and burst of unique keys
+	EvictTinyLFU
+)
+
+// evictor track keys of a bounded cache, it's not safe for concurrent use
+type evictor[K comparable] interface {
+	// add record key with cost, or update cost of an exist key without counting
+	// it as an access, and return the keys that should be evicted.
+	add(key K, cost int64) (victims []K)
+	// access record a hit of key
+	access(key K)
+	// remove forget key
+	remove(key K)
+}
+
+// bound limits of entries count and total cost, zero means no limit
+type bound struct {
+	maxEntries int
+	maxCost    int64
+	count      int
+	cost       int64
+}
+
+func (b *bound) over() bool {
+	return (b.maxEntries > 0 && b.count > b.maxEntries) || (b.maxCost > 0 && b.cost > b.maxCost)
+}
+
+type evictNode[K comparable] struct {
+	key  K
+	cost int64
+	// seg is segment for tinyLFU
+	seg int
+}
+
+func newEvictor[K comparable](policy EvictionPolicy, maxEntries int, maxCost int64) evictor[K] {
+	b := bound{maxEntries: maxEntries, maxCost: maxCost}
+	switch policy {
+	case EvictLFU:
+		return &lfuEvictor[K]{bound: b, nodes: make(map[K]*list.Element), buckets: list.New()}
+	case EvictTinyLFU:
+		return newTinyLFUEvictor[K](b)
+	default:
+		return &lruEvictor[K]{bound: b, nodes: make(map[K]*list.Element), ll: list.New()}
+	}
+}
+
+type lruEvictor[K comparable] struct {
+	bound
+	nodes map[K]*list.Element
+	ll    *list.List
+}
+
+func (e *lruEvictor[K]) add(key K, cost int64) (victims []K) {
+	if el, exist := e.nodes[key]; exist {
+		node := el.Value.(*evictNode[K])
+		e.cost += cost - node.cost
+		node.cost = cost
+	} else {
+		e.nodes[key] = e.ll.PushFront(&evictNode[K]{key: key, cost: cost})
+		e.count, e.cost = e.count+1, e.cost+cost
+	}
+	for e.over() && e.ll.Len() > 0 {
+		victim := e.ll.Back().Value.(*evictNode[K]).key
+		e.remove(victim)
+		victims = append(victims, victim)
+	}
+	return
+}
+
+func (e *lruEvictor[K]) access(key K) {
+	if el, exist := e.nodes[key]; exist {
+		e.ll.MoveToFront(el)
+	}
+}
+
+func (e *lruEvictor[K]) remove(key K) {
+	if el, exist := e.nodes[key]; exist {
+		e.ll.Remove(el)
+		delete(e.nodes, key)
+		e.count, e.cost = e.count-1, e.cost-el.Value.(*evictNode[K]).cost
+	}
+}
+
+// lfuEvictor O(1) LFU that group keys by frequency in buckets ordered by frequency,
+// keys of a bucket are ordered by recency.
+type lfuEvictor[K comparable] struct {
+	bound
+	nodes map[K]*list.Element
+	// buckets of *lfuBucket ordered by frequency from low to high
+	buckets *list.List
+}
+
+type lfuBucket struct {
+	freq  int
+	items *list.List
+}
+
+type lfuNode[K comparable] struct {
+	key    K
+	cost   int64
+	bucket *list.Element
+}
+
+func (e *lfuEvictor[K]) add(key K, cost int64) (victims []K) {
+	if el, exist := e.nodes[key]; exist {
+		node := el.Value.(*lfuNode[K])
+		e.cost += cost - node.cost
+		node.cost = cost
+	} else {
+		// evict before insert, or the new key is always the least frequently used
+		e.count, e.cost = e.count+1, e.cost+cost
+		victims = e.evict()
+		front := e.buckets.Front()
+		if front == nil || front.Value.(*lfuBucket).freq != 1 {
+			front = e.buckets.PushFront(&lfuBucket{freq: 1, items: list.New()})
+		}
+		e.push(&lfuNode[K]{key: key, cost: cost}, front)
+	}
+	return append(victims, e.evict()...)
+}
+
+func (e *lfuEvictor[K]) evict() (victims []K) {
+	for e.over() && len(e.nodes) > 0 {
+		victim := e.buckets.Front().Value.(*lfuBucket).items.Back().Value.(*lfuNode[K]).key
+		e.remove(victim)
+		victims = append(victims, victim)
+	}
+	return
+}
+
+func (e *lfuEvictor[K]) access(key K) {
+	el, exist := e.nodes[key]
+	if !exist {
+		return
+	}
+	node := el.Value.(*lfuNode[K])
+	cur := node.bucket
+	freq := cur.Value.(*lfuBucket).freq + 1
+	next := cur.Next()
+	if next == nil || next.Value.(*lfuBucket).freq != freq {
+		next = e.buckets.InsertAfter(&lfuBucket{freq: freq, items: list.New()}, cur)
+	}
+	e.unlink(el, cur)
+	e.push(node, next)
+}
+
+func (e *lfuEvictor[K]) remove(key K) {
+	el, exist := e.nodes[key]
+	if !exist {
+		return
+	}
+	node := el.Value.(*lfuNode[K])
+	e.unlink(el, node.bucket)
+	delete(e.nodes, key)
+	e.count, e.cost = e.count-1, e.cost-node.cost
+}
+
+func (e *lfuEvictor[K]) push(node *lfuNode[K], bucket *list.Element) {
+	node.bucket = bucket
+	e.nodes[node.key] = bucket.Value.(*lfuBucket).items.PushFront(node)
+}
+
+func (e *lfuEvictor[K]) unlink(el *list.Element, bucket *list.Element) {
+	items := bucket.Value.(*lfuBucket).items
+	items.Remove(el)
+	if items.Len() == 0 {
+		e.buckets.Remove(bucket)
+	}
+}
+
+const (
+	segWindow = iota
+	segProbation
+	segProtected
+)
+
+// tinyLFUEvictor W-TinyLFU: a small LRU window admit new keys, the main space is
+// a segmented LRU, and a key leaving window replace the victim of main space only
+// if its estimated frequency is higher.
+type tinyLFUEvictor[K comparable] struct {
+	bound
+	seed     maphash.Seed
+	sketch   *cmSketch
+	nodes    map[K]*list.Element
+	segments [3]*list.List
+	weights  [3]int64
+	// capacity of window and protected segment in weight
+	windowCap    int64
+	protectedCap int64
+}
+
+func newTinyLFUEvictor[K comparable](b bound) *tinyLFUEvictor[K] {
+	// weight is cost if cost is limited, otherwise is count of entries
+	capacity, width := int64(b.maxEntries), b.maxEntries
+	if b.maxCost > 0 {
+		capacity = b.maxCost
+	}
+	if width <= 0 {
+		width = 1024
+	}
+	// a wider sketch reduce collision of counters for a scan of unique keys
+	width *= 8
+	e := &tinyLFUEvictor[K]{
+		bound:        b,
+		seed:         maphash.MakeSeed(),
+		sketch:       newCMSketch(width),
+		nodes:        make(map[K]*list.Element),
+		windowCap:    max(capacity/100, 1),
+		protectedCap: max(capacity*80/100, 1),
+	}
+	for i := range e.segments {
+		e.segments[i] = list.New()
+	}
+	return e
+}
+
+func (e *tinyLFUEvictor[K]) weight(cost int64) int64 {
+	if e.maxCost > 0 {
+		return cost
+	}
+	return 1
+}
+
+func (e *tinyLFUEvictor[K]) add(key K, cost int64) (victims []K) {
+	if el, exist := e.nodes[key]; exist {
+		node := el.Value.(*evictNode[K])
+		e.weights[node.seg] += e.weight(cost) - e.weight(node.cost)
+		e.cost += cost - node.cost
+		node.cost = cost
+	} else {
+		e.sketch.increment(maphash.Comparable(e.seed, key))
+		e.nodes[key] = e.segments[segWindow].PushFront(&evictNode[K]{key: key, cost: cost, seg: segWindow})
+		e.weights[segWindow] += e.weight(cost)
+		e.count, e.cost = e.count+1, e.cost+cost
+	}
+	// keys leave window become candidates in probation
+	var candidates []*list.Element
+	for window := e.segments[segWindow]; e.weights[segWindow] > e.windowCap && window.Len() > 1; {
+		candidates = append(candidates, e.move(window.Back(), segProbation))
+	}
+	for e.over() && len(e.nodes) > 0 {
+		victim := e.segments[segProbation].Back()
+		if victim == nil {
+			victim = e.segments[segProtected].Back()
+		}
+		if victim == nil {
+			victim = e.segments[segWindow].Back()
+		}
+		for len(candidates) > 0 {
+			candidate := candidates[0]
+			candidates = candidates[1:]
+			// skip candidate that has been evicted as a victim already
+			if e.nodes[candidate.Value.(*evictNode[K]).key] != candidate {
+				continue
+			}
+			if candidate != victim && e.frequency(candidate) <= e.frequency(victim) {
+				victim = candidate
+			}
+			break
+		}
+		key := victim.Value.(*evictNode[K]).key
+		e.remove(key)
+		victims = append(victims, key)
+	}
+	return
+}
+
+func (e *tinyLFUEvictor[K]) access(key K) {
+	e.sketch.increment(maphash.Comparable(e.seed, key))
+	el, exist := e.nodes[key]
+	if !exist {
+		return
+	}
+	switch el.Value.(*evictNode[K]).seg {
+	case segProbation:
+		e.move(el, segProtected)
+		// demote the overflow of protected back to probation
+		for protected := e.segments[segProtected]; e.weights[segProtected] > e.protectedCap && protected.Len() > 1; {
+			e.move(protected.Back(), segProbation)
+		}
+	default:
+		e.segments[el.Value.(*evictNode[K]).seg].MoveToFront(el)
+	}
+}
+
+func (e *tinyLFUEvictor[K]) remove(key K) {
+	el, exist := e.nodes[key]
+	if !exist {
+		return
+	}
+	node := el.Value.(*evictNode[K])
+	e.segments[node.seg].Remove(el)
+	e.weights[node.seg] -= e.weight(node.cost)
+	delete(e.nodes, key)
+	e.count, e.cost = e.count-1, e.cost-node.cost
+}
+
+func (e *tinyLFUEvictor[K]) move(el *list.Element, seg int) *list.Element {
+	node := el.Value.(*evictNode[K])
+	e.segments[node.seg].Remove(el)
+	e.weights[node.seg] -= e.weight(node.cost)
+	node.seg = seg
+	e.weights[seg] += e.weight(node.cost)
+	el = e.segments[seg].PushFront(node)
+	e.nodes[node.key] = el
+	return el
+}
+
+func (e *tinyLFUEvictor[K]) frequency(el *list.Element) uint8 {
+	return e.sketch.estimate(maphash.Comparable(e.seed, el.Value.(*evictNode[K]).key))
+}
+
+const _cmDepth = 4
+
+// cmSketch count-min sketch with 8-bit counters that halved periodically, so the
+// frequency of old keys decays.
+type cmSketch struct {
+	rows    [_cmDepth][]uint8
+	mask    uint64
+	added   int
+	resetAt int
+}
+
+func newCMSketch(width int) *cmSketch {
+	size := 16
+	for size < width {
+		size <<= 1
+	}
+	s := &cmSketch{
+		mask:    uint64(size - 1),
+		resetAt: 10 * size,
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, size)
+	}
+	return s
+}
+
+func (s *cmSketch) increment(h uint64) {
+	for i := range s.rows {
+		idx := s.index(h, i)
+		if s.rows[i][idx] < 255 {
+			s.rows[i][idx]++
+		}
+	}
+	if s.added++; s.added >= s.resetAt {
+		s.reset()
+	}
+}
+
+func (s *cmSketch) estimate(h uint64) uint8 {
+	res := uint8(255)
+	for i := range s.rows {
+		res = min(res, s.rows[i][s.index(h, i)])
+	}
+	return res
+}
+
+func (s *cmSketch) reset() {
+	s.added /= 2
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+}
+
+func (s *cmSketch) index(h uint64, i int) uint64 {
+	// derive hash of each row by double hashing
+	return (h + uint64(i)*(h>>32|1)) & s.mask
+}
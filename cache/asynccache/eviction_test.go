@@ -0,0 +1,119 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package asynccache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvictLRU(t *testing.T) {
+	e := newEvictor[int](EvictLRU, 3, 0)
+	for i := range 3 {
+		assert.Empty(t, e.add(i, 1))
+	}
+	e.access(0)
+	assert.Equal(t, []int{1}, e.add(3, 1))
+	assert.Equal(t, []int{2}, e.add(4, 1))
+	e.remove(0)
+	assert.Empty(t, e.add(5, 1))
+}
+
+func TestEvictLFU(t *testing.T) {
+	e := newEvictor[int](EvictLFU, 3, 0)
+	for i := range 3 {
+		assert.Empty(t, e.add(i, 1))
+	}
+	e.access(0)
+	e.access(0)
+	e.access(1)
+	assert.Equal(t, []int{2}, e.add(3, 1))
+	// the new key is the least frequently used one now
+	assert.Equal(t, []int{3}, e.add(4, 1))
+	// remove the only key of the lowest frequency
+	e.remove(4)
+	e.access(1)
+	assert.Empty(t, e.add(5, 1))
+	assert.Equal(t, []int{5}, e.add(6, 1))
+	e.access(6)
+	e.access(6)
+	e.access(6)
+	// 0 and 1 have the same frequency, 0 is less recently used
+	assert.Equal(t, []int{0}, e.add(7, 1))
+}
+
+func TestEvictCost(t *testing.T) {
+	for _, policy := range []EvictionPolicy{EvictLRU, EvictLFU, EvictTinyLFU} {
+		e := newEvictor[int](policy, 0, 10)
+		assert.Empty(t, e.add(0, 4))
+		assert.Empty(t, e.add(1, 4))
+		assert.Len(t, e.add(2, 4), 1)
+		// an entry that larger than max cost is never kept
+		assert.Contains(t, e.add(3, 11), 3)
+	}
+}
+
+func TestEvictTinyLFU(t *testing.T) {
+	e := newEvictor[int](EvictTinyLFU, 100, 0)
+	for i := range 100 {
+		e.add(i, 1)
+		for range 10 {
+			e.access(i)
+		}
+	}
+	// a scan of unique keys should not flush the frequently used keys
+	for i := 100; i < 1000; i++ {
+		e.add(i, 1)
+	}
+	hot := 0
+	for i := range 100 {
+		if _, exist := e.(*tinyLFUEvictor[int]).nodes[i]; exist {
+			hot++
+		}
+	}
+	assert.Greater(t, hot, 90)
+}
+
+func TestBoundedCache(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		deleted []string
+	)
+	c := NewAsyncCache(Options[string, string]{
+		RefreshDuration: time.Minute,
+		Fetcher: func(ctx context.Context, key string) (string, error) {
+			return key, nil
+		},
+		DeleteHandler: func(key string, oldData string) {
+			mu.Lock()
+			deleted = append(deleted, key)
+			mu.Unlock()
+		},
+		MaxEntries: 10,
+	})
+	defer c.Close()
+
+	for i := range 100 {
+		_, err := c.Get(ctx, strconv.Itoa(i))
+		assert.NoError(t, err)
+	}
+	assert.Len(t, c.Dump(), 10)
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(deleted) == 90
+	}, time.Second, 10*time.Millisecond)
+
+	c.DeleteIf(func(string) bool { return true })
+	for i := range 10 {
+		assert.False(t, c.SetDefault(strconv.Itoa(i), "default"))
+	}
+	assert.Len(t, c.Dump(), 10)
+}
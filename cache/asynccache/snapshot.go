@@ -0,0 +1,113 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package asynccache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const _snapshotVersion = 1
+
+// ErrSnapshotVersion snapshot is written by an unsupported version
+var ErrSnapshotVersion = errors.New("asynccache: unsupported snapshot version")
+
+// Codec encodes and decodes snapshot of the cache, json.API of the json
+// facade module github.com/alimy/tryst/json is a Codec.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// stdCodec a Codec of encoding/json
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+type snapshot[K comparable, V any] struct {
+	Version int                   `json:"version"`
+	Entries []snapshotEntry[K, V] `json:"entries"`
+}
+
+// snapshotEntry entry of snapshot, the times are relative to the snapshot time
+// so that they are independent of clock of the restoring host.
+type snapshotEntry[K comparable, V any] struct {
+	Key K `json:"key"`
+	Val V `json:"val"`
+	// Age is the duration since the value fetched
+	Age time.Duration `json:"age"`
+	// TTL is the duration until the value stale, may be negative
+	TTL time.Duration `json:"ttl"`
+}
+
+// Snapshot writes all cached entries without error to w.
+func (c *asyncCache[K, V]) Snapshot(w io.Writer) error {
+	now := time.Now().UnixNano()
+	snap := snapshot[K, V]{Version: _snapshotVersion}
+	c.data.Range(func(key, value any) bool {
+		e := value.(*entry[V])
+		if e.err.Load() != nil {
+			return true
+		}
+		snap.Entries = append(snap.Entries, snapshotEntry[K, V]{
+			Key: key.(K),
+			Val: *e.val.Load(),
+			Age: time.Duration(now - e.updated.Load()),
+			TTL: time.Duration(e.deadline.Load() - now),
+		})
+		return true
+	})
+	data, err := c.opt.Codec.Marshal(&snap)
+	if err != nil {
+		return fmt.Errorf("asynccache: encode snapshot: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Restore reads a snapshot from r and sets its entries that are new to the
+// cache, with their ages and deadlines preserved.
+func (c *asyncCache[K, V]) Restore(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var snap snapshot[K, V]
+	if err = c.opt.Codec.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("asynccache: decode snapshot: %w", err)
+	}
+	if snap.Version != _snapshotVersion {
+		return ErrSnapshotVersion
+	}
+	now := time.Now()
+	for _, se := range snap.Entries {
+		e := &entry[V]{}
+		e.Store(se.Val, nil)
+		e.updated.Store(now.Add(-se.Age).UnixNano())
+		e.deadline.Store(now.Add(se.TTL).UnixNano())
+		c.restore(se.Key, e)
+	}
+	return nil
+}
+
+// restore stores e if key is new to the cache.
+func (c *asyncCache[K, V]) restore(key K, e *entry[V]) {
+	if c.evictor != nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	}
+	if _, exist := c.data.LoadOrStore(key, e); !exist {
+		c.admit(key, *e.val.Load())
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package asynccache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	var fetched atomic.Int32
+	op := Options[string, int]{
+		RefreshDuration: time.Hour,
+		Fetcher: func(ctx context.Context, key string) (int, error) {
+			fetched.Add(1)
+			if key == "err" {
+				return 0, errors.New("error")
+			}
+			return len(key), nil
+		},
+	}
+	c := NewAsyncCache(op).(*asyncCache[string, int])
+	defer c.Close()
+	c.Get(ctx, "a")
+	c.Get(ctx, "bb")
+	c.Get(ctx, "err")
+	e, _ := c.data.Load("a")
+	e.(*entry[int]).updated.Add(-int64(time.Minute))
+
+	var buf bytes.Buffer
+	assert.NoError(t, c.Snapshot(&buf))
+
+	r := NewAsyncCache(op).(*asyncCache[string, int])
+	defer r.Close()
+	r.SetDefault("bb", 0)
+	assert.NoError(t, r.Restore(&buf))
+	// the existing entry is not overridden and entry with error is skipped
+	assert.Equal(t, map[string]int{"a": 1, "bb": 0}, r.Dump())
+
+	fetched.Store(0)
+	v, err := r.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.Zero(t, fetched.Load())
+
+	re, _ := r.data.Load("a")
+	age := time.Since(time.Unix(0, re.(*entry[int]).updated.Load()))
+	assert.True(t, age >= time.Minute && age < 2*time.Minute)
+	ttl := time.Until(time.Unix(0, re.(*entry[int]).deadline.Load()))
+	assert.True(t, ttl > 59*time.Minute && ttl <= time.Hour)
+}
+
+func TestRestoreErr(t *testing.T) {
+	c := NewAsyncCache(Options[string, int]{
+		RefreshDuration: time.Hour,
+		Fetcher: func(ctx context.Context, key string) (int, error) {
+			return 0, nil
+		},
+	})
+	defer c.Close()
+
+	assert.ErrorIs(t, c.Restore(strings.NewReader(`{"version":0}`)), ErrSnapshotVersion)
+	assert.Error(t, c.Restore(strings.NewReader(`{`)))
+}
@@ -5,49 +5,83 @@
 package cache
 
 import (
+	"time"
+
 	lru "github.com/hashicorp/golang-lru/v2"
 )
 
-// KeyPool[K] key pool used for cache keys
+var _ StatsProvider = (*lruKeyPool[int])(nil)
+
+// KeyPool[K] key pool used for cache keys, it's kept unchanged for existing
+// implementations, key pools created by NewKeyPool implement StatsProvider
+// for statistics.
 type KeyPool[K comparable] interface {
 	Get(key K) string
 }
 
+// KeyPoolOption key pool option help function used to create key pool instance
+type KeyPoolOption = func(opt *keyPoolOpt)
+
+type keyPoolOpt struct {
+	metricsHook MetricsHook
+}
+
 type lruKeyPool[K comparable] struct {
 	pool  *lru.Cache[K, string]
 	newFn func(K) string
+	stats *StatsRecorder
 }
 
 func (p *lruKeyPool[K]) Get(key K) string {
 	res, ok := p.pool.Get(key)
 	if ok {
+		p.stats.OnHit()
 		return res
 	}
+	p.stats.OnMiss()
+	start := time.Now()
 	res = p.newFn(key)
+	p.stats.OnFetch(time.Since(start), nil)
 	p.pool.Add(key, res)
 	return res
 }
 
+func (p *lruKeyPool[K]) Stats() Stats {
+	return p.stats.Stats(p.pool.Len())
+}
+
+// WithMetricsHook set metrics hook
+func WithMetricsHook(h MetricsHook) KeyPoolOption {
+	return func(opt *keyPoolOpt) {
+		opt.metricsHook = h
+	}
+}
+
 // NewKeyPool[K] create a new KeyPool[K] instance
-func NewKeyPool[K comparable](size int, newFn func(key K) string) (KeyPool[K], error) {
-	pool, err := lru.New[K, string](size)
+func NewKeyPool[K comparable](size int, newFn func(key K) string, opts ...KeyPoolOption) (KeyPool[K], error) {
+	opt := &keyPoolOpt{}
+	for _, optFn := range opts {
+		optFn(opt)
+	}
+	p := &lruKeyPool[K]{
+		newFn: newFn,
+		stats: NewStatsRecorder(opt.metricsHook),
+	}
+	pool, err := lru.NewWithEvict(size, func(K, string) {
+		p.stats.OnEvict()
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &lruKeyPool[K]{
-		pool:  pool,
-		newFn: newFn,
-	}, nil
+	p.pool = pool
+	return p, nil
 }
 
 // MustKeyPool[K] same as NewKeyPool[K] bug panic if occurs error
-func MustKeyPool[K comparable](size int, newFn func(key K) string) KeyPool[K] {
-	pool, err := lru.New[K, string](size)
+func MustKeyPool[K comparable](size int, newFn func(key K) string, opts ...KeyPoolOption) KeyPool[K] {
+	pool, err := NewKeyPool(size, newFn, opts...)
 	if err != nil {
 		panic(err)
 	}
-	return &lruKeyPool[K]{
-		pool:  pool,
-		newFn: newFn,
-	}
+	return pool
 }
@@ -0,0 +1,302 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+// Package layered provides a two-level cache that put an in-process cache in
+// front of a remote one, and broadcast invalidations to other replicas over a
+// pub/sub bus. A value loaded concurrently with an invalidation is not filled
+// into L1, so L1 never keeps a value that has been invalidated.
+package layered
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"sync/atomic"
+	"time"
+
+	"github.com/alimy/tryst/cache"
+	"github.com/alimy/tryst/cache/asynccache"
+	"github.com/alimy/tryst/internal/singleflight"
+	"github.com/alimy/tryst/lang/fastrand"
+)
+
+const (
+	// _defaultLocalTTL ttl of the default L1 if LocalTTL is not set
+	_defaultLocalTTL = time.Minute
+	// _generations count of generation counters that keys are hashed to
+	_generations = 256
+)
+
+// ErrNotFound the key is found neither in cache nor by loader
+var ErrNotFound = errors.New("layered: not found")
+
+// Local in-process L1 cache, cache.Cache[string, V] is a Local, and an
+// asynccache.AsyncCache could be adapted by AsyncLocal.
+type Local[V any] interface {
+	Get(key string) (val V, ok bool)
+	Set(key string, val V)
+	Delete(key string)
+}
+
+type asyncLocal[V any] struct {
+	asynccache.Store[string, V]
+}
+
+func (l asyncLocal[V]) Get(key string) (V, bool) {
+	return l.Peek(key)
+}
+
+// AsyncLocal adapt an asynccache.AsyncCache created by asynccache.NewAsyncCache
+// to Local, the values of L1 are refreshed by its Fetcher in background, and
+// entries are expired or evicted by its options.
+func AsyncLocal[V any](c asynccache.AsyncCache[string, V]) Local[V] {
+	s, ok := c.(asynccache.Store[string, V])
+	if !ok {
+		panic("layered: AsyncLocal need an asynccache.Store")
+	}
+	return asyncLocal[V]{Store: s}
+}
+
+// Remote remote L2 cache with a Redis-like contract
+type Remote interface {
+	// Get return the value of key, ok is false if the key is not exist
+	Get(ctx context.Context, key string) (val []byte, ok bool, err error)
+	// Set set the value of key with ttl, zero ttl means never expire
+	Set(ctx context.Context, key string, val []byte, ttl time.Duration) error
+	// Del delete the keys
+	Del(ctx context.Context, keys ...string) error
+}
+
+// Bus pub/sub bus used to broadcast invalidations
+type Bus interface {
+	// Publish send msg to all subscribers, include the publisher self
+	Publish(ctx context.Context, msg []byte) error
+	// Subscribe register handler of messages, cancel stop the subscription
+	Subscribe(handler func(msg []byte)) (cancel func(), err error)
+}
+
+// Codec encodes and decodes values stored in Remote and messages of Bus,
+// json.API of the json facade module github.com/alimy/tryst/json is a Codec.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// stdCodec a Codec of encoding/json
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// Options[V] options used to create Cache[V] instance
+type Options[V any] struct {
+	// Local is the L1 cache, a cache.Cache[string, V] with LocalTTL is
+	// created if it is nil, LocalTTL is one minute if not set.
+	Local    Local[V]
+	LocalTTL time.Duration
+
+	// Remote is the L2 cache, it must be set.
+	Remote    Remote
+	RemoteTTL time.Duration
+
+	// Bus broadcast invalidations to other replicas, the invalidations are
+	// only applied locally if it is nil.
+	Bus Bus
+
+	// Codec is encoding/json if it is nil.
+	Codec Codec
+
+	// Loader load the value of key missed in both levels, the value is set
+	// to both levels. Get return ErrNotFound on miss if it is nil.
+	Loader func(ctx context.Context, key string) (V, error)
+}
+
+// Cache[V] two-level cache
+type Cache[V any] interface {
+	// Get return the value of key from L1, L2 or Loader in order, and fill
+	// the levels missed.
+	Get(ctx context.Context, key string) (V, error)
+	// Set set the value of key to L2 and L1, and invalidate L1 of other replicas.
+	Set(ctx context.Context, key string, val V) error
+	// Delete delete the keys from L2 and L1 of all replicas.
+	Delete(ctx context.Context, keys ...string) error
+	// Close stop the subscription of invalidations, and close the L1 created
+	// by New if Options.Local is nil.
+	Close()
+}
+
+// invalidation message broadcast over Bus
+type invalidation struct {
+	Origin string   `json:"origin"`
+	Keys   []string `json:"keys"`
+}
+
+type layeredCache[V any] struct {
+	opt      Options[V]
+	id       string
+	sfg      singleflight.Group[string, V]
+	cancel   func()
+	ownLocal cache.Cache[string, V]
+
+	// generations of keys hashed to them, an invalidation increase the
+	// generation of key and loads started at an old generation are not
+	// filled into L1.
+	seed maphash.Seed
+	gens [_generations]atomic.Uint64
+}
+
+// New[V] create a new Cache[V] instance
+func New[V any](opt Options[V]) (Cache[V], error) {
+	if opt.Remote == nil {
+		return nil, errors.New("layered: Remote must be set")
+	}
+	id := make([]byte, 8)
+	fastrand.Read(id)
+	c := &layeredCache[V]{
+		opt:  opt,
+		id:   hex.EncodeToString(id),
+		seed: maphash.MakeSeed(),
+	}
+	if c.opt.Codec == nil {
+		c.opt.Codec = stdCodec{}
+	}
+	if c.opt.Local == nil {
+		if c.opt.LocalTTL <= 0 {
+			c.opt.LocalTTL = _defaultLocalTTL
+		}
+		c.ownLocal = cache.NewCache(cache.Options[string, V]{TTL: c.opt.LocalTTL})
+		c.opt.Local = c.ownLocal
+	}
+	if c.opt.Bus != nil {
+		cancel, err := c.opt.Bus.Subscribe(c.onInvalidate)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("layered: subscribe invalidations: %w", err)
+		}
+		c.cancel = cancel
+	}
+	return c, nil
+}
+
+func (c *layeredCache[V]) Get(ctx context.Context, key string) (V, error) {
+	if val, ok := c.opt.Local.Get(key); ok {
+		return val, nil
+	}
+	return c.sfg.Do(ctx, key, func() (val V, err error) {
+		ctx, gen := context.WithoutCancel(ctx), c.generation(key).Load()
+		data, ok, err := c.opt.Remote.Get(ctx, key)
+		if err != nil {
+			return val, err
+		}
+		if ok {
+			if err = c.opt.Codec.Unmarshal(data, &val); err != nil {
+				return val, fmt.Errorf("layered: decode value of %s: %w", key, err)
+			}
+			c.fill(key, val, gen)
+			return val, nil
+		}
+		if c.opt.Loader == nil {
+			return val, ErrNotFound
+		}
+		if val, err = c.opt.Loader(ctx, key); err != nil {
+			return val, err
+		}
+		if err = c.setRemote(ctx, key, val); err != nil {
+			return val, err
+		}
+		c.fill(key, val, gen)
+		return val, nil
+	})
+}
+
+func (c *layeredCache[V]) Set(ctx context.Context, key string, val V) error {
+	if err := c.setRemote(ctx, key, val); err != nil {
+		return err
+	}
+	c.invalidate(key)
+	c.opt.Local.Set(key, val)
+	return c.publish(ctx, key)
+}
+
+func (c *layeredCache[V]) Delete(ctx context.Context, keys ...string) error {
+	if err := c.opt.Remote.Del(ctx, keys...); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		c.invalidate(key)
+	}
+	return c.publish(ctx, keys...)
+}
+
+func (c *layeredCache[V]) Close() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	if c.ownLocal != nil {
+		c.ownLocal.Close()
+	}
+}
+
+func (c *layeredCache[V]) setRemote(ctx context.Context, key string, val V) error {
+	data, err := c.opt.Codec.Marshal(val)
+	if err != nil {
+		return fmt.Errorf("layered: encode value of %s: %w", key, err)
+	}
+	return c.opt.Remote.Set(ctx, key, data, c.opt.RemoteTTL)
+}
+
+func (c *layeredCache[V]) publish(ctx context.Context, keys ...string) error {
+	if c.opt.Bus == nil {
+		return nil
+	}
+	msg, err := c.opt.Codec.Marshal(&invalidation{Origin: c.id, Keys: keys})
+	if err != nil {
+		return err
+	}
+	return c.opt.Bus.Publish(ctx, msg)
+}
+
+// onInvalidate delete keys invalidated by other replicas from L1
+func (c *layeredCache[V]) onInvalidate(msg []byte) {
+	var inv invalidation
+	if err := c.opt.Codec.Unmarshal(msg, &inv); err != nil || inv.Origin == c.id {
+		return
+	}
+	for _, key := range inv.Keys {
+		c.invalidate(key)
+	}
+}
+
+func (c *layeredCache[V]) generation(key string) *atomic.Uint64 {
+	return &c.gens[maphash.String(c.seed, key)%_generations]
+}
+
+// invalidate delete key from L1 and drop the in-flight load of key
+func (c *layeredCache[V]) invalidate(key string) {
+	c.generation(key).Add(1)
+	c.sfg.Forget(key)
+	c.opt.Local.Delete(key)
+}
+
+// fill set the value of key loaded at generation gen to L1, the value is
+// dropped if key has been invalidated since then.
+func (c *layeredCache[V]) fill(key string, val V, gen uint64) {
+	g := c.generation(key)
+	if g.Load() != gen {
+		return
+	}
+	c.opt.Local.Set(key, val)
+	// key may be invalidated between the check and Set
+	if g.Load() != gen {
+		c.opt.Local.Delete(key)
+	}
+}
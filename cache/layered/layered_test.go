@@ -0,0 +1,181 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package layered
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alimy/tryst/cache/asynccache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ctx = context.Background()
+
+func TestLayered(t *testing.T) {
+	var loaded atomic.Int32
+	remote, bus := NewMemoryRemote(), NewMemoryBus()
+	opt := Options[int]{
+		LocalTTL: time.Minute,
+		Remote:   remote,
+		Bus:      bus,
+		Loader: func(ctx context.Context, key string) (int, error) {
+			loaded.Add(1)
+			if key == "err" {
+				return 0, errors.New("error")
+			}
+			return len(key), nil
+		},
+	}
+	a, err := New(opt)
+	require.NoError(t, err)
+	defer a.Close()
+	b, err := New(opt)
+	require.NoError(t, err)
+	defer b.Close()
+
+	// a load the value and fill L2 for b
+	v, err := a.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v)
+	v, err = b.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, int32(1), loaded.Load())
+
+	// the set of a invalidate L1 of b
+	assert.NoError(t, a.Set(ctx, "key", 10))
+	v, err = b.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, 10, v)
+
+	assert.NoError(t, b.Delete(ctx, "key"))
+	_, ok, _ := remote.Get(ctx, "key")
+	assert.False(t, ok)
+	v, err = a.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, int32(2), loaded.Load())
+
+	_, err = a.Get(ctx, "err")
+	assert.Error(t, err)
+}
+
+func TestLayeredNotFound(t *testing.T) {
+	remote := NewMemoryRemote()
+	c, err := New(Options[string]{Remote: remote})
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, err = c.Get(ctx, "key")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// without bus, L1 is invalidated locally only
+	assert.NoError(t, remote.Set(ctx, "key", []byte(`"val"`), 0))
+	v, err := c.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, "val", v)
+
+	_, err = New(Options[string]{})
+	assert.Error(t, err)
+}
+
+// blockingRemote block the first Get after reading the value until release is closed
+type blockingRemote struct {
+	*MemoryRemote
+	once    sync.Once
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r *blockingRemote) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, ok, err := r.MemoryRemote.Get(ctx, key)
+	r.once.Do(func() {
+		close(r.started)
+		<-r.release
+	})
+	return data, ok, err
+}
+
+func TestLayeredStaleLoad(t *testing.T) {
+	remote := &blockingRemote{MemoryRemote: NewMemoryRemote(), started: make(chan struct{}), release: make(chan struct{})}
+	bus := NewMemoryBus()
+	a, err := New(Options[int]{Remote: remote, Bus: bus})
+	require.NoError(t, err)
+	defer a.Close()
+	b, err := New(Options[int]{Remote: remote.MemoryRemote, Bus: bus})
+	require.NoError(t, err)
+	defer b.Close()
+
+	require.NoError(t, remote.Set(ctx, "key", []byte("1"), 0))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		v, err := a.Get(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v)
+	}()
+	<-remote.started
+	// b invalidate the key while a is loading the old value
+	require.NoError(t, b.Set(ctx, "key", 2))
+	close(remote.release)
+	<-done
+
+	v, err := a.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestAsyncLocal(t *testing.T) {
+	local := asynccache.NewAsyncCache(asynccache.Options[string, int]{
+		RefreshDuration: time.Hour,
+		Fetcher: func(ctx context.Context, key string) (int, error) {
+			return 0, errors.New("not fetched")
+		},
+		MaxEntries: 16,
+	})
+	defer local.Close()
+	remote := NewMemoryRemote()
+	c, err := New(Options[int]{Local: AsyncLocal(local), Remote: remote})
+	require.NoError(t, err)
+	defer c.Close()
+
+	require.NoError(t, c.Set(ctx, "key", 1))
+	assert.Equal(t, map[string]int{"key": 1}, local.Dump())
+	// L1 hit does not read L2
+	require.NoError(t, remote.Del(ctx, "key"))
+	v, err := c.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	require.NoError(t, c.Delete(ctx, "key"))
+	assert.Empty(t, local.Dump())
+}
+
+func TestMemoryRemoteTTL(t *testing.T) {
+	r := NewMemoryRemote()
+	assert.NoError(t, r.Set(ctx, "key", []byte("val"), time.Millisecond))
+	time.Sleep(2 * time.Millisecond)
+	_, ok, err := r.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestMemoryBus(t *testing.T) {
+	bus := NewMemoryBus()
+	var got []string
+	cancel, err := bus.Subscribe(func(msg []byte) {
+		got = append(got, string(msg))
+	})
+	require.NoError(t, err)
+	assert.NoError(t, bus.Publish(ctx, []byte("a")))
+	cancel()
+	assert.NoError(t, bus.Publish(ctx, []byte("b")))
+	assert.Equal(t, []string{"a"}, got)
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package layered
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+type memoryValue struct {
+	data     []byte
+	expireAt time.Time
+}
+
+// MemoryRemote in-memory Remote, it's a stand-in of remote cache for tests
+type MemoryRemote struct {
+	mu   sync.RWMutex
+	data map[string]memoryValue
+}
+
+// NewMemoryRemote create a MemoryRemote instance
+func NewMemoryRemote() *MemoryRemote {
+	return &MemoryRemote{
+		data: make(map[string]memoryValue),
+	}
+}
+
+func (r *MemoryRemote) Get(_ context.Context, key string) ([]byte, bool, error) {
+	r.mu.RLock()
+	v, ok := r.data[key]
+	r.mu.RUnlock()
+	if !ok || (!v.expireAt.IsZero() && !time.Now().Before(v.expireAt)) {
+		return nil, false, nil
+	}
+	return slices.Clone(v.data), true, nil
+}
+
+func (r *MemoryRemote) Set(_ context.Context, key string, val []byte, ttl time.Duration) error {
+	v := memoryValue{data: slices.Clone(val)}
+	if ttl > 0 {
+		v.expireAt = time.Now().Add(ttl)
+	}
+	r.mu.Lock()
+	r.data[key] = v
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *MemoryRemote) Del(_ context.Context, keys ...string) error {
+	r.mu.Lock()
+	for _, key := range keys {
+		delete(r.data, key)
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+// MemoryBus in-memory Bus that deliver messages to subscribers synchronously,
+// it's a stand-in of pub/sub bus for tests
+type MemoryBus struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]func(msg []byte)
+}
+
+// NewMemoryBus create a MemoryBus instance
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{
+		handlers: make(map[int]func(msg []byte)),
+	}
+}
+
+func (b *MemoryBus) Publish(_ context.Context, msg []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.handlers {
+		handler(slices.Clone(msg))
+	}
+	return nil
+}
+
+func (b *MemoryBus) Subscribe(handler func(msg []byte)) (func(), error) {
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = handler
+	b.mu.Unlock()
+	return func() {
+		b.mu.Lock()
+		delete(b.handlers, id)
+		b.mu.Unlock()
+	}, nil
+}
@@ -0,0 +1,325 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"hash/maphash"
+	"math/bits"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/alimy/tryst/internal/singleflight"
+	"github.com/alimy/tryst/internal/wyhash"
+	"github.com/alimy/tryst/lang/fastrand"
+	"golang.org/x/sys/cpu"
+)
+
+// Cache[K, V] concurrent safe cache with per-entry ttl
+type Cache[K comparable, V any] interface {
+	// Get return the value of key if it's cached and not expired
+	Get(key K) (val V, ok bool)
+	// GetOrLoad return the value of key, or load it by load if it's not cached.
+	// Only one loading of a key is in-flight at a time, and it return ctx.Err()
+	// if ctx is done before loading completed but the loading continues.
+	GetOrLoad(ctx context.Context, key K, load func(ctx context.Context, key K) (V, error)) (V, error)
+	// Set cache the value of key with the default ttl
+	Set(key K, val V)
+	// SetWithTTL cache the value of key with ttl, zero ttl means never expire
+	SetWithTTL(key K, val V, ttl time.Duration)
+	// Delete delete the value of key
+	Delete(key K)
+	// Len return count of entries, include expired ones that not yet purged
+	Len() int
+	// Range call fn for every entry not expired until fn return false
+	Range(fn func(key K, val V) bool)
+	// Close stop the background goroutine that purge expired entries
+	Close()
+}
+
+// Options[K, V] options used to create Cache[K, V] instance
+type Options[K comparable, V any] struct {
+	// Shards count of lock-striped shards, rounded up to power of two,
+	// it's 4*GOMAXPROCS if not set.
+	Shards int
+	// TTL default ttl of entries, zero means never expire
+	TTL time.Duration
+	// TickInterval tick of the timing wheel that purge expired entries,
+	// it's 1 second if not set.
+	TickInterval time.Duration
+	// OnExpire called when an expired entry purged
+	OnExpire func(key K, val V)
+}
+
+const (
+	_wheelSlots = 512
+	_wheelMask  = _wheelSlots - 1
+)
+
+type item[V any] struct {
+	val V
+	// expireAt unix nano of expiry, zero means never expire
+	expireAt int64
+	// wheelTick tick of the timing wheel that the key scheduled
+	wheelTick int64
+}
+
+type shard[K comparable, V any] struct {
+	sync.RWMutex
+	items map[K]item[V]
+	// slots of timing wheel, key in it is checked when the tick come
+	slots [_wheelSlots]map[K]struct{}
+	// cursor last tick that the shard processed
+	cursor int64
+	_      cpu.CacheLinePad
+}
+
+// schedule put key into the slot of tick, it's a no-op if the key is
+// already in the slot.
+func (s *shard[K, V]) schedule(key K, tick int64) {
+	slot := tick & _wheelMask
+	if s.slots[slot] == nil {
+		s.slots[slot] = make(map[K]struct{})
+	}
+	s.slots[slot][key] = struct{}{}
+}
+
+type shardedCache[K comparable, V any] struct {
+	shards   []shard[K, V]
+	mask     uint64
+	hash     func(K) uint64
+	sfg      singleflight.Group[K, V]
+	ttl      time.Duration
+	tick     int64
+	cursor   atomic.Int64 // last tick that the wheel processed
+	onExpire func(key K, val V)
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewCache[K, V] create a new Cache[K, V] instance, shards are hashed by wyhash
+// and expired entries are purged by a timing wheel instead of per-key timers.
+func NewCache[K comparable, V any](opt Options[K, V]) Cache[K, V] {
+	if opt.Shards <= 0 {
+		opt.Shards = 4 * runtime.GOMAXPROCS(0)
+	}
+	if opt.TickInterval <= 0 {
+		opt.TickInterval = time.Second
+	}
+	if opt.TTL < 0 {
+		panic("cache: invalid TTL")
+	}
+	size := 1 << bits.Len(uint(opt.Shards-1))
+	c := &shardedCache[K, V]{
+		shards:   make([]shard[K, V], size),
+		mask:     uint64(size - 1),
+		hash:     newHasher[K](),
+		ttl:      opt.TTL,
+		tick:     int64(opt.TickInterval),
+		onExpire: opt.OnExpire,
+		stopCh:   make(chan struct{}),
+	}
+	cursor := time.Now().UnixNano() / c.tick
+	for i := range c.shards {
+		c.shards[i].items = make(map[K]item[V])
+		c.shards[i].cursor = cursor
+	}
+	c.cursor.Store(cursor)
+	go c.run(opt.TickInterval)
+	return c
+}
+
+// newHasher return hash function of K, key of string or fixed-size number is
+// hashed by wyhash, others are hashed by maphash.
+func newHasher[K comparable]() func(K) uint64 {
+	seed := fastrand.Uint64()
+	var zero K
+	switch any(zero).(type) {
+	case string:
+		return func(key K) uint64 {
+			return wyhash.Sum64StringWithSeed(*(*string)(unsafe.Pointer(&key)), seed)
+		}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr:
+		return func(key K) uint64 {
+			return wyhash.Sum64StringWithSeed(unsafe.String((*byte)(unsafe.Pointer(&key)), unsafe.Sizeof(key)), seed)
+		}
+	default:
+		mseed := maphash.MakeSeed()
+		return func(key K) uint64 {
+			return maphash.Comparable(mseed, key)
+		}
+	}
+}
+
+func (c *shardedCache[K, V]) shardOf(key K) *shard[K, V] {
+	return &c.shards[c.hash(key)&c.mask]
+}
+
+func (c *shardedCache[K, V]) Get(key K) (val V, ok bool) {
+	s := c.shardOf(key)
+	s.RLock()
+	it, ok := s.items[key]
+	s.RUnlock()
+	if !ok || (it.expireAt > 0 && it.expireAt <= time.Now().UnixNano()) {
+		return val, false
+	}
+	return it.val, true
+}
+
+func (c *shardedCache[K, V]) GetOrLoad(ctx context.Context, key K, load func(ctx context.Context, key K) (V, error)) (V, error) {
+	if val, ok := c.Get(key); ok {
+		return val, nil
+	}
+	return c.sfg.Do(ctx, key, func() (V, error) {
+		val, err := load(context.WithoutCancel(ctx), key)
+		if err == nil {
+			c.Set(key, val)
+		}
+		return val, err
+	})
+}
+
+func (c *shardedCache[K, V]) Set(key K, val V) {
+	c.SetWithTTL(key, val, c.ttl)
+}
+
+func (c *shardedCache[K, V]) SetWithTTL(key K, val V, ttl time.Duration) {
+	it := item[V]{val: val}
+	if ttl > 0 {
+		it.expireAt = time.Now().Add(ttl).UnixNano()
+	}
+	s := c.shardOf(key)
+	s.Lock()
+	if ttl > 0 {
+		// the tick is decided under the lock of shard, so the key is never
+		// put into a slot that the shard has just processed.
+		it.wheelTick = max(it.expireAt/c.tick, s.cursor+1)
+		s.schedule(key, it.wheelTick)
+	}
+	s.items[key] = it
+	s.Unlock()
+}
+
+func (c *shardedCache[K, V]) Delete(key K) {
+	s := c.shardOf(key)
+	s.Lock()
+	delete(s.items, key)
+	s.Unlock()
+}
+
+func (c *shardedCache[K, V]) Len() (n int) {
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.RLock()
+		n += len(s.items)
+		s.RUnlock()
+	}
+	return
+}
+
+func (c *shardedCache[K, V]) Range(fn func(key K, val V) bool) {
+	type kv struct {
+		key K
+		val V
+	}
+	var entries []kv
+	for i := range c.shards {
+		s := &c.shards[i]
+		now := time.Now().UnixNano()
+		entries = entries[:0]
+		s.RLock()
+		for k, it := range s.items {
+			if it.expireAt == 0 || it.expireAt > now {
+				entries = append(entries, kv{k, it.val})
+			}
+		}
+		s.RUnlock()
+		// call fn without lock so that fn could modify the cache
+		for _, e := range entries {
+			if !fn(e.key, e.val) {
+				return
+			}
+		}
+	}
+}
+
+func (c *shardedCache[K, V]) Close() {
+	c.stopOnce.Do(func() {
+		close(c.stopCh)
+	})
+}
+
+func (c *shardedCache[K, V]) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			c.advance(now.UnixNano())
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// advance process the slots of ticks until now
+func (c *shardedCache[K, V]) advance(now int64) {
+	target, cursor := now/c.tick, c.cursor.Load()
+	// every slot is processed once at most if the wheel lagged a full round
+	start := max(cursor+1, target-_wheelSlots+1)
+	for t := start; t <= target; t++ {
+		for i := range c.shards {
+			c.expireSlot(&c.shards[i], t, now)
+		}
+		c.cursor.Store(t)
+	}
+}
+
+// expireSlot purge expired keys in slot of tick t, and drop stale keys that
+// rescheduled to other slot.
+func (c *shardedCache[K, V]) expireSlot(s *shard[K, V], t, now int64) {
+	type kv struct {
+		key K
+		val V
+	}
+	var expired []kv
+	slot := t & _wheelMask
+	s.Lock()
+	keys := s.slots[slot]
+	for key := range keys {
+		it, exist := s.items[key]
+		switch {
+		case !exist || it.expireAt == 0 || it.wheelTick&_wheelMask != slot:
+			// stale key
+			delete(keys, key)
+		case it.expireAt <= now:
+			delete(keys, key)
+			delete(s.items, key)
+			expired = append(expired, kv{key, it.val})
+		case it.wheelTick > t:
+			// wait for a later round
+		default:
+			// not yet expired in the tick it's scheduled
+			delete(keys, key)
+			it.wheelTick = t + 1
+			s.items[key] = it
+			s.schedule(key, it.wheelTick)
+		}
+	}
+	if len(keys) == 0 {
+		// release the memory of drained slot
+		s.slots[slot] = nil
+	}
+	s.cursor = t
+	s.Unlock()
+	if c.onExpire != nil {
+		for _, e := range expired {
+			c.onExpire(e.key, e.val)
+		}
+	}
+}
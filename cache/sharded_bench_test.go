@@ -0,0 +1,122 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alimy/tryst/lang/fastrand"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+const benchKeys = 1 << 16
+
+var benchKeyStrs = func() []string {
+	keys := make([]string, benchKeys)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	return keys
+}()
+
+func BenchmarkGet(b *testing.B) {
+	b.Run("cache", func(b *testing.B) {
+		c := NewCache(Options[string, int]{TTL: time.Hour})
+		defer c.Close()
+		for i, key := range benchKeyStrs {
+			c.Set(key, i)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				c.Get(benchKeyStrs[fastrand.Uint32n(benchKeys)])
+			}
+		})
+	})
+	b.Run("sync.Map", func(b *testing.B) {
+		var m sync.Map
+		for i, key := range benchKeyStrs {
+			m.Store(key, i)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				m.Load(benchKeyStrs[fastrand.Uint32n(benchKeys)])
+			}
+		})
+	})
+	b.Run("lru", func(b *testing.B) {
+		l, _ := lru.New[string, int](benchKeys)
+		for i, key := range benchKeyStrs {
+			l.Add(key, i)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				l.Get(benchKeyStrs[fastrand.Uint32n(benchKeys)])
+			}
+		})
+	})
+}
+
+func BenchmarkSet(b *testing.B) {
+	b.Run("cache", func(b *testing.B) {
+		c := NewCache(Options[string, int]{TTL: time.Hour})
+		defer c.Close()
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				c.Set(benchKeyStrs[fastrand.Uint32n(benchKeys)], 0)
+			}
+		})
+	})
+	b.Run("sync.Map", func(b *testing.B) {
+		var m sync.Map
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				m.Store(benchKeyStrs[fastrand.Uint32n(benchKeys)], 0)
+			}
+		})
+	})
+	b.Run("lru", func(b *testing.B) {
+		l, _ := lru.New[string, int](benchKeys)
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				l.Add(benchKeyStrs[fastrand.Uint32n(benchKeys)], 0)
+			}
+		})
+	})
+}
+
+func BenchmarkMixed(b *testing.B) {
+	c := NewCache(Options[string, int]{TTL: time.Hour})
+	defer c.Close()
+	for i, key := range benchKeyStrs {
+		c.Set(key, i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			key := benchKeyStrs[fastrand.Uint32n(benchKeys)]
+			if fastrand.Uint32n(10) == 0 {
+				c.Set(key, 0)
+			} else {
+				c.Get(key)
+			}
+		}
+	})
+}
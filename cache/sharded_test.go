@@ -0,0 +1,188 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	c := NewCache(Options[string, int]{Shards: 3})
+	defer c.Close()
+	assert.Len(t, c.(*shardedCache[string, int]).shards, 4)
+
+	for i := range 100 {
+		c.Set(strconv.Itoa(i), i)
+	}
+	assert.Equal(t, 100, c.Len())
+	for i := range 100 {
+		v, ok := c.Get(strconv.Itoa(i))
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	c.Delete("1")
+	_, ok := c.Get("1")
+	assert.False(t, ok)
+
+	n := 0
+	c.Range(func(key string, val int) bool {
+		assert.Equal(t, strconv.Itoa(val), key)
+		n++
+		return true
+	})
+	assert.Equal(t, 99, n)
+}
+
+func TestCacheTTL(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		expired []int
+	)
+	c := NewCache(Options[int, int]{
+		TTL:          20 * time.Millisecond,
+		TickInterval: 5 * time.Millisecond,
+		OnExpire: func(key, val int) {
+			mu.Lock()
+			expired = append(expired, key)
+			mu.Unlock()
+		},
+	})
+	defer c.Close()
+
+	c.Set(1, 1)
+	c.SetWithTTL(2, 2, time.Hour)
+	c.SetWithTTL(3, 3, 0)
+	// reset ttl of key 4 repeatedly
+	for range 3 {
+		c.Set(4, 4)
+	}
+	_, ok := c.Get(1)
+	assert.True(t, ok)
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(expired) == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.ElementsMatch(t, []int{1, 4}, expired)
+	_, ok = c.Get(1)
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestCacheExpireOnGet(t *testing.T) {
+	c := NewCache(Options[string, int]{TickInterval: time.Hour})
+	defer c.Close()
+
+	c.SetWithTTL("key", 1, time.Millisecond)
+	time.Sleep(2 * time.Millisecond)
+	_, ok := c.Get("key")
+	assert.False(t, ok)
+	c.Range(func(string, int) bool {
+		t.Fatal("expired entry ranged")
+		return false
+	})
+}
+
+func TestCacheGetOrLoad(t *testing.T) {
+	var loaded atomic.Int32
+	c := NewCache(Options[string, string]{})
+	defer c.Close()
+	load := func(ctx context.Context, key string) (string, error) {
+		loaded.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		if key == "err" {
+			return "", errors.New("error")
+		}
+		return key, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := c.GetOrLoad(context.Background(), "key", load)
+			assert.NoError(t, err)
+			assert.Equal(t, "key", v)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), loaded.Load())
+
+	_, err := c.GetOrLoad(context.Background(), "err", load)
+	assert.Error(t, err)
+	_, ok := c.Get("err")
+	assert.False(t, ok)
+}
+
+func TestCacheGetAllocs(t *testing.T) {
+	s := NewCache(Options[string, int]{})
+	defer s.Close()
+	s.SetWithTTL("key", 1, time.Hour)
+	i := NewCache(Options[int64, int]{})
+	defer i.Close()
+	i.Set(1, 1)
+
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		s.Get("key")
+		i.Get(1)
+	}))
+}
+
+func TestCacheConcurrentExpire(t *testing.T) {
+	var expired atomic.Int32
+	c := NewCache(Options[int, int]{
+		Shards:       2,
+		TickInterval: time.Millisecond,
+		OnExpire: func(key, val int) {
+			expired.Add(1)
+		},
+	})
+	defer c.Close()
+
+	// set keys while the wheel advances, a key put into a slot that has just
+	// been processed would wait a full round of the wheel
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 2000 {
+				key := g*2000 + i%200
+				c.SetWithTTL(key, i, time.Duration(1+i%3)*time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Eventually(t, func() bool {
+		return c.Len() == 0
+	}, 200*time.Millisecond, time.Millisecond)
+	// a key may expire more than once if it expired before set again
+	assert.GreaterOrEqual(t, expired.Load(), int32(800))
+}
+
+func TestCacheRescheduleOnce(t *testing.T) {
+	c := NewCache(Options[string, int]{Shards: 1, TickInterval: time.Hour}).(*shardedCache[string, int])
+	defer c.Close()
+
+	// rescheduled A -> B -> A, the key is put into slot A once
+	c.SetWithTTL("key", 1, 2*time.Hour)
+	c.SetWithTTL("key", 1, 5*time.Hour)
+	c.SetWithTTL("key", 1, 2*time.Hour)
+	n := 0
+	for _, slot := range c.shards[0].slots {
+		n += len(slot)
+	}
+	assert.Equal(t, 2, n)
+}
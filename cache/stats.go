@@ -0,0 +1,134 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// MetricsHook hook cache events, it's called synchronously so it should be fast
+type MetricsHook interface {
+	OnHit()
+	OnMiss()
+	// OnFetch called after every fetching of value, include refreshing
+	OnFetch(latency time.Duration, err error)
+	// OnRefresh called after a fetching triggered by refreshing
+	OnRefresh()
+	OnEvict()
+}
+
+// StatsProvider provide statistics of a cache, KeyPool instances created by
+// NewKeyPool and asynccache.AsyncCache implement it.
+//
+//	if sp, ok := pool.(cache.StatsProvider); ok {
+//		stats := sp.Stats()
+//	}
+type StatsProvider interface {
+	// Stats return snapshot of the cache statistics
+	Stats() Stats
+}
+
+// Stats snapshot of cache statistics
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Fetches     uint64
+	FetchErrors uint64
+	// FetchTime total latency of fetches
+	FetchTime time.Duration
+	Refreshes uint64
+	Evictions uint64
+	Entries   int
+}
+
+// HitRatio return ratio of hits in all lookups
+func (s Stats) HitRatio() float64 {
+	if total := s.Hits + s.Misses; total > 0 {
+		return float64(s.Hits) / float64(total)
+	}
+	return 0
+}
+
+// AvgFetchLatency return average latency of fetches
+func (s Stats) AvgFetchLatency() time.Duration {
+	if s.Fetches > 0 {
+		return s.FetchTime / time.Duration(s.Fetches)
+	}
+	return 0
+}
+
+// StatsRecorder count cache events and forward them to an optional hook, it's
+// a MetricsHook that safe for concurrent use.
+type StatsRecorder struct {
+	hook        MetricsHook
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	fetches     atomic.Uint64
+	fetchErrors atomic.Uint64
+	fetchTime   atomic.Int64
+	refreshes   atomic.Uint64
+	evictions   atomic.Uint64
+}
+
+// NewStatsRecorder create a StatsRecorder instance, hook could be nil
+func NewStatsRecorder(hook MetricsHook) *StatsRecorder {
+	return &StatsRecorder{
+		hook: hook,
+	}
+}
+
+func (r *StatsRecorder) OnHit() {
+	r.hits.Add(1)
+	if r.hook != nil {
+		r.hook.OnHit()
+	}
+}
+
+func (r *StatsRecorder) OnMiss() {
+	r.misses.Add(1)
+	if r.hook != nil {
+		r.hook.OnMiss()
+	}
+}
+
+func (r *StatsRecorder) OnFetch(latency time.Duration, err error) {
+	r.fetches.Add(1)
+	r.fetchTime.Add(int64(latency))
+	if err != nil {
+		r.fetchErrors.Add(1)
+	}
+	if r.hook != nil {
+		r.hook.OnFetch(latency, err)
+	}
+}
+
+func (r *StatsRecorder) OnRefresh() {
+	r.refreshes.Add(1)
+	if r.hook != nil {
+		r.hook.OnRefresh()
+	}
+}
+
+func (r *StatsRecorder) OnEvict() {
+	r.evictions.Add(1)
+	if r.hook != nil {
+		r.hook.OnEvict()
+	}
+}
+
+// Stats return snapshot of the counted statistics with entries count
+func (r *StatsRecorder) Stats(entries int) Stats {
+	return Stats{
+		Hits:        r.hits.Load(),
+		Misses:      r.misses.Load(),
+		Fetches:     r.fetches.Load(),
+		FetchErrors: r.fetchErrors.Load(),
+		FetchTime:   time.Duration(r.fetchTime.Load()),
+		Refreshes:   r.refreshes.Load(),
+		Evictions:   r.evictions.Load(),
+		Entries:     entries,
+	}
+}
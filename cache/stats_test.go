@@ -0,0 +1,49 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countHook struct {
+	hits, misses, fetches, evictions int
+}
+
+func (h *countHook) OnHit()                       { h.hits++ }
+func (h *countHook) OnMiss()                      { h.misses++ }
+func (h *countHook) OnFetch(time.Duration, error) { h.fetches++ }
+func (h *countHook) OnRefresh()                   {}
+func (h *countHook) OnEvict()                     { h.evictions++ }
+
+func TestKeyPoolStats(t *testing.T) {
+	hook := &countHook{}
+	p := MustKeyPool(2, strconv.Itoa, WithMetricsHook(hook))
+	for _, key := range []int{1, 2, 1, 3, 1} {
+		assert.Equal(t, strconv.Itoa(key), p.Get(key))
+	}
+	sp, ok := p.(StatsProvider)
+	if !ok {
+		t.Fatalf("KeyPool want StatsProvider")
+	}
+	stats := sp.Stats()
+	assert.Equal(t, Stats{
+		Hits:      2,
+		Misses:    3,
+		Fetches:   3,
+		FetchTime: stats.FetchTime,
+		Evictions: 1,
+		Entries:   2,
+	}, stats)
+	assert.InDelta(t, 0.4, stats.HitRatio(), 1e-9)
+	assert.Equal(t, &countHook{hits: 2, misses: 3, fetches: 3, evictions: 1}, hook)
+
+	_, err := NewKeyPool(0, strconv.Itoa)
+	assert.Error(t, err)
+}
@@ -0,0 +1,260 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cfg
+
+import (
+	"cmp"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	_durationType        = reflect.TypeFor[time.Duration]()
+	_textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// Bind bind kv entries to the fields of struct that v point to, fields are
+// tagged like below:
+//
+//	type Config struct {
+//		Addr    string        `cfg:"addr" default:":8080"`
+//		Timeout time.Duration `cfg:"timeout" default:"3s" validate:"min=1s,max=1m"`
+//		Hosts   []string      `cfg:"hosts" validate:"required,min=1"`
+//		Mode    string        `validate:"oneof=debug release"`
+//		DB      struct {
+//			MaxConns int `cfg:"max_conns" default:"10"`
+//		} `cfg:"db"`
+//	}
+//
+// The key of a field is its cfg tag or its name, keys are case-insensitive and
+// the key of a nested struct field is prefixed with the key of the struct and
+// a dot, like db.max_conns. Fields of embedded struct without cfg tag have no
+// prefix, and fields tagged with cfg:"-" are skipped. Supported types are
+// string, bool, numbers, time.Duration, encoding.TextUnmarshaler, pointers of
+// them and slices of them that the value is comma separated.
+//
+// The default value is used if the key is not in kv, and the field is kept
+// unchanged if neither is set. The validate tag is a comma separated list of
+// required, min=n, max=n and oneof=a b, min and max limit the length of string
+// and slice or the value of others. All errors are reported joined.
+func (f *Features) Bind(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cfg: Bind want a non-nil pointer to struct but got %T", v)
+	}
+	return errors.Join(bindStruct(rv.Elem(), "", f.snapshot.Load().kv)...)
+}
+
+func bindStruct(rv reflect.Value, prefix string, kv map[string]string) (errs []error) {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		sf := rt.Field(i)
+		name, tagged := sf.Tag.Lookup("cfg")
+		if name == "-" || !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+		fv := rv.Field(i)
+		if isNested(sf.Type) {
+			if sf.Type.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					if !fv.CanSet() {
+						continue
+					}
+					fv.Set(reflect.New(sf.Type.Elem()))
+				}
+				fv = fv.Elem()
+			}
+			switch {
+			case sf.Anonymous && !tagged:
+				errs = append(errs, bindStruct(fv, prefix, kv)...)
+			case sf.IsExported():
+				if !tagged {
+					name = sf.Name
+				}
+				errs = append(errs, bindStruct(fv, prefix+strings.ToLower(name)+".", kv)...)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if !tagged {
+			name = sf.Name
+		}
+		key := prefix + strings.ToLower(name)
+		if err := bindField(fv, key, sf.Tag, kv); err != nil {
+			errs = append(errs, fmt.Errorf("cfg: bind %s: %w", key, err))
+		}
+	}
+	return
+}
+
+// isNested return true if t is a struct or pointer to struct that bind field
+// by field
+func isNested(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(_textUnmarshalerType)
+}
+
+func bindField(fv reflect.Value, key string, tag reflect.StructTag, kv map[string]string) error {
+	var required bool
+	var rules []string
+	for _, rule := range strings.Split(tag.Get("validate"), ",") {
+		switch rule = strings.TrimSpace(rule); rule {
+		case "":
+		case "required":
+			required = true
+		default:
+			rules = append(rules, rule)
+		}
+	}
+	value, ok := kv[key]
+	if !ok {
+		value, ok = tag.Lookup("default")
+	}
+	if !ok {
+		if required {
+			return errors.New("required but not set")
+		}
+		return nil
+	}
+	if err := setValue(fv, value); err != nil {
+		return err
+	}
+	var errs []error
+	for _, rule := range rules {
+		if err := validate(fv, rule); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func setValue(fv reflect.Value, value string) error {
+	if fv.Kind() != reflect.Pointer && reflect.PointerTo(fv.Type()).Implements(_textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+	if fv.Type() == _durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 0, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(value, 0, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	case reflect.Pointer:
+		elem := reflect.New(fv.Type().Elem())
+		if err := setValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		fv.Set(elem)
+	case reflect.Slice:
+		items := splitList(value)
+		res := reflect.MakeSlice(fv.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(res.Index(i), item); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		fv.Set(res)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+	return nil
+}
+
+func validate(fv reflect.Value, rule string) error {
+	name, arg, _ := strings.Cut(rule, "=")
+	if fv.Kind() == reflect.Pointer {
+		fv = fv.Elem()
+	}
+	switch name {
+	case "min", "max":
+		res, err := compareTo(fv, arg)
+		if err != nil {
+			return fmt.Errorf("invalid rule %s: %w", rule, err)
+		}
+		if name == "min" && res < 0 {
+			return fmt.Errorf("%v is less than %s", describe(fv), arg)
+		}
+		if name == "max" && res > 0 {
+			return fmt.Errorf("%v is greater than %s", describe(fv), arg)
+		}
+	case "oneof":
+		v := fmt.Sprint(fv.Interface())
+		if !slices.Contains(strings.Fields(arg), v) {
+			return fmt.Errorf("%q is not one of %s", v, arg)
+		}
+	default:
+		return fmt.Errorf("unknown rule %s", rule)
+	}
+	return nil
+}
+
+// compareTo compare the length of string and slice or the value of others
+// with bound
+func compareTo(fv reflect.Value, bound string) (int, error) {
+	switch fv.Kind() {
+	case reflect.String, reflect.Slice:
+		n, err := strconv.Atoi(bound)
+		return cmp.Compare(fv.Len(), n), err
+	}
+	b := reflect.New(fv.Type()).Elem()
+	if err := setValue(b, bound); err != nil {
+		return 0, err
+	}
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(fv.Int(), b.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(fv.Uint(), b.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(fv.Float(), b.Float()), nil
+	}
+	return 0, fmt.Errorf("not supported by %s", fv.Type())
+}
+
+func describe(fv reflect.Value) string {
+	switch fv.Kind() {
+	case reflect.String, reflect.Slice:
+		return "length " + strconv.Itoa(fv.Len())
+	}
+	return fmt.Sprint(fv.Interface())
+}
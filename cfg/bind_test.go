@@ -0,0 +1,102 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cfg
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindLog struct {
+	Level string `cfg:"level" default:"info" validate:"oneof=debug info warn"`
+}
+
+type bindConfig struct {
+	bindLog
+	Addr     string        `cfg:"addr" default:":8080"`
+	Debug    bool          `cfg:"debug"`
+	Timeout  time.Duration `cfg:"timeout" default:"3s" validate:"min=1s,max=1m"`
+	Hosts    []string      `cfg:"hosts" validate:"required,min=1"`
+	Ports    []uint16      `cfg:"ports"`
+	Ratio    float64       `cfg:"ratio"`
+	IP       netip.Addr    `cfg:"ip"`
+	Retry    *int          `cfg:"retry"`
+	Name     string
+	Ignored  string `cfg:"-"`
+	internal string
+	DB       struct {
+		MaxConns int `cfg:"max_conns" default:"10" validate:"min=1"`
+	} `cfg:"db"`
+	Cache *struct {
+		Size int `cfg:"size"`
+	}
+}
+
+func TestBind(t *testing.T) {
+	f := NewFeatures(nil, map[string]string{
+		"debug":         "true",
+		"hosts":         "a.com, b.com",
+		"ports":         "80,443",
+		"ratio":         "0.5",
+		"ip":            "10.0.0.1",
+		"retry":         "3",
+		"name":          "tryst",
+		"ignored":       "ignored",
+		"internal":      "internal",
+		"level":         "debug",
+		"db.max_conns":  "20",
+		"cache.size":    "1024",
+		"timeout":       "5s",
+		"unknown.field": "unknown",
+	})
+	c := bindConfig{Ignored: "kept"}
+	if err := f.Bind(&c); err != nil {
+		t.Fatalf("want no error but got %s", err)
+	}
+	if c.Addr != ":8080" || !c.Debug || c.Timeout != 5*time.Second || c.Ratio != 0.5 ||
+		c.Name != "tryst" || c.Ignored != "kept" || c.internal != "" || c.Level != "debug" {
+		t.Errorf("want scalar fields bound but got %+v", c)
+	}
+	if !slices.Equal(c.Hosts, []string{"a.com", "b.com"}) || !slices.Equal(c.Ports, []uint16{80, 443}) {
+		t.Errorf("want slices bound but got %v %v", c.Hosts, c.Ports)
+	}
+	if c.IP != netip.MustParseAddr("10.0.0.1") || c.Retry == nil || *c.Retry != 3 {
+		t.Errorf("want TextUnmarshaler and pointer bound but got %v %v", c.IP, c.Retry)
+	}
+	if c.DB.MaxConns != 20 || c.Cache == nil || c.Cache.Size != 1024 {
+		t.Errorf("want nested structs bound but got %+v %+v", c.DB, c.Cache)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	f := NewFeatures(nil, map[string]string{
+		"debug":        "yes",
+		"ports":        "80,http",
+		"timeout":      "2m",
+		"level":        "trace",
+		"db.max_conns": "0",
+	})
+	var c bindConfig
+	err := f.Bind(&c)
+	if err == nil {
+		t.Fatal("want errors but not")
+	}
+	for _, key := range []string{"debug", "ports", "timeout", "hosts", "level", "db.max_conns"} {
+		if !strings.Contains(err.Error(), "cfg: bind "+key+":") {
+			t.Errorf("want error of %s but got %s", key, err)
+		}
+	}
+	if c.Addr != ":8080" {
+		t.Errorf("want valid fields bound despite errors but got %q", c.Addr)
+	}
+	for _, v := range []any{nil, c, new(int)} {
+		if err := f.Bind(v); err == nil {
+			t.Errorf("want error of Bind(%T) but not", v)
+		}
+	}
+}
@@ -39,23 +39,33 @@ var (
 
 	// Not alias of Features.CfgNot func
 	Not = _features.CfgNot
+
+	// Reload alias of Features.Reload func
+	Reload = _features.Reload
+
+	// ReloadSource alias of Features.ReloadSource func
+	ReloadSource = _features.ReloadSource
+
+	// PollSource alias of Features.PollSource func
+	PollSource = _features.PollSource
+
+	// WatchFile alias of Features.WatchFile func
+	WatchFile = _features.WatchFile
+
+	// Watch alias of Features.Watch func
+	Watch = _features.Watch
+
+	// IfFor alias of Features.IfFor func
+	IfFor = _features.IfFor
+
+	// SetRules alias of Features.SetRules func
+	SetRules = _features.SetRules
+
+	// Bind alias of Features.Bind func
+	Bind = _features.Bind
 )
 
-// Initial initialize features in cfg pkg
+// Initial initialize features in cfg pkg, the default suite is used
 func Initial(suites map[string][]string, kv map[string]string) {
-	_features = NewFeatures(suites, kv)
-	{
-		// must re-assign variable below
-		Use = _features.Use
-		UseDefault = _features.UseDefault
-		Val = _features.Cfg
-		As = _features.CfgAs
-		If = _features.CfgIf
-		All = _features.CfgAll
-		Any = _features.CfgAny
-		In = _features.CfgIn
-		On = _features.CfgOn
-		Be = _features.CfgBe
-		Not = _features.CfgNot
-	}
+	_features.reset(suites, kv)
 }
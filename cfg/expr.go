@@ -0,0 +1,501 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cfg
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// _exprCacheSize max count of cached expressions
+const _exprCacheSize = 1024
+
+// Expr compiled boolean expression of features, the grammar is:
+//
+//	expr       = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | "(" expr ")" | comparison
+//	comparison = key [ op value | "in" "(" value { "," value } ")" ]
+//	op         = "=" | "==" | "!=" | "<" | "<=" | ">" | ">="
+//
+// A bare key is true if the feature is enabled, keys are case-insensitive and
+// values are case-sensitive. Values that contain spaces or operators could be
+// quoted by double or single quotes. `key != value` is the negation of
+// `key = value`, and the ordering operators compare the value of the feature
+// with value as versions like v1.2.3-beta.1, they are false if the feature is
+// disabled or its value is not a version. An example is
+// `Redis && (Sms = SmsJuhe || !Alipay)`.
+//
+// For compatibility, `key = value` that does not parse is compared like the
+// legacy CfgIf, the value is all the text after `=` with spaces trimmed, like
+// `Sms = Sms Juhe`, or `Sms = ` that is true if Sms is enabled without value.
+type Expr struct {
+	src  string
+	root node
+}
+
+var (
+	// _exprs cache of compiled expressions, it's read without lock on the hot
+	// path of CfgIf, and cleared once it's full. Invalid expressions are not
+	// cached.
+	_exprs sync.Map // map[string]*Expr
+	// _exprCount count of cached expressions, approximately
+	_exprCount atomic.Int64
+)
+
+// Compile parse the expression, the compiled expressions are cached so compile
+// an expression again is cheap.
+func Compile(expression string) (*Expr, error) {
+	if expr, ok := _exprs.Load(expression); ok {
+		return expr.(*Expr), nil
+	}
+	expr, err := parseExpr(expression)
+	if err != nil {
+		var ok bool
+		if expr, ok = parseLegacy(expression); !ok {
+			return nil, err
+		}
+	}
+	if _exprCount.Load() >= _exprCacheSize {
+		// bound the cache if expressions are built dynamically
+		_exprs.Clear()
+		_exprCount.Store(0)
+	}
+	if _, loaded := _exprs.LoadOrStore(expression, expr); !loaded {
+		_exprCount.Add(1)
+	}
+	return expr, nil
+}
+
+// parseLegacy parse `key = value` like the legacy CfgIf that split expression
+// by `=`, key must be a single word and value must not be quoted. An empty
+// value matches a feature enabled without value.
+func parseLegacy(src string) (*Expr, bool) {
+	kv := strings.Split(src, "=")
+	if len(kv) != 2 {
+		return nil, false
+	}
+	key, val := strings.Trim(strings.ToLower(kv[0]), " "), strings.Trim(kv[1], " ")
+	if len(key) == 0 || strings.HasPrefix(val, `"`) || strings.HasPrefix(val, "'") || strings.IndexFunc(key, func(r rune) bool {
+		return r < 0x80 && isDelimiter(byte(r))
+	}) >= 0 {
+		return nil, false
+	}
+	return &Expr{src: src, root: eqNode{key, val}}, true
+}
+
+// MustCompile like Compile but panic if the expression is invalid
+func MustCompile(expression string) *Expr {
+	expr, err := Compile(expression)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
+
+// String return the source of the expression
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Eval evaluate the expression with the features
+func (e *Expr) Eval(f *Features) bool {
+	return e.root.eval(f.snapshot.Load().features)
+}
+
+type node interface {
+	eval(features map[string]string) bool
+}
+
+type (
+	orNode  []node
+	andNode []node
+	notNode struct{ x node }
+	// keyNode enabled check of key
+	keyNode struct{ key string }
+	eqNode  struct{ key, val string }
+	inNode  struct {
+		key  string
+		vals []string
+	}
+	cmpNode struct {
+		key string
+		op  string
+		ver version
+	}
+)
+
+func (n orNode) eval(features map[string]string) bool {
+	for _, x := range n {
+		if x.eval(features) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n andNode) eval(features map[string]string) bool {
+	for _, x := range n {
+		if !x.eval(features) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n notNode) eval(features map[string]string) bool {
+	return !n.x.eval(features)
+}
+
+func (n keyNode) eval(features map[string]string) bool {
+	_, ok := features[n.key]
+	return ok
+}
+
+func (n eqNode) eval(features map[string]string) bool {
+	v, ok := features[n.key]
+	return ok && v == n.val
+}
+
+func (n inNode) eval(features map[string]string) bool {
+	v, ok := features[n.key]
+	if !ok {
+		return false
+	}
+	for _, val := range n.vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+func (n cmpNode) eval(features map[string]string) bool {
+	v, ok := features[n.key]
+	if !ok {
+		return false
+	}
+	ver, ok := parseVersion(v)
+	if !ok {
+		return false
+	}
+	res := ver.compare(n.ver)
+	switch n.op {
+	case "<":
+		return res < 0
+	case "<=":
+		return res <= 0
+	case ">":
+		return res > 0
+	default:
+		return res >= 0
+	}
+}
+
+type tokenKind uint8
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type parser struct {
+	src    string
+	tokens []token
+	idx    int
+}
+
+func parseExpr(src string) (*Expr, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, tokens: tokens}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("cfg: invalid expression %q: unterminated string at %d", src, i)
+			}
+			tokens = append(tokens, token{tokString, src[i+1 : i+1+end], i})
+			i += end + 2
+		case strings.HasPrefix(src[i:], "&&"), strings.HasPrefix(src[i:], "||"),
+			strings.HasPrefix(src[i:], "=="), strings.HasPrefix(src[i:], "!="),
+			strings.HasPrefix(src[i:], "<="), strings.HasPrefix(src[i:], ">="):
+			tokens = append(tokens, token{tokOp, src[i : i+2], i})
+			i += 2
+		case strings.IndexByte("()!=<>,", c) >= 0:
+			tokens = append(tokens, token{tokOp, src[i : i+1], i})
+			i++
+		case c == '&' || c == '|':
+			return nil, fmt.Errorf("cfg: invalid expression %q: unexpected %q at %d", src, c, i)
+		default:
+			start := i
+			for i < len(src) && !isDelimiter(src[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokWord, src[start:i], start})
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+func isDelimiter(c byte) bool {
+	return strings.IndexByte(" \t\n\r\"'&|()!=<>,", c) >= 0
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.idx]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.idx]
+	if t.kind != tokEOF {
+		p.idx++
+	}
+	return t
+}
+
+// accept consume the next token if it's the operator op
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokOp && t.text == op {
+		p.idx++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	if t.kind == tokEOF {
+		return fmt.Errorf("cfg: invalid expression %q: unexpected end", p.src)
+	}
+	return fmt.Errorf("cfg: invalid expression %q: %s at %d", p.src, fmt.Sprintf(format, args...), t.pos)
+}
+
+func (p *parser) or() (node, error) {
+	x, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	res := orNode{x}
+	for p.accept("||") {
+		if x, err = p.and(); err != nil {
+			return nil, err
+		}
+		res = append(res, x)
+	}
+	if len(res) == 1 {
+		return res[0], nil
+	}
+	return res, nil
+}
+
+func (p *parser) and() (node, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	res := andNode{x}
+	for p.accept("&&") {
+		if x, err = p.unary(); err != nil {
+			return nil, err
+		}
+		res = append(res, x)
+	}
+	if len(res) == 1 {
+		return res[0], nil
+	}
+	return res, nil
+}
+
+func (p *parser) unary() (node, error) {
+	switch {
+	case p.accept("!"):
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x}, nil
+	case p.accept("("):
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.peek(); !p.accept(")") {
+			return nil, p.errorf(t, "want ) but got %q", t.text)
+		}
+		return x, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (node, error) {
+	t := p.next()
+	if t.kind != tokWord {
+		return nil, p.errorf(t, "want feature but got %q", t.text)
+	}
+	key := strings.ToLower(t.text)
+	if t = p.peek(); t.kind == tokWord && t.text == "in" {
+		p.idx++
+		return p.in(key)
+	}
+	if t.kind != tokOp {
+		return keyNode{key}, nil
+	}
+	switch op := t.text; op {
+	case "=", "==", "!=":
+		p.idx++
+		val, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if op == "!=" {
+			return notNode{eqNode{key, val}}, nil
+		}
+		return eqNode{key, val}, nil
+	case "<", "<=", ">", ">=":
+		p.idx++
+		vt := p.peek()
+		val, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		ver, ok := parseVersion(val)
+		if !ok {
+			return nil, p.errorf(vt, "invalid version %q", val)
+		}
+		return cmpNode{key, op, ver}, nil
+	}
+	return keyNode{key}, nil
+}
+
+func (p *parser) in(key string) (node, error) {
+	if t := p.peek(); !p.accept("(") {
+		return nil, p.errorf(t, "want ( but got %q", t.text)
+	}
+	n := inNode{key: key}
+	for {
+		val, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		n.vals = append(n.vals, val)
+		if p.accept(")") {
+			return n, nil
+		}
+		if t := p.peek(); !p.accept(",") {
+			return nil, p.errorf(t, "want , or ) but got %q", t.text)
+		}
+	}
+}
+
+func (p *parser) value() (string, error) {
+	t := p.next()
+	if t.kind != tokWord && t.kind != tokString {
+		return "", p.errorf(t, "want value but got %q", t.text)
+	}
+	return t.text, nil
+}
+
+// version semantic version like v1.2.3-beta.1+build, count of the numeric
+// parts is not limited and the missing parts are zero.
+type version struct {
+	nums []uint64
+	pre  []string
+}
+
+func parseVersion(s string) (v version, ok bool) {
+	s = strings.TrimPrefix(s, "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, pre, hasPre := strings.Cut(s, "-")
+	if hasPre {
+		if v.pre = strings.Split(pre, "."); len(pre) == 0 {
+			return v, false
+		}
+	}
+	for _, part := range strings.Split(s, ".") {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return v, false
+		}
+		v.nums = append(v.nums, n)
+	}
+	return v, true
+}
+
+// compare return -1, 0 or 1 if v is less than, equal to or greater than o
+func (v version) compare(o version) int {
+	for i := range max(len(v.nums), len(o.nums)) {
+		var a, b uint64
+		if i < len(v.nums) {
+			a = v.nums[i]
+		}
+		if i < len(o.nums) {
+			b = o.nums[i]
+		}
+		if a != b {
+			return cmp.Compare(a, b)
+		}
+	}
+	// a pre-release version has lower precedence than the release
+	switch {
+	case len(v.pre) == 0 && len(o.pre) == 0:
+		return 0
+	case len(v.pre) == 0:
+		return 1
+	case len(o.pre) == 0:
+		return -1
+	}
+	for i := range min(len(v.pre), len(o.pre)) {
+		if res := comparePre(v.pre[i], o.pre[i]); res != 0 {
+			return res
+		}
+	}
+	return cmp.Compare(len(v.pre), len(o.pre))
+}
+
+// comparePre compare identifiers of pre-release, numeric identifiers are
+// compared numerically and have lower precedence than others.
+func comparePre(a, b string) int {
+	na, errA := strconv.ParseUint(a, 10, 64)
+	nb, errB := strconv.ParseUint(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
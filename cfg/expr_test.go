@@ -0,0 +1,141 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cfg
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestExpr(t *testing.T) {
+	suites := map[string][]string{
+		"default": {"Sms", "Alipay", "Redis", "Version", "Api", "Custom", "Empty"},
+	}
+	kv := map[string]string{
+		"sms":     "SmsJuhe",
+		"version": "v1.10.2",
+		"api":     "2.0.0-beta.2",
+		"custom":  "a b",
+		"alipay":  "a!b",
+		"redis":   "Sms Juhe",
+	}
+	f := NewFeatures(suites, kv)
+	for exp, res := range map[string]bool{
+		"Redis && (Sms = SmsJuhe || !Alipay)":   true,
+		"Redis && (Sms = SmsAli || !Alipay)":    false,
+		"Redis&&(Sms=SmsAli||Alipay)":           true,
+		"!Redis || Zinc":                        false,
+		"!!Redis":                               true,
+		"Sms == SmsJuhe":                        true,
+		"Sms != SmsJuhe":                        false,
+		"Zinc != SmsJuhe":                       true,
+		"Sms in (SmsAli, SmsJuhe)":              true,
+		"Sms in (SmsAli)":                       false,
+		"!(Sms in (SmsAli))":                    true,
+		"Zinc in (SmsJuhe)":                     false,
+		"Custom = 'a b'":                        true,
+		`Custom = "a b" && Sms = "SmsJuhe"`:     true,
+		"Version >= 1.10":                       true,
+		"Version > v1.9.9":                      true,
+		"Version < 1.10.2":                      false,
+		"Version <= 1.10.2":                     true,
+		"Api < 2.0.0":                           true,
+		"Api > 2.0.0-beta.1":                    true,
+		"Api > 2.0.0-beta.10":                   false,
+		"Api < 2.0.0-beta.2.1":                  true,
+		"Api > 2.0.0-alpha":                     true,
+		"Api > 2.0.0-1":                         true,
+		"Sms > 1.0.0":                           false,
+		"Zinc < 1.0.0":                          false,
+		"Redis && Sms = SmsJuhe || Zinc && Api": true,
+		// legacy `key = value` that does not parse
+		"Redis = Sms Juhe":   true,
+		" Redis = Sms Juhe ": true,
+		"Redis = Sms":        false,
+		"alipay = a!b":       true,
+		"Alipay=a!b":         true,
+		"alipay = a!c":       false,
+		"Custom = a b":       true,
+		"Zinc = a b":         false,
+		// legacy `key = ` of a feature without value
+		"Empty = ": true,
+		"empty=":   true,
+		"Sms = ":   false,
+		"Zinc = ":  false,
+	} {
+		if ok := f.CfgIf(exp); res != ok {
+			t.Errorf("CfgIf(%s) want %t got %t", exp, res, ok)
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	for _, exp := range []string{
+		"",
+		"Sms &&",
+		"Sms & Redis",
+		"(Sms",
+		"Sms)",
+		"Sms in SmsJuhe",
+		"Sms in (SmsJuhe",
+		"Sms in ()",
+		"Sms = 'SmsJuhe",
+		"Version > latest",
+		"Sms Redis",
+		"= Sms",
+	} {
+		if _, err := Compile(exp); err == nil {
+			t.Errorf("Compile(%q) want error but not", exp)
+		}
+		if If(exp) {
+			t.Errorf("If(%q) want false for invalid expression but not", exp)
+		}
+	}
+	e1, err := Compile("Sms || Redis")
+	if err != nil {
+		t.Fatalf("Compile(%q) want no error but got %s", "Sms || Redis", err)
+	}
+	if e2 := MustCompile("Sms || Redis"); e1 != e2 {
+		t.Error("want compiled expression cached but not")
+	}
+	if e1.String() != "Sms || Redis" {
+		t.Errorf("want expression string %q got %q", "Sms || Redis", e1.String())
+	}
+	if _, err = Compile("Sms &&"); err == nil {
+		t.Error("want invalid expression error but not")
+	}
+	if _, ok := _exprs.Load("Sms &&"); ok {
+		t.Error("want invalid expression not cached but cached")
+	}
+	for i := range 2*_exprCacheSize + 1 {
+		MustCompile("Sms = v" + strconv.Itoa(i))
+	}
+	n := 0
+	_exprs.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	if n > _exprCacheSize {
+		t.Errorf("want at most %d cached expressions got %d", _exprCacheSize, n)
+	}
+}
+
+func BenchmarkCfgIf(b *testing.B) {
+	f := NewFeatures(map[string][]string{"default": {"Sms", "Redis"}}, map[string]string{"sms": "SmsJuhe"})
+	b.ReportAllocs()
+	for b.Loop() {
+		f.CfgIf("Redis && (Sms = SmsJuhe || !Alipay)")
+	}
+}
+
+func BenchmarkCfgIfParallel(b *testing.B) {
+	f := NewFeatures(map[string][]string{"default": {"Sms", "Redis"}}, map[string]string{"sms": "SmsJuhe"})
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			f.CfgIf("Redis && (Sms = SmsJuhe || !Alipay)")
+		}
+	})
+}
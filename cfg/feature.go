@@ -5,16 +5,46 @@
 package cfg
 
 import (
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/alimy/tryst/types"
 )
 
-// Features fetures info struct
+// Features fetures info struct, it's safe for concurrent use and could be
+// reloaded at runtime.
 type Features struct {
+	snapshot atomic.Pointer[featuresSnapshot]
+	// mu serialize the writers and guard watchers and pending
+	mu       sync.Mutex
+	watchers map[string]map[*watcher]struct{}
+	// pending changes not yet notified in the order of updates, they are
+	// delivered by one goroutine at a time while notifying is true
+	pending   []change
+	notifying bool
+}
+
+// featuresSnapshot immutable snapshot of features that swapped atomically
+type featuresSnapshot struct {
 	kv       map[string]string
 	suites   map[string][]string
 	features map[string]string
+	// use the suites used to build features
+	use []string
+	// rules targeting rules of features
+	rules map[string]*rule
+}
+
+type watcher struct {
+	fn func(old, new string)
+}
+
+// change a change of feature to notify a watcher
+type change struct {
+	fn       func(old, new string)
+	old, new string
 }
 
 // Actions feature-func map alias type
@@ -23,39 +53,79 @@ type Actions map[string]types.Fn
 // NewFeatures create new Features instance
 func NewFeatures(suites map[string][]string, kv map[string]string) *Features {
 	f := newEmptyFeatures()
+	f.snapshot.Store(newSnapshot(suites, kv, []string{"default"}))
+	return f
+}
+
+func newEmptyFeatures() *Features {
+	f := &Features{
+		watchers: make(map[string]map[*watcher]struct{}),
+	}
+	f.snapshot.Store(newSnapshot(nil, nil, nil))
+	return f
+}
+
+func newSnapshot(suites map[string][]string, kv map[string]string, use []string) *featuresSnapshot {
+	s := &featuresSnapshot{
+		suites:   make(map[string][]string),
+		kv:       make(map[string]string),
+		features: make(map[string]string),
+	}
 	for k, v := range suites {
 		if len(k) > 0 {
-			for i := 0; i < len(v); i++ {
-				// ignore empty string
-				if len(v[i]) == 0 {
-					lastIdx := len(v) - 1
-					v[i] = v[lastIdx]
-					v = v[:lastIdx]
-					i--
-				}
-			}
+			// ignore empty string
+			v = slices.DeleteFunc(slices.Clone(v), func(item string) bool {
+				return len(item) == 0
+			})
 			if len(v) > 0 {
-				f.suites[k] = v
+				s.suites[k] = v
 			}
 		}
 	}
 	for k, v := range kv {
 		if len(k) > 0 && len(v) > 0 {
-			f.kv[k] = v
+			s.kv[k] = v
 		}
 	}
-	f.UseDefault()
-	return f
+	if len(use) > 0 {
+		s.apply(use)
+	}
+	return s
 }
 
-func newEmptyFeatures() *Features {
-	return &Features{
-		suites:   make(map[string][]string),
-		kv:       make(map[string]string),
-		features: make(map[string]string),
+// withRules return a copy of s with the rules
+func (s *featuresSnapshot) withRules(rules map[string]*rule) *featuresSnapshot {
+	res := *s
+	res.rules = rules
+	return &res
+}
+
+// apply add features of suite to s, it's only used before s published
+func (s *featuresSnapshot) apply(suite []string) {
+	s.use = append(s.use, suite...)
+	for _, feature := range s.flatFeatures(suite) {
+		if len(feature) == 0 {
+			continue
+		}
+		s.features[feature] = s.kv[feature]
 	}
 }
 
+func (s *featuresSnapshot) flatFeatures(suite []string) []string {
+	features := make([]string, 0, len(suite)+10)
+	for stack := slices.Clone(suite); len(stack) > 0; stack = stack[:len(stack)-1] {
+		item := strings.TrimSpace(strings.ToLower(stack[0]))
+		if len(item) > 0 {
+			if items, exist := s.suites[item]; exist {
+				stack = append(stack, items...)
+			}
+			features = append(features, item)
+		}
+		stack[0] = stack[len(stack)-1]
+	}
+	return features
+}
+
 // UseDefault use default suite for features
 func (f *Features) UseDefault() {
 	f.Use([]string{"default"}, true)
@@ -63,37 +133,122 @@ func (f *Features) UseDefault() {
 
 // Use use custom suite for features
 func (f *Features) Use(suite []string, noDefault bool) {
-	if noDefault && len(f.features) != 0 {
-		f.features = make(map[string]string)
+	f.update(func(old *featuresSnapshot) *featuresSnapshot {
+		use := suite
+		if !noDefault {
+			use = append(slices.Clone(old.use), suite...)
+		}
+		return newSnapshot(old.suites, old.kv, use).withRules(old.rules)
+	})
+}
+
+// Reload replace suites and kv of features, the suites in use are kept
+func (f *Features) Reload(suites map[string][]string, kv map[string]string) {
+	f.update(func(old *featuresSnapshot) *featuresSnapshot {
+		return newSnapshot(suites, kv, old.use).withRules(old.rules)
+	})
+}
+
+// reset replace suites and kv of features, and use the default suite
+func (f *Features) reset(suites map[string][]string, kv map[string]string) {
+	f.update(func(old *featuresSnapshot) *featuresSnapshot {
+		return newSnapshot(suites, kv, []string{"default"}).withRules(old.rules)
+	})
+}
+
+// Watch subscribe changes of the feature key, fn is called with the old and
+// new value after the feature toggled or its value changed. The value of a
+// disabled feature is empty, use If to tell it from an enabled feature without
+// value. The returned cancel stop the subscription.
+//
+// Changes are notified one at a time in the order that they happened, even
+// if the features are reloaded concurrently, so the new value of the last
+// notification is always the live one. A reload may return before its changes
+// notified if a concurrent one is notifying, the changes are notified by that
+// one then.
+func (f *Features) Watch(key string, fn func(old, new string)) (cancel func()) {
+	key = strings.ToLower(key)
+	w := &watcher{fn: fn}
+	f.mu.Lock()
+	if f.watchers[key] == nil {
+		f.watchers[key] = make(map[*watcher]struct{})
 	}
-	features := f.flatFeatures(suite)
-	for _, feature := range features {
-		if len(feature) == 0 {
+	f.watchers[key][w] = struct{}{}
+	f.mu.Unlock()
+	return func() {
+		f.mu.Lock()
+		delete(f.watchers[key], w)
+		if len(f.watchers[key]) == 0 {
+			delete(f.watchers, key)
+		}
+		f.mu.Unlock()
+	}
+}
+
+// update swap the snapshot with the one built by fn, and notify watchers of
+// the changed features.
+func (f *Features) update(fn func(old *featuresSnapshot) *featuresSnapshot) {
+	f.mu.Lock()
+	old := f.snapshot.Load()
+	cur := fn(old)
+	f.snapshot.Store(cur)
+	for key, watchers := range f.watchers {
+		ov, oe := old.features[key]
+		nv, ne := cur.features[key]
+		if oe == ne && ov == nv {
 			continue
 		}
-		f.features[feature] = f.kv[feature]
+		for w := range watchers {
+			f.pending = append(f.pending, change{w.fn, ov, nv})
+		}
+	}
+	if f.notifying {
+		// the changes are notified by the goroutine that is notifying
+		f.mu.Unlock()
+		return
 	}
+	f.notifying = true
+	f.notify()
 }
 
-func (f *Features) flatFeatures(suite []string) []string {
-	features := make([]string, 0, len(suite)+10)
-	for s := suite[:]; len(s) > 0; s = s[:len(s)-1] {
-		item := strings.TrimSpace(strings.ToLower(s[0]))
-		if len(item) > 0 {
-			if items, exist := f.suites[item]; exist {
-				s = append(s, items...)
-			}
-			features = append(features, item)
+// notify deliver pending changes until there is none, it must be called with
+// f.mu locked and f.notifying set, and return with f.mu unlocked.
+func (f *Features) notify() {
+	var (
+		changes []change
+		next    int
+		done    bool
+	)
+	defer func() {
+		if !done {
+			// a watcher panicked, let the next update notify the rest in order
+			f.mu.Lock()
+			f.pending = append(changes[next:], f.pending...)
+			f.notifying = false
+			f.mu.Unlock()
 		}
-		s[0] = s[len(s)-1]
+	}()
+	for len(f.pending) > 0 {
+		changes, next = f.pending, 0
+		f.pending = nil
+		f.mu.Unlock()
+		// call watchers without lock so they could use and reload the features
+		for next < len(changes) {
+			c := changes[next]
+			next++
+			c.fn(c.old, c.new)
+		}
+		f.mu.Lock()
 	}
-	return features
+	f.notifying = false
+	f.mu.Unlock()
+	done = true
 }
 
 // Cfg get value by key if exist
 func (f *Features) Cfg(key string) (string, bool) {
 	key = strings.ToLower(key)
-	value, exist := f.features[key]
+	value, exist := f.snapshot.Load().features[key]
 	return value, exist
 }
 
@@ -101,7 +256,7 @@ func (f *Features) Cfg(key string) (string, bool) {
 func (f *Features) CfgAs(key string, handle func(v string)) {
 	if handle != nil {
 		key = strings.ToLower(key)
-		if v, exist := f.features[key]; exist {
+		if v, exist := f.snapshot.Load().features[key]; exist {
 			handle(v)
 		}
 	}
@@ -110,17 +265,15 @@ func (f *Features) CfgAs(key string, handle func(v string)) {
 // CfgIf check expression is true. if expression just have a string like
 // `Sms` is mean `Sms` whether define in suite feature settings. expression like
 // `Sms = SmsJuhe` is mean whether `Sms` define in suite feature settings and value
-// is `SmsJuhe`
+// is `SmsJuhe`. expressions could be combined by &&, ||, ! and parentheses, see
+// Expr for the full grammar. The compiled expression is cached, and an invalid
+// expression is always false.
 func (f *Features) CfgIf(expression string) bool {
-	kv := strings.Split(expression, "=")
-	key := strings.Trim(strings.ToLower(kv[0]), " ")
-	v, ok := f.features[key]
-	if len(kv) == 2 && ok && strings.Trim(kv[1], " ") == v {
-		return true
-	} else if len(kv) == 1 && ok {
-		return true
+	expr, err := Compile(expression)
+	if err != nil {
+		return false
 	}
-	return false
+	return expr.Eval(f)
 }
 
 // CfgAll check all expressions is true.
@@ -0,0 +1,220 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cfg
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Source feature settings loaded from file, environment variables or
+// command-line flags. Suite names and kv keys are case-insensitive.
+type Source struct {
+	// Use the suites to use, the default suite is used if it's empty
+	Use    []string            `json:"use" yaml:"use"`
+	Suites map[string][]string `json:"suites" yaml:"suites"`
+	KV     map[string]string   `json:"kv" yaml:"kv"`
+	// Rules targeting rules of features, they are only loaded from file
+	Rules map[string]Rule `json:"rules" yaml:"rules"`
+}
+
+// FromYAML load Source from YAML data
+func FromYAML(data []byte) (*Source, error) {
+	s := &Source{}
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("cfg: parse yaml: %w", err)
+	}
+	return s.normalize(), nil
+}
+
+// FromJSON load Source from JSON data by encoding/json
+func FromJSON(data []byte) (*Source, error) {
+	return FromJSONWith(data, json.Unmarshal)
+}
+
+// FromJSONWith load Source from JSON data by unmarshal, like json.API.Unmarshal
+// of the json facade module.
+func FromJSONWith(data []byte, unmarshal func(data []byte, v any) error) (*Source, error) {
+	s := &Source{}
+	if err := unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("cfg: parse json: %w", err)
+	}
+	return s.normalize(), nil
+}
+
+// FromFile load Source from a YAML(.yaml/.yml) or JSON(.json) file
+func FromFile(path string) (*Source, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		return FromYAML(data)
+	case ".json":
+		return FromJSON(data)
+	default:
+		return nil, fmt.Errorf("cfg: unsupported file format %q", ext)
+	}
+}
+
+// FromEnv load Source from environment variables with prefix, like below:
+//
+//	<PREFIX>_USE=develop,slim
+//	<PREFIX>_SUITE_<NAME>=Zinc,MySQL,LogFile
+//	<PREFIX>_KV_<KEY>=value
+func FromEnv(prefix string) *Source {
+	s := &Source{}
+	prefix = strings.ToUpper(prefix) + "_"
+	for _, env := range os.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+		if !strings.HasPrefix(strings.ToUpper(name), prefix) {
+			continue
+		}
+		name = name[len(prefix):]
+		switch upper := strings.ToUpper(name); {
+		case upper == "USE":
+			s.Use = splitList(value)
+		case strings.HasPrefix(upper, "SUITE_"):
+			s.setSuite(name[len("SUITE_"):], splitList(value))
+		case strings.HasPrefix(upper, "KV_"):
+			s.setKV(name[len("KV_"):], value)
+		}
+	}
+	return s
+}
+
+// FromFlags register flags below to fs, and return Source that filled
+// after fs parsed:
+//
+//	-cfg.use develop,slim
+//	-cfg.suite develop=Zinc,MySQL,LogFile (repeatable)
+//	-cfg.kv sms=SmsJuhe (repeatable)
+func FromFlags(fs *flag.FlagSet) *Source {
+	s := &Source{}
+	fs.Func("cfg.use", "comma separated suites to use", func(value string) error {
+		s.Use = splitList(value)
+		return nil
+	})
+	fs.Func("cfg.suite", "suite in form of name=feature1,feature2 (repeatable)", func(value string) error {
+		name, features, ok := strings.Cut(value, "=")
+		if !ok {
+			return fmt.Errorf("want name=feature1,feature2 but got %q", value)
+		}
+		s.setSuite(name, splitList(features))
+		return nil
+	})
+	fs.Func("cfg.kv", "feature value in form of key=value (repeatable)", func(value string) error {
+		k, v, ok := strings.Cut(value, "=")
+		if !ok {
+			return fmt.Errorf("want key=value but got %q", value)
+		}
+		s.setKV(k, v)
+		return nil
+	})
+	return s
+}
+
+// Merge merge sources into one, the later source take precedence over the
+// earlier: a suite is replaced by the one has the same name, a kv entry is
+// replaced by the one has the same key, a rule is replaced by the one of the
+// same feature, and Use is replaced if it's not empty.
+// The conventional order is Merge(file, env, flags).
+func Merge(sources ...*Source) *Source {
+	res := &Source{}
+	for _, s := range sources {
+		if s == nil {
+			continue
+		}
+		if len(s.Use) > 0 {
+			res.Use = s.Use
+		}
+		for name, features := range s.Suites {
+			res.setSuite(name, features)
+		}
+		for k, v := range s.KV {
+			res.setKV(k, v)
+		}
+		for feature, r := range s.Rules {
+			res.setRule(feature, r)
+		}
+	}
+	return res
+}
+
+// Features create Features instance from the source
+func (s *Source) Features() *Features {
+	f := newEmptyFeatures()
+	f.snapshot.Store(newSnapshot(s.Suites, s.KV, s.use()).withRules(compileRules(s.Rules)))
+	return f
+}
+
+// InitialFrom initialize features in cfg pkg from merged sources
+func InitialFrom(sources ...*Source) {
+	s := Merge(sources...)
+	_features.update(func(*featuresSnapshot) *featuresSnapshot {
+		return newSnapshot(s.Suites, s.KV, s.use()).withRules(compileRules(s.Rules))
+	})
+}
+
+// use return the suites to use
+func (s *Source) use() []string {
+	if len(s.Use) > 0 {
+		return s.Use
+	}
+	return []string{"default"}
+}
+
+func (s *Source) normalize() *Source {
+	suites, kv, rules := s.Suites, s.KV, s.Rules
+	s.Suites, s.KV, s.Rules = nil, nil, nil
+	for name, features := range suites {
+		s.setSuite(name, features)
+	}
+	for k, v := range kv {
+		s.setKV(k, v)
+	}
+	for feature, r := range rules {
+		s.setRule(feature, r)
+	}
+	return s
+}
+
+func (s *Source) setSuite(name string, features []string) {
+	if s.Suites == nil {
+		s.Suites = make(map[string][]string)
+	}
+	s.Suites[strings.ToLower(strings.TrimSpace(name))] = features
+}
+
+func (s *Source) setKV(k, v string) {
+	if s.KV == nil {
+		s.KV = make(map[string]string)
+	}
+	s.KV[strings.ToLower(strings.TrimSpace(k))] = v
+}
+
+func (s *Source) setRule(feature string, r Rule) {
+	if s.Rules == nil {
+		s.Rules = make(map[string]Rule)
+	}
+	s.Rules[strings.ToLower(strings.TrimSpace(feature))] = r
+}
+
+func splitList(value string) []string {
+	var res []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			res = append(res, item)
+		}
+	}
+	return res
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cfg
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const _yamlSource = `
+suites:
+  Default: [Sms, Alipay, Zinc, MySQL, Redis]
+  develop: [Zinc, MySQL, LogFile]
+kv:
+  Sms: SmsJuhe
+  zinc: zinc-v1
+`
+
+func TestFromFile(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath, jsonPath := filepath.Join(dir, "features.yaml"), filepath.Join(dir, "features.json")
+	os.WriteFile(yamlPath, []byte(_yamlSource), 0644)
+	os.WriteFile(jsonPath, []byte(`{"use":["develop"],"suites":{"develop":["Zinc","LogFile"]},"kv":{"zinc":"zinc-v2"}}`), 0644)
+
+	ys, err := FromFile(yamlPath)
+	if err != nil {
+		t.Fatalf("FromFile(yaml) got error: %s", err)
+	}
+	want := &Source{
+		Suites: map[string][]string{
+			"default": {"Sms", "Alipay", "Zinc", "MySQL", "Redis"},
+			"develop": {"Zinc", "MySQL", "LogFile"},
+		},
+		KV: map[string]string{"sms": "SmsJuhe", "zinc": "zinc-v1"},
+	}
+	if !reflect.DeepEqual(ys, want) {
+		t.Errorf("FromFile(yaml) want %+v got %+v", want, ys)
+	}
+	js, err := FromFile(jsonPath)
+	if err != nil {
+		t.Fatalf("FromFile(json) got error: %s", err)
+	}
+	if len(js.Use) != 1 || js.KV["zinc"] != "zinc-v2" {
+		t.Errorf("FromFile(json) got unexpected %+v", js)
+	}
+	if _, err = FromFile(filepath.Join(dir, "features.toml")); err == nil {
+		t.Error("want FromFile(toml) error but not")
+	}
+	if _, err = FromYAML([]byte("suites: [")); err == nil {
+		t.Error("want FromYAML error but not")
+	}
+
+	// a codec like json.API could be plugged in
+	called := false
+	js, err = FromJSONWith([]byte(`{"KV":{"Zinc":"zinc-v3"}}`), func(data []byte, v any) error {
+		called = true
+		return json.Unmarshal(data, v)
+	})
+	if err != nil || !called || js.KV["zinc"] != "zinc-v3" {
+		t.Errorf("FromJSONWith() got unexpected %+v, %v", js, err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("TRYST_USE", "develop, slim")
+	t.Setenv("TRYST_SUITE_DEVELOP", "Zinc,MySQL,,LogFile")
+	t.Setenv("TRYST_KV_SMS", "SmsJuhe")
+	t.Setenv("TRYST_OTHER", "ignored")
+	t.Setenv("OTHER_KV_SMS", "ignored")
+
+	s := FromEnv("tryst")
+	want := &Source{
+		Use:    []string{"develop", "slim"},
+		Suites: map[string][]string{"develop": {"Zinc", "MySQL", "LogFile"}},
+		KV:     map[string]string{"sms": "SmsJuhe"},
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("FromEnv want %+v got %+v", want, s)
+	}
+}
+
+func TestFromFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	s := FromFlags(fs)
+	err := fs.Parse([]string{"-cfg.use", "slim", "-cfg.suite", "slim=Zinc,Redis", "-cfg.kv", "sms=SmsAli", "-cfg.kv", "zinc=zinc-v3"})
+	if err != nil {
+		t.Fatalf("parse flags got error: %s", err)
+	}
+	want := &Source{
+		Use:    []string{"slim"},
+		Suites: map[string][]string{"slim": {"Zinc", "Redis"}},
+		KV:     map[string]string{"sms": "SmsAli", "zinc": "zinc-v3"},
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("FromFlags want %+v got %+v", want, s)
+	}
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	FromFlags(fs)
+	if err = fs.Parse([]string{"-cfg.kv", "sms"}); err == nil {
+		t.Error("want parse invalid kv flag error but not")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	file, _ := FromYAML([]byte(_yamlSource))
+	env := &Source{KV: map[string]string{"SMS": "SmsAli"}}
+	flags := &Source{
+		Use:    []string{"develop"},
+		Suites: map[string][]string{"develop": {"Zinc", "LogFile"}},
+	}
+
+	f := Merge(file, env, nil, flags).Features()
+	for _, data := range []struct {
+		key    string
+		expect string
+		exist  bool
+	}{
+		{"Zinc", "zinc-v1", true},
+		{"LogFile", "", true},
+		{"MySQL", "", false},
+		{"Sms", "", false},
+	} {
+		if v, ok := f.Cfg(data.key); ok != data.exist || v != data.expect {
+			t.Errorf("key: %s expect: %s exist: %t got v: %s ok: %t", data.key, data.expect, data.exist, v, ok)
+		}
+	}
+
+	f = Merge(file, env).Features()
+	if v, _ := f.Cfg("Sms"); v != "SmsAli" {
+		t.Errorf(`want Cfg("Sms") == "SmsAli" but got %q`, v)
+	}
+}
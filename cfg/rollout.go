@@ -0,0 +1,167 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cfg
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alimy/tryst/internal/wyhash"
+)
+
+// Subject the subject like a user that features are evaluated for
+type Subject struct {
+	ID string
+	// Attrs attributes of the subject like region and tenant, the keys are
+	// case-insensitive.
+	Attrs map[string]string
+}
+
+// Rule targeting rule of a feature, it narrows an enabled feature down to part
+// of subjects. A subject in Deny is always excluded, a subject in Allow is
+// always included, otherwise the subject must match all Attrs and fall into
+// the Percentage.
+type Rule struct {
+	// Percentage of subjects to include in [0, 100], subjects are bucketed
+	// by hash of their IDs so a subject always gets the same answer. All
+	// subjects are included if it's nil, like a rule that targets by Attrs
+	// only, set it to Percent(0) to include the Allow subjects only.
+	Percentage *float64 `json:"percentage" yaml:"percentage"`
+	// Allow IDs of subjects that always included
+	Allow []string `json:"allow" yaml:"allow"`
+	// Deny IDs of subjects that always excluded
+	Deny []string `json:"deny" yaml:"deny"`
+	// Attrs the value of every attribute of subject must be in the list,
+	// like {"region": ["eu", "us"]}.
+	Attrs map[string][]string `json:"attrs" yaml:"attrs"`
+	// Salt seed the buckets, it's the feature name if empty. Features have
+	// different salts get independent buckets, so a subject in the 10% of
+	// one feature is not always in the 10% of another.
+	Salt string `json:"salt" yaml:"salt"`
+}
+
+// Percent return a pointer to p, used to set Rule.Percentage
+func Percent(p float64) *float64 {
+	return &p
+}
+
+// rule compiled Rule
+type rule struct {
+	allow     map[string]struct{}
+	deny      map[string]struct{}
+	attrs     map[string]map[string]struct{}
+	seed      uint64
+	threshold uint64
+}
+
+// _buckets count of buckets that percentage is mapped to
+const _buckets = 10000
+
+func compileRules(rules map[string]Rule) map[string]*rule {
+	res := make(map[string]*rule, len(rules))
+	for feature, r := range rules {
+		feature = strings.ToLower(strings.TrimSpace(feature))
+		salt := r.Salt
+		if len(salt) == 0 {
+			salt = feature
+		}
+		percentage := 100.0
+		if r.Percentage != nil {
+			percentage = min(max(*r.Percentage, 0), 100)
+		}
+		cr := &rule{
+			allow:     toSet(r.Allow),
+			deny:      toSet(r.Deny),
+			attrs:     make(map[string]map[string]struct{}, len(r.Attrs)),
+			seed:      wyhash.Sum64String(salt),
+			threshold: uint64(percentage * _buckets / 100),
+		}
+		for k, values := range r.Attrs {
+			cr.attrs[strings.ToLower(k)] = toSet(values)
+		}
+		res[feature] = cr
+	}
+	return res
+}
+
+func toSet(items []string) map[string]struct{} {
+	res := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		res[item] = struct{}{}
+	}
+	return res
+}
+
+func (r *rule) match(s Subject) bool {
+	if _, ok := r.deny[s.ID]; ok {
+		return false
+	}
+	if _, ok := r.allow[s.ID]; ok {
+		return true
+	}
+	for k, values := range r.attrs {
+		v, ok := subjectAttr(s, k)
+		if !ok {
+			return false
+		}
+		if _, ok = values[v]; !ok {
+			return false
+		}
+	}
+	return wyhash.Sum64StringWithSeed(s.ID, r.seed)%_buckets < r.threshold
+}
+
+// subjectAttr return attribute k of s, k is lower case
+func subjectAttr(s Subject, k string) (string, bool) {
+	if v, ok := s.Attrs[k]; ok {
+		return v, true
+	}
+	for key, v := range s.Attrs {
+		if strings.EqualFold(key, k) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+type overridesKey struct{}
+
+// WithOverride return a copy of ctx that force the feature on or off for
+// IfFor, it's useful to force a feature for a request in testing.
+func WithOverride(ctx context.Context, feature string, on bool) context.Context {
+	old, _ := ctx.Value(overridesKey{}).(map[string]bool)
+	overrides := make(map[string]bool, len(old)+1)
+	for k, v := range old {
+		overrides[k] = v
+	}
+	overrides[strings.ToLower(feature)] = on
+	return context.WithValue(ctx, overridesKey{}, overrides)
+}
+
+// SetRules replace targeting rules of features, the keys are feature names
+func (f *Features) SetRules(rules map[string]Rule) {
+	f.update(func(old *featuresSnapshot) *featuresSnapshot {
+		return old.withRules(compileRules(rules))
+	})
+}
+
+// IfFor check the feature is enabled for the subject. The override set by
+// WithOverride in ctx take precedence, then the feature must be enabled and
+// the subject must match the Rule of the feature if it has one. The result
+// is deterministic, the same subject get the same answer across replicas.
+func (f *Features) IfFor(ctx context.Context, feature string, subject Subject) bool {
+	feature = strings.ToLower(feature)
+	if overrides, ok := ctx.Value(overridesKey{}).(map[string]bool); ok {
+		if on, exist := overrides[feature]; exist {
+			return on
+		}
+	}
+	s := f.snapshot.Load()
+	if _, ok := s.features[feature]; !ok {
+		return false
+	}
+	r, ok := s.rules[feature]
+	return !ok || r.match(subject)
+}
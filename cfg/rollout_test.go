@@ -0,0 +1,124 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cfg
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+func TestIfFor(t *testing.T) {
+	ctx := context.Background()
+	f := NewFeatures(map[string][]string{"default": {"Sms", "Alipay", "Zinc"}}, nil)
+	f.SetRules(map[string]Rule{
+		"Sms": {
+			Percentage: Percent(100),
+			Allow:      []string{"alice"},
+			Deny:       []string{"bob"},
+			Attrs:      map[string][]string{"Region": {"eu", "us"}},
+		},
+		"Alipay":  {Percentage: Percent(0), Allow: []string{"alice"}},
+		"Zinc":    {Attrs: map[string][]string{"region": {"eu"}}},
+		"LogFile": {Percentage: Percent(100)},
+	})
+	for _, data := range []struct {
+		feature string
+		subject Subject
+		expect  bool
+	}{
+		{"Sms", Subject{ID: "alice"}, true},
+		{"Sms", Subject{ID: "bob", Attrs: map[string]string{"region": "eu"}}, false},
+		{"Sms", Subject{ID: "carol", Attrs: map[string]string{"region": "eu"}}, true},
+		{"Sms", Subject{ID: "carol", Attrs: map[string]string{"REGION": "us"}}, true},
+		{"Sms", Subject{ID: "carol", Attrs: map[string]string{"region": "cn"}}, false},
+		{"Sms", Subject{ID: "carol"}, false},
+		{"Alipay", Subject{ID: "alice"}, true},
+		{"Alipay", Subject{ID: "carol"}, false},
+		{"Zinc", Subject{ID: "carol", Attrs: map[string]string{"region": "eu"}}, true},
+		{"Zinc", Subject{ID: "carol", Attrs: map[string]string{"region": "us"}}, false},
+		{"LogFile", Subject{ID: "alice"}, false},
+	} {
+		if ok := f.IfFor(ctx, data.feature, data.subject); ok != data.expect {
+			t.Errorf("IfFor(%s, %+v) want %t got %t", data.feature, data.subject, data.expect, ok)
+		}
+	}
+
+	ctx = WithOverride(ctx, "Alipay", true)
+	ctx = WithOverride(ctx, "Zinc", false)
+	if !f.IfFor(ctx, "Alipay", Subject{ID: "carol"}) || f.IfFor(ctx, "Zinc", Subject{ID: "carol", Attrs: map[string]string{"region": "eu"}}) {
+		t.Error("want overrides in ctx take precedence but not")
+	}
+
+	// rules are kept after suites changed
+	f.Use([]string{"LogFile"}, false)
+	if f.IfFor(context.Background(), "Alipay", Subject{ID: "carol"}) {
+		t.Error("want rules kept after Use but not")
+	}
+}
+
+func TestRolloutPercentage(t *testing.T) {
+	ctx := context.Background()
+	f := NewFeatures(map[string][]string{"default": {"Sms", "Alipay"}}, nil)
+	f.SetRules(map[string]Rule{
+		"Sms":    {Percentage: Percent(30)},
+		"Alipay": {Percentage: Percent(30)},
+	})
+	g := NewFeatures(map[string][]string{"default": {"Sms"}}, nil)
+	g.SetRules(map[string]Rule{"Sms": {Percentage: Percent(30)}})
+
+	n, sms, both := 20000, 0, 0
+	for i := range n {
+		s := Subject{ID: "user-" + strconv.Itoa(i)}
+		on := f.IfFor(ctx, "Sms", s)
+		if on != g.IfFor(ctx, "Sms", s) {
+			t.Fatalf("want the same answer for %s across instances but not", s.ID)
+		}
+		if on {
+			sms++
+			if f.IfFor(ctx, "Alipay", s) {
+				both++
+			}
+		}
+	}
+	if ratio := float64(sms) / float64(n); ratio < 0.28 || ratio > 0.32 {
+		t.Errorf("want about 30%% subjects included got %.3f", ratio)
+	}
+	// buckets of features are independent
+	if ratio := float64(both) / float64(sms); ratio < 0.26 || ratio > 0.34 {
+		t.Errorf("want about 30%% subjects of Sms included in Alipay got %.3f", ratio)
+	}
+
+	// growing the percentage keep the subjects included before
+	g.SetRules(map[string]Rule{"Sms": {Percentage: Percent(50)}})
+	for i := range n {
+		s := Subject{ID: "user-" + strconv.Itoa(i)}
+		if f.IfFor(ctx, "Sms", s) && !g.IfFor(ctx, "Sms", s) {
+			t.Fatalf("want %s kept after percentage grown but not", s.ID)
+		}
+	}
+}
+
+func TestRulesFromSource(t *testing.T) {
+	s, err := FromYAML([]byte(`
+suites:
+  default: [Sms]
+rules:
+  SMS:
+    percentage: 0
+    allow: [alice]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := s.Features()
+	if !f.IfFor(context.Background(), "Sms", Subject{ID: "alice"}) || f.IfFor(context.Background(), "Sms", Subject{ID: "bob"}) {
+		t.Error("want rules loaded from source but not")
+	}
+	f.ReloadSource(&Source{Suites: s.Suites})
+	if !f.IfFor(context.Background(), "Sms", Subject{ID: "bob"}) {
+		t.Error("want rules replaced after ReloadSource but not")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cfg
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// ReloadSource replace suites, kv and rules of features with the source, the suites
+// in use are replaced too if Use of the source is not empty or no suite is
+// in use.
+func (f *Features) ReloadSource(s *Source) {
+	f.update(func(old *featuresSnapshot) *featuresSnapshot {
+		use := old.use
+		if len(s.Use) > 0 || len(use) == 0 {
+			use = s.use()
+		}
+		return newSnapshot(s.Suites, s.KV, use).withRules(compileRules(s.Rules))
+	})
+}
+
+// PollSource reload features with the source returned by load every interval
+// in background until ctx done, load could return a nil source to skip the
+// reloading. Errors of load are passed to onErr if it's not nil, and the
+// features are kept unchanged.
+func (f *Features) PollSource(ctx context.Context, interval time.Duration, load func() (*Source, error), onErr func(error)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s, err := load()
+				if err != nil {
+					if onErr != nil {
+						onErr(err)
+					}
+					continue
+				}
+				if s != nil {
+					f.ReloadSource(s)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// WatchFile check the YAML/JSON file every interval in background until ctx
+// done, and reload features from it once its modification time or size changed.
+// Errors are passed to onErr if it's not nil.
+func (f *Features) WatchFile(ctx context.Context, path string, interval time.Duration, onErr func(error)) {
+	var modTime time.Time
+	size := int64(-1)
+	f.PollSource(ctx, interval, func() (*Source, error) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.ModTime().Equal(modTime) && info.Size() == size {
+			return nil, nil
+		}
+		s, err := FromFile(path)
+		if err == nil {
+			modTime, size = info.ModTime(), info.Size()
+		}
+		return s, err
+	}, onErr)
+}
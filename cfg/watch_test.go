@@ -0,0 +1,172 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cfg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	suites := map[string][]string{
+		"default": {"Sms", "Zinc"},
+		"develop": {"Zinc", "LogFile"},
+	}
+	f := NewFeatures(suites, map[string]string{"sms": "SmsJuhe"})
+
+	var changes, logChanges []string
+	cancel := f.Watch("Sms", func(old, new string) {
+		changes = append(changes, old+"->"+new)
+		// watchers could use the features
+		f.Cfg("Sms")
+	})
+	f.Watch("LogFile", func(old, new string) {
+		logChanges = append(logChanges, old+"->"+new)
+	})
+
+	f.Reload(suites, map[string]string{"sms": "SmsAli"})
+	f.Reload(suites, map[string]string{"sms": "SmsAli", "zinc": "v2"})
+	f.Use([]string{"develop"}, true)
+	cancel()
+	f.UseDefault()
+	if want := []string{"SmsJuhe->SmsAli", "SmsAli->"}; !slices.Equal(changes, want) {
+		t.Errorf("want Sms changes %v got %v", want, changes)
+	}
+	if want := []string{"->", "->"}; !slices.Equal(logChanges, want) {
+		t.Errorf("want LogFile changes %v got %v", want, logChanges)
+	}
+	// the suites in use are kept after reload
+	f.Use([]string{"develop"}, false)
+	f.Reload(suites, nil)
+	if !f.CfgAll("Sms", "LogFile") {
+		t.Error(`want CfgAll("Sms", "LogFile") after reload but not`)
+	}
+}
+
+func TestConcurrentReload(t *testing.T) {
+	f := NewFeatures(map[string][]string{"default": {"Sms"}}, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f.PollSource(ctx, time.Millisecond, func() (*Source, error) {
+		return &Source{Suites: map[string][]string{"default": {"Sms"}}, KV: map[string]string{"sms": time.Now().String()}}, nil
+	}, nil)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				if !f.CfgIf("Sms") {
+					t.Error(`want CfgIf("Sms") but not`)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConcurrentReloadNotify(t *testing.T) {
+	suites := map[string][]string{"default": {"Sms"}}
+	f := NewFeatures(suites, map[string]string{"sms": "v0"})
+
+	// watchers are called one at a time, so no lock is needed
+	var changes [][2]string
+	f.Watch("Sms", func(old, new string) {
+		// widen the window between the swap and the notification
+		runtime.Gosched()
+		changes = append(changes, [2]string{old, new})
+	})
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				f.Reload(suites, map[string]string{"sms": fmt.Sprintf("v%d-%d", g, i)})
+			}
+		}()
+	}
+	wg.Wait()
+
+	// every notification continues from the previous one, and the last one
+	// is the live value
+	last := "v0"
+	for _, c := range changes {
+		if c[0] != last {
+			t.Fatalf("want change from %s got %s->%s", last, c[0], c[1])
+		}
+		last = c[1]
+	}
+	if v, _ := f.Cfg("Sms"); v != last {
+		t.Errorf("want last notified value %s to be live value %s", last, v)
+	}
+}
+
+func TestWatchReentrant(t *testing.T) {
+	suites := map[string][]string{"default": {"Sms"}}
+	f := NewFeatures(suites, map[string]string{"sms": "v0"})
+
+	var changes []string
+	f.Watch("Sms", func(old, new string) {
+		changes = append(changes, old+"->"+new)
+		// watchers could reload the features
+		if new == "v1" {
+			f.Reload(suites, map[string]string{"sms": "v2"})
+		}
+	})
+	f.Reload(suites, map[string]string{"sms": "v1"})
+	if want := []string{"v0->v1", "v1->v2"}; !slices.Equal(changes, want) {
+		t.Errorf("want Sms changes %v got %v", want, changes)
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "features.json")
+	os.WriteFile(path, []byte(`{"suites":{"default":["Sms"]},"kv":{"sms":"SmsJuhe"}}`), 0644)
+
+	f := newEmptyFeatures()
+	changed := make(chan string, 2)
+	f.Watch("sms", func(_, new string) {
+		changed <- new
+	})
+	var errCount sync.Map
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f.WatchFile(ctx, path, 5*time.Millisecond, func(err error) {
+		errCount.Store(errors.Is(err, os.ErrNotExist), true)
+	})
+
+	for _, want := range []string{"SmsJuhe", "SmsAli"} {
+		select {
+		case v := <-changed:
+			if v != want {
+				t.Errorf("want sms changed to %q got %q", want, v)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("want sms changed to %q but timeout", want)
+		}
+		// make sure modification time changed
+		time.Sleep(10 * time.Millisecond)
+		os.WriteFile(path, []byte(`{"kv":{"sms":"SmsAli"},"suites":{"default":["Sms","Zinc"]}}`), 0644)
+	}
+	os.Remove(path)
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := errCount.Load(true); !ok {
+		t.Error("want not exist error after file removed but not")
+	}
+	if v, _ := f.Cfg("sms"); v != "SmsAli" {
+		t.Errorf("want features kept after file removed but got %q", v)
+	}
+}
@@ -0,0 +1,448 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+// Package bloom provides Bloom filter and counting Bloom filter, which are
+// approximate membership structures that may report false positive but never
+// false negative.
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/alimy/tryst/internal/wyhash"
+)
+
+const (
+	_kindFilter byte = iota + 1
+	_kindCountingFilter
+
+	_version   = 1
+	_headerLen = 2 + 4 + 8
+
+	// seed of the second hash used by double hashing
+	_seed2 = 0x9e3779b97f4a7c15
+)
+
+// ErrInvalidData data to unmarshal is invalid
+var ErrInvalidData = errors.New("bloom: invalid data")
+
+// EstimateParameters return bits count m and hash functions count k of a filter
+// that has false positive rate fp with n elements
+func EstimateParameters(n uint64, fp float64) (m uint64, k uint32) {
+	n, fp = max(n, 1), min(max(fp, 1e-12), 0.5)
+	m = uint64(math.Ceil(-float64(n) * math.Log(fp) / (math.Ln2 * math.Ln2)))
+	k = uint32(max(math.Round(float64(m)/float64(n)*math.Ln2), 1))
+	return
+}
+
+// location hashes of data that derive its locations by double hashing
+type location struct {
+	h1, h2 uint64
+}
+
+func locate(data string) location {
+	return location{
+		h1: wyhash.Sum64String(data),
+		h2: wyhash.Sum64StringWithSeed(data, _seed2) | 1,
+	}
+}
+
+// at return the i-th location in [0, m)
+func (l location) at(i uint32, m uint64) uint64 {
+	return (l.h1 + uint64(i)*l.h2) % m
+}
+
+func bytesToString(data []byte) string {
+	return unsafe.String(unsafe.SliceData(data), len(data))
+}
+
+// Filter Bloom filter, it's not safe for concurrent use
+type Filter struct {
+	m    uint64
+	k    uint32
+	bits []uint64
+}
+
+// New create a Filter with m bits and k hash functions
+func New(m uint64, k uint32) *Filter {
+	m, k = max(m, 1), max(k, 1)
+	return &Filter{
+		m:    m,
+		k:    k,
+		bits: make([]uint64, (m+63)/64),
+	}
+}
+
+// NewWithEstimates create a Filter that has false positive rate fp with n elements
+func NewWithEstimates(n uint64, fp float64) *Filter {
+	return New(EstimateParameters(n, fp))
+}
+
+// Cap return bits count of the filter
+func (f *Filter) Cap() uint64 {
+	return f.m
+}
+
+// K return hash functions count of the filter
+func (f *Filter) K() uint32 {
+	return f.k
+}
+
+// Add add data to the filter
+func (f *Filter) Add(data []byte) {
+	f.AddString(bytesToString(data))
+}
+
+// AddString add data to the filter
+func (f *Filter) AddString(data string) {
+	l := locate(data)
+	for i := range f.k {
+		idx := l.at(i, f.m)
+		f.bits[idx>>6] |= 1 << (idx & 63)
+	}
+}
+
+// Contains return true if data may be in the filter
+func (f *Filter) Contains(data []byte) bool {
+	return f.ContainsString(bytesToString(data))
+}
+
+// ContainsString return true if data may be in the filter
+func (f *Filter) ContainsString(data string) bool {
+	l := locate(data)
+	for i := range f.k {
+		idx := l.at(i, f.m)
+		if f.bits[idx>>6]&(1<<(idx&63)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset remove all data from the filter
+func (f *Filter) Reset() {
+	clear(f.bits)
+}
+
+// MarshalBinary encode the filter
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	data := appendHeader(make([]byte, 0, _headerLen+8*len(f.bits)), _kindFilter, f.m, f.k)
+	for _, w := range f.bits {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decode data to f, or to a new Filter if f is nil
+func (f *Filter) UnmarshalBinary(data []byte) (*Filter, error) {
+	m, k, body, err := parseHeader(data, _kindFilter)
+	if err != nil {
+		return nil, err
+	}
+	words := (m + 63) / 64
+	if uint64(len(body)) != 8*words {
+		return nil, ErrInvalidData
+	}
+	if f == nil {
+		f = &Filter{}
+	}
+	f.m, f.k, f.bits = m, k, make([]uint64, words)
+	for i := range f.bits {
+		f.bits[i] = binary.LittleEndian.Uint64(body[8*i:])
+	}
+	return f, nil
+}
+
+// SyncFilter Bloom filter that safe for concurrent use, it's lock-free
+type SyncFilter struct {
+	f Filter
+}
+
+// NewSync create a SyncFilter with m bits and k hash functions
+func NewSync(m uint64, k uint32) *SyncFilter {
+	return &SyncFilter{f: *New(m, k)}
+}
+
+// NewSyncWithEstimates create a SyncFilter that has false positive rate fp with n elements
+func NewSyncWithEstimates(n uint64, fp float64) *SyncFilter {
+	return NewSync(EstimateParameters(n, fp))
+}
+
+// Cap return bits count of the filter
+func (f *SyncFilter) Cap() uint64 {
+	return f.f.m
+}
+
+// K return hash functions count of the filter
+func (f *SyncFilter) K() uint32 {
+	return f.f.k
+}
+
+// Add add data to the filter
+func (f *SyncFilter) Add(data []byte) {
+	f.AddString(bytesToString(data))
+}
+
+// AddString add data to the filter
+func (f *SyncFilter) AddString(data string) {
+	l := locate(data)
+	for i := range f.f.k {
+		idx := l.at(i, f.f.m)
+		atomic.OrUint64(&f.f.bits[idx>>6], 1<<(idx&63))
+	}
+}
+
+// Contains return true if data may be in the filter
+func (f *SyncFilter) Contains(data []byte) bool {
+	return f.ContainsString(bytesToString(data))
+}
+
+// ContainsString return true if data may be in the filter
+func (f *SyncFilter) ContainsString(data string) bool {
+	l := locate(data)
+	for i := range f.f.k {
+		idx := l.at(i, f.f.m)
+		if atomic.LoadUint64(&f.f.bits[idx>>6])&(1<<(idx&63)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalBinary encode the filter
+func (f *SyncFilter) MarshalBinary() ([]byte, error) {
+	data := appendHeader(make([]byte, 0, _headerLen+8*len(f.f.bits)), _kindFilter, f.f.m, f.f.k)
+	for i := range f.f.bits {
+		data = binary.LittleEndian.AppendUint64(data, atomic.LoadUint64(&f.f.bits[i]))
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decode data to f, or to a new SyncFilter if f is nil,
+// it's not safe to call concurrently with other methods
+func (f *SyncFilter) UnmarshalBinary(data []byte) (*SyncFilter, error) {
+	if f == nil {
+		f = &SyncFilter{}
+	}
+	if _, err := f.f.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// CountingFilter counting Bloom filter that support removing, every location
+// has a 8-bit saturating counter. It's not safe for concurrent use.
+type CountingFilter struct {
+	m        uint64
+	k        uint32
+	counters []uint8
+}
+
+// NewCounting create a CountingFilter with m counters and k hash functions
+func NewCounting(m uint64, k uint32) *CountingFilter {
+	m, k = max(m, 1), max(k, 1)
+	return &CountingFilter{
+		m:        m,
+		k:        k,
+		counters: make([]uint8, m),
+	}
+}
+
+// NewCountingWithEstimates create a CountingFilter that has false positive
+// rate fp with n elements
+func NewCountingWithEstimates(n uint64, fp float64) *CountingFilter {
+	return NewCounting(EstimateParameters(n, fp))
+}
+
+// Cap return counters count of the filter
+func (f *CountingFilter) Cap() uint64 {
+	return f.m
+}
+
+// K return hash functions count of the filter
+func (f *CountingFilter) K() uint32 {
+	return f.k
+}
+
+// Add add data to the filter
+func (f *CountingFilter) Add(data []byte) {
+	f.AddString(bytesToString(data))
+}
+
+// AddString add data to the filter
+func (f *CountingFilter) AddString(data string) {
+	l := locate(data)
+	for i := range f.k {
+		if idx := l.at(i, f.m); f.counters[idx] < math.MaxUint8 {
+			f.counters[idx]++
+		}
+	}
+}
+
+// Remove remove data that added before from the filter, it return false if
+// data is not in the filter. Removing data never added may cause false negative.
+func (f *CountingFilter) Remove(data []byte) bool {
+	return f.RemoveString(bytesToString(data))
+}
+
+// RemoveString remove data that added before from the filter, it return false if
+// data is not in the filter. Removing data never added may cause false negative.
+func (f *CountingFilter) RemoveString(data string) bool {
+	if !f.ContainsString(data) {
+		return false
+	}
+	l := locate(data)
+	for i := range f.k {
+		// a saturated counter is sticky as its real count is unknown
+		if idx := l.at(i, f.m); f.counters[idx] < math.MaxUint8 {
+			f.counters[idx]--
+		}
+	}
+	return true
+}
+
+// Contains return true if data may be in the filter
+func (f *CountingFilter) Contains(data []byte) bool {
+	return f.ContainsString(bytesToString(data))
+}
+
+// ContainsString return true if data may be in the filter
+func (f *CountingFilter) ContainsString(data string) bool {
+	l := locate(data)
+	for i := range f.k {
+		if f.counters[l.at(i, f.m)] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset remove all data from the filter
+func (f *CountingFilter) Reset() {
+	clear(f.counters)
+}
+
+// MarshalBinary encode the filter
+func (f *CountingFilter) MarshalBinary() ([]byte, error) {
+	data := appendHeader(make([]byte, 0, _headerLen+len(f.counters)), _kindCountingFilter, f.m, f.k)
+	return append(data, f.counters...), nil
+}
+
+// UnmarshalBinary decode data to f, or to a new CountingFilter if f is nil
+func (f *CountingFilter) UnmarshalBinary(data []byte) (*CountingFilter, error) {
+	m, k, body, err := parseHeader(data, _kindCountingFilter)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(body)) != m {
+		return nil, ErrInvalidData
+	}
+	if f == nil {
+		f = &CountingFilter{}
+	}
+	f.m, f.k, f.counters = m, k, append([]uint8(nil), body...)
+	return f, nil
+}
+
+// SyncCountingFilter counting Bloom filter that safe for concurrent use
+type SyncCountingFilter struct {
+	mu sync.RWMutex
+	f  CountingFilter
+}
+
+// NewSyncCounting create a SyncCountingFilter with m counters and k hash functions
+func NewSyncCounting(m uint64, k uint32) *SyncCountingFilter {
+	return &SyncCountingFilter{f: *NewCounting(m, k)}
+}
+
+// NewSyncCountingWithEstimates create a SyncCountingFilter that has false
+// positive rate fp with n elements
+func NewSyncCountingWithEstimates(n uint64, fp float64) *SyncCountingFilter {
+	return NewSyncCounting(EstimateParameters(n, fp))
+}
+
+// Add add data to the filter
+func (f *SyncCountingFilter) Add(data []byte) {
+	f.mu.Lock()
+	f.f.Add(data)
+	f.mu.Unlock()
+}
+
+// AddString add data to the filter
+func (f *SyncCountingFilter) AddString(data string) {
+	f.mu.Lock()
+	f.f.AddString(data)
+	f.mu.Unlock()
+}
+
+// Remove remove data that added before from the filter
+func (f *SyncCountingFilter) Remove(data []byte) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.f.Remove(data)
+}
+
+// RemoveString remove data that added before from the filter
+func (f *SyncCountingFilter) RemoveString(data string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.f.RemoveString(data)
+}
+
+// Contains return true if data may be in the filter
+func (f *SyncCountingFilter) Contains(data []byte) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.f.Contains(data)
+}
+
+// ContainsString return true if data may be in the filter
+func (f *SyncCountingFilter) ContainsString(data string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.f.ContainsString(data)
+}
+
+// MarshalBinary encode the filter
+func (f *SyncCountingFilter) MarshalBinary() ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.f.MarshalBinary()
+}
+
+// UnmarshalBinary decode data to f, or to a new SyncCountingFilter if f is nil
+func (f *SyncCountingFilter) UnmarshalBinary(data []byte) (*SyncCountingFilter, error) {
+	if f == nil {
+		f = &SyncCountingFilter{}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.f.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func appendHeader(data []byte, kind byte, m uint64, k uint32) []byte {
+	data = append(data, kind, _version)
+	data = binary.LittleEndian.AppendUint32(data, k)
+	return binary.LittleEndian.AppendUint64(data, m)
+}
+
+func parseHeader(data []byte, kind byte) (m uint64, k uint32, body []byte, err error) {
+	if len(data) < _headerLen || data[0] != kind || data[1] != _version {
+		return 0, 0, nil, ErrInvalidData
+	}
+	k, m = binary.LittleEndian.Uint32(data[2:]), binary.LittleEndian.Uint64(data[6:])
+	// m is rounded up to words of 64 bits that must not overflow
+	if k == 0 || m == 0 || m > math.MaxUint64-63 {
+		return 0, 0, nil, ErrInvalidData
+	}
+	return m, k, data[_headerLen:], nil
+}
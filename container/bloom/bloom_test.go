@@ -0,0 +1,150 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package bloom
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/alimy/tryst/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateParameters(t *testing.T) {
+	m, k := EstimateParameters(1000, 0.01)
+	assert.Equal(t, uint64(9586), m)
+	assert.Equal(t, uint32(7), k)
+}
+
+func TestFilter(t *testing.T) {
+	const n = 10000
+	f := NewWithEstimates(n, 0.01)
+	for i := range n {
+		f.AddString(strconv.Itoa(i))
+	}
+	for i := range n {
+		assert.True(t, f.Contains([]byte(strconv.Itoa(i))))
+	}
+	fp := 0
+	for i := n; i < 2*n; i++ {
+		if f.ContainsString(strconv.Itoa(i)) {
+			fp++
+		}
+	}
+	assert.Less(t, fp, n*2/100)
+
+	data, err := f.MarshalBinary()
+	require.NoError(t, err)
+	g, err := (*Filter)(nil).UnmarshalBinary(data)
+	require.NoError(t, err)
+	assert.Equal(t, f, g)
+
+	_, err = g.UnmarshalBinary(data[:len(data)-1])
+	assert.ErrorIs(t, err, ErrInvalidData)
+	_, err = (&CountingFilter{}).UnmarshalBinary(data)
+	assert.ErrorIs(t, err, ErrInvalidData)
+	// words of m near 2^64 overflow to an empty body
+	bad := append([]byte{}, data[:_headerLen]...)
+	for _, m := range []uint64{0, math.MaxUint64, math.MaxUint64 - 62} {
+		binary.LittleEndian.PutUint64(bad[6:], m)
+		_, err = (*Filter)(nil).UnmarshalBinary(bad)
+		assert.ErrorIs(t, err, ErrInvalidData)
+	}
+
+	f.Reset()
+	assert.False(t, f.ContainsString("0"))
+}
+
+func TestSyncFilter(t *testing.T) {
+	f := NewSyncWithEstimates(1000, 0.01)
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := g; i < 1000; i += 4 {
+				f.AddString(strconv.Itoa(i))
+				assert.True(t, f.ContainsString(strconv.Itoa(i)))
+			}
+		}()
+	}
+	wg.Wait()
+	for i := range 1000 {
+		assert.True(t, f.Contains([]byte(strconv.Itoa(i))))
+	}
+
+	b := types.Binary[*SyncFilter]{Data: f}
+	v, err := b.Value()
+	require.NoError(t, err)
+	var r types.Binary[*SyncFilter]
+	require.NoError(t, r.Scan(v))
+	assert.Equal(t, f.Cap(), r.Data.Cap())
+	assert.True(t, r.Data.ContainsString("999"))
+}
+
+func TestCountingFilter(t *testing.T) {
+	f := NewCountingWithEstimates(100, 0.01)
+	f.AddString("a")
+	f.AddString("a")
+	f.Add([]byte("b"))
+	assert.True(t, f.RemoveString("a"))
+	assert.True(t, f.ContainsString("a"))
+	assert.True(t, f.Remove([]byte("a")))
+	assert.False(t, f.ContainsString("a"))
+	assert.False(t, f.RemoveString("a"))
+	assert.True(t, f.ContainsString("b"))
+
+	data, err := f.MarshalBinary()
+	require.NoError(t, err)
+	g, err := (*CountingFilter)(nil).UnmarshalBinary(data)
+	require.NoError(t, err)
+	assert.Equal(t, f, g)
+
+	s := NewSyncCounting(f.Cap(), f.K())
+	_, err = s.UnmarshalBinary(data)
+	require.NoError(t, err)
+	assert.True(t, s.ContainsString("b"))
+	assert.True(t, s.Remove([]byte("b")))
+	assert.False(t, s.Contains([]byte("b")))
+}
+
+func BenchmarkFilter(b *testing.B) {
+	keys := make([]string, 1<<16)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	b.Run("Add", func(b *testing.B) {
+		f := NewWithEstimates(uint64(len(keys)), 0.01)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			f.AddString(keys[i&(len(keys)-1)])
+		}
+	})
+	b.Run("Contains", func(b *testing.B) {
+		f := NewWithEstimates(uint64(len(keys)), 0.01)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			f.ContainsString(keys[i&(len(keys)-1)])
+		}
+	})
+	b.Run("SyncParallel", func(b *testing.B) {
+		f := NewSyncWithEstimates(uint64(len(keys)), 0.01)
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				if i++; i&3 == 0 {
+					f.AddString(keys[i&(len(keys)-1)])
+				} else {
+					f.ContainsString(keys[i&(len(keys)-1)])
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,373 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+// Package cuckoo provides Cuckoo filter, an approximate membership structure
+// that support removing and may report false positive but never false negative.
+package cuckoo
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"sync"
+	"unsafe"
+
+	"github.com/alimy/tryst/internal/wyhash"
+	"github.com/alimy/tryst/lang/fastrand"
+)
+
+const (
+	_bucketSize = 4
+	_maxKicks   = 500
+	// load factor that a filter with 4-entry buckets could reach
+	_loadFactor = 0.95
+
+	_minFingerprintBits = 4
+	_maxFingerprintBits = 16
+
+	_kindFilter byte = 1
+	_version    byte = 1
+	_headerLen       = 2 + 1 + 8 + 8 + 1 + 8 + 2
+)
+
+// ErrInvalidData data to unmarshal is invalid
+var ErrInvalidData = errors.New("cuckoo: invalid data")
+
+// EstimateParameters return buckets count and fingerprint bits of a filter that
+// has false positive rate fp with n elements
+func EstimateParameters(n uint64, fp float64) (buckets uint64, fpBits uint8) {
+	fp = min(max(fp, 1e-12), 1)
+	b := math.Ceil(math.Log2(2 * _bucketSize / fp))
+	fpBits = uint8(min(max(b, _minFingerprintBits), _maxFingerprintBits))
+	buckets = uint64(math.Ceil(float64(max(n, 1)) / _bucketSize / _loadFactor))
+	return
+}
+
+func bytesToString(data []byte) string {
+	return unsafe.String(unsafe.SliceData(data), len(data))
+}
+
+// victim the fingerprint kicked out when the filter is full
+type victim struct {
+	used  bool
+	index uint64
+	fp    uint16
+}
+
+// Filter Cuckoo filter, it's not safe for concurrent use
+type Filter struct {
+	mask   uint64 // buckets count - 1
+	fpBits uint8
+	fpMask uint64
+	count  uint64
+	victim victim
+	slots  []uint16 // fingerprints of buckets, zero means empty
+}
+
+// New create a Filter with buckets count rounded up to power of two, and
+// fingerprints of fpBits bits in [4, 16]
+func New(buckets uint64, fpBits uint8) *Filter {
+	buckets = 1 << bits.Len64(max(buckets, 1)-1)
+	fpBits = min(max(fpBits, _minFingerprintBits), _maxFingerprintBits)
+	return &Filter{
+		mask:   buckets - 1,
+		fpBits: fpBits,
+		fpMask: 1<<fpBits - 1,
+		slots:  make([]uint16, buckets*_bucketSize),
+	}
+}
+
+// NewWithEstimates create a Filter that has false positive rate fp with n elements
+func NewWithEstimates(n uint64, fp float64) *Filter {
+	return New(EstimateParameters(n, fp))
+}
+
+// Cap return max count of fingerprints the filter could hold
+func (f *Filter) Cap() uint64 {
+	return uint64(len(f.slots))
+}
+
+// Count return count of data in the filter
+func (f *Filter) Count() uint64 {
+	return f.count
+}
+
+func (f *Filter) locate(data string) (i1 uint64, fp uint16) {
+	h := wyhash.Sum64String(data)
+	// fingerprint use the high bits that independent of the index
+	if fp = uint16((h >> 32) & f.fpMask); fp == 0 {
+		fp = 1
+	}
+	return h & f.mask, fp
+}
+
+// altIndex return the other bucket index of fp, it's an involution
+func (f *Filter) altIndex(i uint64, fp uint16) uint64 {
+	return (i ^ (uint64(fp) * 0x5bd1e995)) & f.mask
+}
+
+func (f *Filter) bucket(i uint64) []uint16 {
+	return f.slots[i*_bucketSize : (i+1)*_bucketSize]
+}
+
+func (f *Filter) insert(i uint64, fp uint16) bool {
+	for j, slot := range f.bucket(i) {
+		if slot == 0 {
+			f.slots[i*_bucketSize+uint64(j)] = fp
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter) delete(i uint64, fp uint16) bool {
+	for j, slot := range f.bucket(i) {
+		if slot == fp {
+			f.slots[i*_bucketSize+uint64(j)] = 0
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter) has(i uint64, fp uint16) bool {
+	for _, slot := range f.bucket(i) {
+		if slot == fp {
+			return true
+		}
+	}
+	return false
+}
+
+// Add add data to the filter, it return false if the filter is full
+func (f *Filter) Add(data []byte) bool {
+	return f.AddString(bytesToString(data))
+}
+
+// AddString add data to the filter, it return false if the filter is full
+func (f *Filter) AddString(data string) bool {
+	if f.victim.used {
+		return false
+	}
+	i, fp := f.locate(data)
+	f.addFingerprint(i, fp)
+	return true
+}
+
+// addFingerprint add fp to bucket i or its alternate, kick out fingerprints
+// to their alternate buckets if both are full, and keep the one kicked out
+// last as victim so that no data is lost.
+func (f *Filter) addFingerprint(i uint64, fp uint16) {
+	f.count++
+	if f.insert(i, fp) || f.insert(f.altIndex(i, fp), fp) {
+		return
+	}
+	if fastrand.Uint32n(2) == 0 {
+		i = f.altIndex(i, fp)
+	}
+	for range _maxKicks {
+		j := i*_bucketSize + uint64(fastrand.Uint32n(_bucketSize))
+		fp, f.slots[j] = f.slots[j], fp
+		if i = f.altIndex(i, fp); f.insert(i, fp) {
+			return
+		}
+	}
+	f.victim = victim{used: true, index: i, fp: fp}
+}
+
+// Contains return true if data may be in the filter
+func (f *Filter) Contains(data []byte) bool {
+	return f.ContainsString(bytesToString(data))
+}
+
+// ContainsString return true if data may be in the filter
+func (f *Filter) ContainsString(data string) bool {
+	i1, fp := f.locate(data)
+	i2 := f.altIndex(i1, fp)
+	if f.has(i1, fp) || f.has(i2, fp) {
+		return true
+	}
+	return f.victim.used && f.victim.fp == fp && (f.victim.index == i1 || f.victim.index == i2)
+}
+
+// Remove remove data that added before from the filter, it return false if
+// data is not in the filter. Removing data never added may remove other data.
+func (f *Filter) Remove(data []byte) bool {
+	return f.RemoveString(bytesToString(data))
+}
+
+// RemoveString remove data that added before from the filter, it return false if
+// data is not in the filter. Removing data never added may remove other data.
+func (f *Filter) RemoveString(data string) bool {
+	i1, fp := f.locate(data)
+	i2 := f.altIndex(i1, fp)
+	switch {
+	case f.delete(i1, fp) || f.delete(i2, fp):
+		// try to move victim back into the freed slot
+		if v := f.victim; v.used {
+			f.victim = victim{}
+			f.count--
+			f.addFingerprint(v.index, v.fp)
+		}
+	case f.victim.used && f.victim.fp == fp && (f.victim.index == i1 || f.victim.index == i2):
+		f.victim = victim{}
+	default:
+		return false
+	}
+	f.count--
+	return true
+}
+
+// Reset remove all data from the filter
+func (f *Filter) Reset() {
+	clear(f.slots)
+	f.count, f.victim = 0, victim{}
+}
+
+// MarshalBinary encode the filter
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, _headerLen+2*len(f.slots))
+	data = append(data, _kindFilter, _version, f.fpBits)
+	data = binary.LittleEndian.AppendUint64(data, f.mask+1)
+	data = binary.LittleEndian.AppendUint64(data, f.count)
+	used := byte(0)
+	if f.victim.used {
+		used = 1
+	}
+	data = append(data, used)
+	data = binary.LittleEndian.AppendUint64(data, f.victim.index)
+	data = binary.LittleEndian.AppendUint16(data, f.victim.fp)
+	for _, slot := range f.slots {
+		data = binary.LittleEndian.AppendUint16(data, slot)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decode data to f, or to a new Filter if f is nil
+func (f *Filter) UnmarshalBinary(data []byte) (*Filter, error) {
+	if len(data) < _headerLen || data[0] != _kindFilter || data[1] != _version {
+		return nil, ErrInvalidData
+	}
+	fpBits, buckets := data[2], binary.LittleEndian.Uint64(data[3:])
+	if fpBits < _minFingerprintBits || fpBits > _maxFingerprintBits ||
+		buckets == 0 || buckets&(buckets-1) != 0 || buckets > math.MaxUint64/(2*_bucketSize) ||
+		uint64(len(data)-_headerLen) != 2*_bucketSize*buckets {
+		return nil, ErrInvalidData
+	}
+	if f == nil {
+		f = &Filter{}
+	}
+	*f = *New(buckets, fpBits)
+	f.count = binary.LittleEndian.Uint64(data[11:])
+	f.victim = victim{
+		used:  data[19] == 1,
+		index: binary.LittleEndian.Uint64(data[20:]) & f.mask,
+		fp:    binary.LittleEndian.Uint16(data[28:]),
+	}
+	body := data[_headerLen:]
+	for i := range f.slots {
+		f.slots[i] = binary.LittleEndian.Uint16(body[2*i:])
+	}
+	return f, nil
+}
+
+// SyncFilter Cuckoo filter that safe for concurrent use
+type SyncFilter struct {
+	mu sync.RWMutex
+	f  Filter
+}
+
+// NewSync create a SyncFilter with buckets count rounded up to power of two,
+// and fingerprints of fpBits bits in [4, 16]
+func NewSync(buckets uint64, fpBits uint8) *SyncFilter {
+	return &SyncFilter{f: *New(buckets, fpBits)}
+}
+
+// NewSyncWithEstimates create a SyncFilter that has false positive rate fp
+// with n elements
+func NewSyncWithEstimates(n uint64, fp float64) *SyncFilter {
+	return NewSync(EstimateParameters(n, fp))
+}
+
+// Cap return max count of fingerprints the filter could hold
+func (f *SyncFilter) Cap() uint64 {
+	return f.f.Cap()
+}
+
+// Count return count of data in the filter
+func (f *SyncFilter) Count() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.f.count
+}
+
+// Add add data to the filter, it return false if the filter is full
+func (f *SyncFilter) Add(data []byte) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.f.Add(data)
+}
+
+// AddString add data to the filter, it return false if the filter is full
+func (f *SyncFilter) AddString(data string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.f.AddString(data)
+}
+
+// Contains return true if data may be in the filter
+func (f *SyncFilter) Contains(data []byte) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.f.Contains(data)
+}
+
+// ContainsString return true if data may be in the filter
+func (f *SyncFilter) ContainsString(data string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.f.ContainsString(data)
+}
+
+// Remove remove data that added before from the filter
+func (f *SyncFilter) Remove(data []byte) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.f.Remove(data)
+}
+
+// RemoveString remove data that added before from the filter
+func (f *SyncFilter) RemoveString(data string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.f.RemoveString(data)
+}
+
+// Reset remove all data from the filter
+func (f *SyncFilter) Reset() {
+	f.mu.Lock()
+	f.f.Reset()
+	f.mu.Unlock()
+}
+
+// MarshalBinary encode the filter
+func (f *SyncFilter) MarshalBinary() ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.f.MarshalBinary()
+}
+
+// UnmarshalBinary decode data to f, or to a new SyncFilter if f is nil
+func (f *SyncFilter) UnmarshalBinary(data []byte) (*SyncFilter, error) {
+	if f == nil {
+		f = &SyncFilter{}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.f.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package cuckoo
+
+import (
+	"encoding/binary"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/alimy/tryst/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateParameters(t *testing.T) {
+	buckets, fpBits := EstimateParameters(1000, 0.01)
+	assert.Equal(t, uint64(264), buckets)
+	assert.Equal(t, uint8(10), fpBits)
+	_, fpBits = EstimateParameters(1000, 1e-9)
+	assert.Equal(t, uint8(16), fpBits)
+}
+
+func TestFilter(t *testing.T) {
+	const n = 10000
+	f := NewWithEstimates(n, 0.01)
+	for i := range n {
+		assert.True(t, f.AddString(strconv.Itoa(i)))
+	}
+	assert.Equal(t, uint64(n), f.Count())
+	for i := range n {
+		assert.True(t, f.Contains([]byte(strconv.Itoa(i))))
+	}
+	fp := 0
+	for i := n; i < 2*n; i++ {
+		if f.ContainsString(strconv.Itoa(i)) {
+			fp++
+		}
+	}
+	assert.Less(t, fp, n*2/100)
+
+	data, err := f.MarshalBinary()
+	require.NoError(t, err)
+	g, err := (*Filter)(nil).UnmarshalBinary(data)
+	require.NoError(t, err)
+	assert.Equal(t, f, g)
+	_, err = g.UnmarshalBinary(data[:len(data)-2])
+	assert.ErrorIs(t, err, ErrInvalidData)
+	// size of buckets near 2^64 overflow to an empty body
+	bad := append([]byte{}, data[:_headerLen]...)
+	for _, buckets := range []uint64{0, 1 << 63, 1 << 61} {
+		binary.LittleEndian.PutUint64(bad[3:], buckets)
+		_, err = (*Filter)(nil).UnmarshalBinary(bad)
+		assert.ErrorIs(t, err, ErrInvalidData)
+	}
+
+	for i := range n / 2 {
+		assert.True(t, f.Remove([]byte(strconv.Itoa(i))))
+	}
+	assert.Equal(t, uint64(n/2), f.Count())
+	for i := n / 2; i < n; i++ {
+		assert.True(t, f.ContainsString(strconv.Itoa(i)))
+	}
+
+	f.Reset()
+	assert.Zero(t, f.Count())
+	assert.False(t, f.RemoveString("0"))
+}
+
+func TestFilterFull(t *testing.T) {
+	f := New(4, 8)
+	added := 0
+	for i := 0; f.AddString(strconv.Itoa(i)); i++ {
+		added++
+	}
+	assert.Equal(t, uint64(added), f.Count())
+	assert.True(t, f.victim.used)
+	// no data is lost even if the filter is full
+	for i := range added {
+		assert.True(t, f.ContainsString(strconv.Itoa(i)))
+	}
+	// the victim is re-inserted after removing
+	assert.True(t, f.RemoveString("0"))
+	assert.False(t, f.victim.used)
+	for i := 1; i < added; i++ {
+		assert.True(t, f.ContainsString(strconv.Itoa(i)))
+	}
+}
+
+func TestSyncFilter(t *testing.T) {
+	f := NewSyncWithEstimates(1000, 0.001)
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := g; i < 1000; i += 4 {
+				assert.True(t, f.AddString(strconv.Itoa(i)))
+				assert.True(t, f.ContainsString(strconv.Itoa(i)))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, uint64(1000), f.Count())
+
+	b := types.Binary[*SyncFilter]{Data: f}
+	v, err := b.Value()
+	require.NoError(t, err)
+	var r types.Binary[*SyncFilter]
+	require.NoError(t, r.Scan(v))
+	assert.Equal(t, f.Cap(), r.Data.Cap())
+	assert.True(t, r.Data.RemoveString("999"))
+	assert.Equal(t, uint64(999), r.Data.Count())
+}
+
+func BenchmarkFilter(b *testing.B) {
+	keys := make([]string, 1<<16)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	b.Run("Contains", func(b *testing.B) {
+		f := NewWithEstimates(uint64(len(keys)), 0.01)
+		for _, key := range keys {
+			f.AddString(key)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			f.ContainsString(keys[i&(len(keys)-1)])
+		}
+	})
+	b.Run("AddRemove", func(b *testing.B) {
+		f := NewWithEstimates(uint64(len(keys)), 0.01)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			key := keys[i&(len(keys)-1)]
+			f.AddString(key)
+			f.RemoveString(key)
+		}
+	})
+}
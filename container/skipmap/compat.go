@@ -0,0 +1,260 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package skipmap
+
+// The maps below are kept for compatibility, they were generated for every
+// key type before Map[K, V] is introduced and store values of any type.
+
+// Float32Map represents a float32 map in ascending order.
+//
+// Deprecated: use Map[float32, V] instead.
+type Float32Map = Map[float32, any]
+
+// NewFloat32 return an empty float32 skipmap in ascending order.
+//
+// Deprecated: use New[float32, V] instead.
+func NewFloat32() *Float32Map {
+	return New[float32, any]()
+}
+
+// Float32MapDesc represents a float32 map in descending order.
+//
+// Deprecated: use Map[float32, V] instead.
+type Float32MapDesc = Map[float32, any]
+
+// NewFloat32Desc return an empty float32 skipmap in descending order.
+//
+// Deprecated: use NewDesc[float32, V] instead.
+func NewFloat32Desc() *Float32MapDesc {
+	return NewDesc[float32, any]()
+}
+
+// Float64Map represents a float64 map in ascending order.
+//
+// Deprecated: use Map[float64, V] instead.
+type Float64Map = Map[float64, any]
+
+// NewFloat64 return an empty float64 skipmap in ascending order.
+//
+// Deprecated: use New[float64, V] instead.
+func NewFloat64() *Float64Map {
+	return New[float64, any]()
+}
+
+// Float64MapDesc represents a float64 map in descending order.
+//
+// Deprecated: use Map[float64, V] instead.
+type Float64MapDesc = Map[float64, any]
+
+// NewFloat64Desc return an empty float64 skipmap in descending order.
+//
+// Deprecated: use NewDesc[float64, V] instead.
+func NewFloat64Desc() *Float64MapDesc {
+	return NewDesc[float64, any]()
+}
+
+// Int64Map represents a int64 map in ascending order.
+//
+// Deprecated: use Map[int64, V] instead.
+type Int64Map = Map[int64, any]
+
+// NewInt64 return an empty int64 skipmap in ascending order.
+//
+// Deprecated: use New[int64, V] instead.
+func NewInt64() *Int64Map {
+	return New[int64, any]()
+}
+
+// Int64MapDesc represents a int64 map in descending order.
+//
+// Deprecated: use Map[int64, V] instead.
+type Int64MapDesc = Map[int64, any]
+
+// NewInt64Desc return an empty int64 skipmap in descending order.
+//
+// Deprecated: use NewDesc[int64, V] instead.
+func NewInt64Desc() *Int64MapDesc {
+	return NewDesc[int64, any]()
+}
+
+// Int32Map represents a int32 map in ascending order.
+//
+// Deprecated: use Map[int32, V] instead.
+type Int32Map = Map[int32, any]
+
+// NewInt32 return an empty int32 skipmap in ascending order.
+//
+// Deprecated: use New[int32, V] instead.
+func NewInt32() *Int32Map {
+	return New[int32, any]()
+}
+
+// Int32MapDesc represents a int32 map in descending order.
+//
+// Deprecated: use Map[int32, V] instead.
+type Int32MapDesc = Map[int32, any]
+
+// NewInt32Desc return an empty int32 skipmap in descending order.
+//
+// Deprecated: use NewDesc[int32, V] instead.
+func NewInt32Desc() *Int32MapDesc {
+	return NewDesc[int32, any]()
+}
+
+// Int16Map represents a int16 map in ascending order.
+//
+// Deprecated: use Map[int16, V] instead.
+type Int16Map = Map[int16, any]
+
+// NewInt16 return an empty int16 skipmap in ascending order.
+//
+// Deprecated: use New[int16, V] instead.
+func NewInt16() *Int16Map {
+	return New[int16, any]()
+}
+
+// Int16MapDesc represents a int16 map in descending order.
+//
+// Deprecated: use Map[int16, V] instead.
+type Int16MapDesc = Map[int16, any]
+
+// NewInt16Desc return an empty int16 skipmap in descending order.
+//
+// Deprecated: use NewDesc[int16, V] instead.
+func NewInt16Desc() *Int16MapDesc {
+	return NewDesc[int16, any]()
+}
+
+// IntMap represents a int map in ascending order.
+//
+// Deprecated: use Map[int, V] instead.
+type IntMap = Map[int, any]
+
+// NewInt return an empty int skipmap in ascending order.
+//
+// Deprecated: use New[int, V] instead.
+func NewInt() *IntMap {
+	return New[int, any]()
+}
+
+// IntMapDesc represents a int map in descending order.
+//
+// Deprecated: use Map[int, V] instead.
+type IntMapDesc = Map[int, any]
+
+// NewIntDesc return an empty int skipmap in descending order.
+//
+// Deprecated: use NewDesc[int, V] instead.
+func NewIntDesc() *IntMapDesc {
+	return NewDesc[int, any]()
+}
+
+// Uint64Map represents a uint64 map in ascending order.
+//
+// Deprecated: use Map[uint64, V] instead.
+type Uint64Map = Map[uint64, any]
+
+// NewUint64 return an empty uint64 skipmap in ascending order.
+//
+// Deprecated: use New[uint64, V] instead.
+func NewUint64() *Uint64Map {
+	return New[uint64, any]()
+}
+
+// Uint64MapDesc represents a uint64 map in descending order.
+//
+// Deprecated: use Map[uint64, V] instead.
+type Uint64MapDesc = Map[uint64, any]
+
+// NewUint64Desc return an empty uint64 skipmap in descending order.
+//
+// Deprecated: use NewDesc[uint64, V] instead.
+func NewUint64Desc() *Uint64MapDesc {
+	return NewDesc[uint64, any]()
+}
+
+// Uint32Map represents a uint32 map in ascending order.
+//
+// Deprecated: use Map[uint32, V] instead.
+type Uint32Map = Map[uint32, any]
+
+// NewUint32 return an empty uint32 skipmap in ascending order.
+//
+// Deprecated: use New[uint32, V] instead.
+func NewUint32() *Uint32Map {
+	return New[uint32, any]()
+}
+
+// Uint32MapDesc represents a uint32 map in descending order.
+//
+// Deprecated: use Map[uint32, V] instead.
+type Uint32MapDesc = Map[uint32, any]
+
+// NewUint32Desc return an empty uint32 skipmap in descending order.
+//
+// Deprecated: use NewDesc[uint32, V] instead.
+func NewUint32Desc() *Uint32MapDesc {
+	return NewDesc[uint32, any]()
+}
+
+// Uint16Map represents a uint16 map in ascending order.
+//
+// Deprecated: use Map[uint16, V] instead.
+type Uint16Map = Map[uint16, any]
+
+// NewUint16 return an empty uint16 skipmap in ascending order.
+//
+// Deprecated: use New[uint16, V] instead.
+func NewUint16() *Uint16Map {
+	return New[uint16, any]()
+}
+
+// Uint16MapDesc represents a uint16 map in descending order.
+//
+// Deprecated: use Map[uint16, V] instead.
+type Uint16MapDesc = Map[uint16, any]
+
+// NewUint16Desc return an empty uint16 skipmap in descending order.
+//
+// Deprecated: use NewDesc[uint16, V] instead.
+func NewUint16Desc() *Uint16MapDesc {
+	return NewDesc[uint16, any]()
+}
+
+// UintMap represents a uint map in ascending order.
+//
+// Deprecated: use Map[uint, V] instead.
+type UintMap = Map[uint, any]
+
+// NewUint return an empty uint skipmap in ascending order.
+//
+// Deprecated: use New[uint, V] instead.
+func NewUint() *UintMap {
+	return New[uint, any]()
+}
+
+// UintMapDesc represents a uint map in descending order.
+//
+// Deprecated: use Map[uint, V] instead.
+type UintMapDesc = Map[uint, any]
+
+// NewUintDesc return an empty uint skipmap in descending order.
+//
+// Deprecated: use NewDesc[uint, V] instead.
+func NewUintDesc() *UintMapDesc {
+	return NewDesc[uint, any]()
+}
+
+// StringMap represents a string map in ascending order.
+//
+// Deprecated: use Map[string, V] instead.
+type StringMap = Map[string, any]
+
+// NewString return an empty string skipmap in ascending order.
+//
+// Deprecated: use New[string, V] instead.
+func NewString() *StringMap {
+	return New[string, any]()
+}
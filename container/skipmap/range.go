@@ -0,0 +1,163 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package skipmap
+
+import (
+	"cmp"
+	"iter"
+	"sync/atomic"
+)
+
+// The ordered queries below follow the order of the map, "before" means less
+// than in ascending order and greater than in descending order. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// valid return true if n is fully linked and not marked
+func (n *node[K, V]) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
+// seek return the first node that not before key, or nil
+func (s *Map[K, V]) seek(key K) *node[K, V] {
+	score := s.score(key)
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && s.lessthan(succ, score, key) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after key, or before key if strict.
+// The header is returned if there is no such node.
+func (s *Map[K, V]) findLast(key K, strict bool) *node[K, V] {
+	score := s.score(key)
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (s.lessthan(succ, score, key) || !strict && succ.equal(key)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func nextValid[K cmp.Ordered, V any](x *node[K, V]) *node[K, V] {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Map[K, V]) prevValid(x *node[K, V]) *node[K, V] {
+	for x != s.header && !x.valid() {
+		// x is being inserted or deleted, it's treated as absent
+		x = s.findLast(x.key, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+func entry[K cmp.Ordered, V any](x *node[K, V]) (key K, value V, ok bool) {
+	if x == nil {
+		return key, value, false
+	}
+	return x.key, x.loadVal(), true
+}
+
+// Min return the first entry of the map, that is the entry of the smallest
+// key in ascending order.
+func (s *Map[K, V]) Min() (key K, value V, ok bool) {
+	return entry(nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last entry of the map, that is the entry of the largest
+// key in ascending order.
+func (s *Map[K, V]) Max() (key K, value V, ok bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return entry(s.prevValid(x))
+}
+
+// Floor return the last entry whose key is not after key, that is the entry
+// of the largest key less than or equal to key in ascending order.
+func (s *Map[K, V]) Floor(key K) (k K, value V, ok bool) {
+	return entry(s.prevValid(s.findLast(key, false)))
+}
+
+// Ceiling return the first entry whose key is not before key, that is the
+// entry of the smallest key greater than or equal to key in ascending order.
+func (s *Map[K, V]) Ceiling(key K) (k K, value V, ok bool) {
+	return entry(nextValid(s.seek(key)))
+}
+
+// PopMin delete the first entry of the map and return it.
+func (s *Map[K, V]) PopMin() (key K, value V, ok bool) {
+	for {
+		x := nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return key, value, false
+		}
+		// retry if it's deleted by others
+		if value, ok = s.LoadAndDelete(x.key); ok {
+			return x.key, value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each entry whose key is not before key.
+// If f returns false, range stops the iteration.
+func (s *Map[K, V]) RangeFrom(key K, f func(key K, value V) bool) {
+	s.From(key)(f)
+}
+
+// RangeBetween calls f sequentially for each entry whose key is in [lo, hi)
+// in the order of the map. If f returns false, range stops the iteration.
+func (s *Map[K, V]) RangeBetween(lo, hi K, f func(key K, value V) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over entries of the map.
+func (s *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over entries whose key is not before key.
+func (s *Map[K, V]) From(key K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for x := s.seek(key); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.key, x.loadVal()) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over entries whose key is in [lo, hi) in the
+// order of the map.
+func (s *Map[K, V]) Between(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		score := s.score(hi)
+		for x := s.seek(lo); x != nil && s.lessthan(x, score, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.key, x.loadVal()) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package skipmap
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func collect[K cmp.Ordered, V any](seq iter.Seq2[K, V]) (keys []K) {
+	for k := range seq {
+		keys = append(keys, k)
+	}
+	return
+}
+
+func TestOrderedQueries(t *testing.T) {
+	m := New[int, int]()
+	if _, _, ok := m.Min(); ok {
+		t.Fatal("want no Min of empty map")
+	}
+	if _, _, ok := m.Max(); ok {
+		t.Fatal("want no Max of empty map")
+	}
+	for i := 10; i <= 50; i += 10 {
+		m.Store(i, i*10)
+	}
+	for _, c := range []struct {
+		name    string
+		fn      func(int) (int, int, bool)
+		key     int
+		wantKey int
+		wantOK  bool
+	}{
+		{"Floor", m.Floor, 30, 30, true},
+		{"Floor", m.Floor, 35, 30, true},
+		{"Floor", m.Floor, 5, 0, false},
+		{"Floor", m.Floor, 100, 50, true},
+		{"Ceiling", m.Ceiling, 30, 30, true},
+		{"Ceiling", m.Ceiling, 35, 40, true},
+		{"Ceiling", m.Ceiling, 55, 0, false},
+		{"Ceiling", m.Ceiling, 0, 10, true},
+	} {
+		k, v, ok := c.fn(c.key)
+		if k != c.wantKey || ok != c.wantOK || ok && v != k*10 {
+			t.Errorf("%s(%d) want %d %t got %d %d %t", c.name, c.key, c.wantKey, c.wantOK, k, v, ok)
+		}
+	}
+	if k, v, ok := m.Min(); k != 10 || v != 100 || !ok {
+		t.Errorf("want Min 10 got %d %d %t", k, v, ok)
+	}
+	if k, v, ok := m.Max(); k != 50 || v != 500 || !ok {
+		t.Errorf("want Max 50 got %d %d %t", k, v, ok)
+	}
+	if keys := collect(m.From(25)); !slices.Equal(keys, []int{30, 40, 50}) {
+		t.Errorf("want From(25) keys [30 40 50] got %v", keys)
+	}
+	if keys := collect(m.Between(20, 40)); !slices.Equal(keys, []int{20, 30}) {
+		t.Errorf("want Between(20, 40) keys [20 30] got %v", keys)
+	}
+	if keys := collect(m.Between(40, 20)); len(keys) != 0 {
+		t.Errorf("want Between(40, 20) empty got %v", keys)
+	}
+	var keys []int
+	m.RangeBetween(10, 60, func(key, _ int) bool {
+		keys = append(keys, key)
+		return len(keys) < 2
+	})
+	if !slices.Equal(keys, []int{10, 20}) {
+		t.Errorf("want RangeBetween stopped after 2 keys got %v", keys)
+	}
+	keys = keys[:0]
+	m.RangeFrom(40, func(key, _ int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if !slices.Equal(keys, []int{40, 50}) {
+		t.Errorf("want RangeFrom(40) keys [40 50] got %v", keys)
+	}
+	for k := range m.All() {
+		if k == 30 {
+			break
+		}
+	}
+	for want := 10; want <= 50; want += 10 {
+		if k, v, ok := m.PopMin(); k != want || v != want*10 || !ok {
+			t.Errorf("want PopMin %d got %d %d %t", want, k, v, ok)
+		}
+	}
+	if _, _, ok := m.PopMin(); ok || m.Len() != 0 {
+		t.Errorf("want nothing to pop from empty map")
+	}
+}
+
+func TestOrderedQueriesDesc(t *testing.T) {
+	m := NewDesc[string, struct{}]()
+	for _, k := range []string{"b", "d", "f"} {
+		m.Store(k, struct{}{})
+	}
+	if k, _, _ := m.Floor("c"); k != "d" {
+		t.Errorf("want Floor(c) d got %q", k)
+	}
+	if k, _, _ := m.Ceiling("c"); k != "b" {
+		t.Errorf("want Ceiling(c) b got %q", k)
+	}
+	if k, _, _ := m.Min(); k != "f" {
+		t.Errorf("want Min f got %q", k)
+	}
+	if k, _, _ := m.Max(); k != "b" {
+		t.Errorf("want Max b got %q", k)
+	}
+	if keys := collect(m.Between("e", "a")); !slices.Equal(keys, []string{"d", "b"}) {
+		t.Errorf("want Between(e, a) keys [d b] got %v", keys)
+	}
+}
+
+func TestConcurrentOrderedQueries(t *testing.T) {
+	const n = 10000
+	m := New[int, int]()
+	for i := range n {
+		m.Store(i*2, i)
+	}
+	var (
+		wg     sync.WaitGroup
+		popped atomic.Int64
+		seen   sync.Map
+	)
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for {
+				k, _, ok := m.PopMin()
+				if !ok {
+					return
+				}
+				if _, dup := seen.LoadOrStore(k, true); dup {
+					t.Errorf("want key %d popped once but not", k)
+				}
+				// odd keys may be popped before they are deleted
+				if k%2 == 0 {
+					popped.Add(1)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := range n {
+				// odd keys are inserted and deleted at the same time
+				m.Store(i*2+1, i)
+				m.Delete(i*2 + 1)
+				prev := -1
+				for k := range m.From(i) {
+					if k <= prev {
+						t.Errorf("want keys in order but got %d after %d", k, prev)
+					}
+					prev = k
+					if k > i+100 {
+						break
+					}
+				}
+				if k, _, ok := m.Floor(i); ok && k > i {
+					t.Errorf("want Floor(%d) not greater than it but got %d", i, k)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for {
+		k, _, ok := m.PopMin()
+		if !ok {
+			break
+		}
+		if k%2 == 0 {
+			popped.Add(1)
+		}
+	}
+	if popped.Load() != n {
+		t.Errorf("want %d keys popped got %d", n, popped.Load())
+	}
+}
@@ -15,35 +15,54 @@
 // Package skipmap is a high-performance, scalable, concurrent-safe map based on skip-list.
 // In the typical pattern(100000 operations, 90%LOAD 9%STORE 1%DELETE, 8C16T), the skipmap
 // up to 10x faster than the built-in sync.Map.
-//
-//go:generate go run types_gen.go
 package skipmap
 
 import (
+	"cmp"
+	"encoding/binary"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"unsafe"
 )
 
-// Int64Map represents a map based on skip list in ascending order.
-type Int64Map struct {
+// Map[K, V] represents a map based on skip list, the keys are in ascending
+// order or the order of the comparator.
+type Map[K cmp.Ordered, V any] struct {
 	length       int64
 	highestLevel int64 // highest level for now
-	header       *int64Node
+	header       *node[K, V]
+	order        order
+	// less is the comparator of keys in orderFunc
+	less func(a, b K) bool
+	// prefixed is true if K is string that nodes are compared by prefix first
+	prefixed bool
 }
 
-type int64Node struct {
-	key   int64
-	value unsafe.Pointer // *any
-	next  optionalArray  // [level]*int64Node
+type order uint8
+
+const (
+	orderAsc order = iota
+	orderDesc
+	orderFunc
+)
+
+type node[K cmp.Ordered, V any] struct {
+	key K
+	// score the big-endian first 8 bytes of string key, zero for others. It's
+	// in the same order of keys, so most comparisons are of integers.
+	score uint64
+	value atomic.Pointer[V]
+	next  optionalArray // [level]*node[K, V]
 	mu    sync.Mutex
 	flags bitflag
 	level uint32
 }
 
-func newInt64Node(key int64, value any, level int) *int64Node {
-	node := &int64Node{
+func newNode[K cmp.Ordered, V any](key K, score uint64, value V, level int) *node[K, V] {
+	node := &node[K, V]{
 		key:   key,
+		score: score,
 		level: uint32(level),
 	}
 	node.storeVal(value)
@@ -53,56 +72,106 @@ func newInt64Node(key int64, value any, level int) *int64Node {
 	return node
 }
 
-func (n *int64Node) storeVal(value any) {
-	atomic.StorePointer(&n.value, unsafe.Pointer(&value))
+func (n *node[K, V]) storeVal(value V) {
+	n.value.Store(&value)
 }
 
-func (n *int64Node) loadVal() any {
-	return *(*any)(atomic.LoadPointer(&n.value))
+func (n *node[K, V]) loadVal() V {
+	return *n.value.Load()
 }
 
-func (n *int64Node) loadNext(i int) *int64Node {
-	return (*int64Node)(n.next.load(i))
+func (n *node[K, V]) loadNext(i int) *node[K, V] {
+	return (*node[K, V])(n.next.load(i))
 }
 
-func (n *int64Node) storeNext(i int, node *int64Node) {
+func (n *node[K, V]) storeNext(i int, node *node[K, V]) {
 	n.next.store(i, unsafe.Pointer(node))
 }
 
-func (n *int64Node) atomicLoadNext(i int) *int64Node {
-	return (*int64Node)(n.next.atomicLoad(i))
+func (n *node[K, V]) atomicLoadNext(i int) *node[K, V] {
+	return (*node[K, V])(n.next.atomicLoad(i))
 }
 
-func (n *int64Node) atomicStoreNext(i int, node *int64Node) {
+func (n *node[K, V]) atomicStoreNext(i int, node *node[K, V]) {
 	n.next.atomicStore(i, unsafe.Pointer(node))
 }
 
-func (n *int64Node) lessthan(key int64) bool {
-	return n.key < key
+func (n *node[K, V]) equal(key K) bool {
+	return n.key == key
 }
 
-func (n *int64Node) equal(key int64) bool {
-	return n.key == key
+// New[K, V] return an empty skipmap in ascending order.
+func New[K cmp.Ordered, V any]() *Map[K, V] {
+	return newMap[K, V](orderAsc, nil)
 }
 
-// NewInt64 return an empty int64 skipmap.
-func NewInt64() *Int64Map {
-	h := newInt64Node(0, "", maxLevel)
+// NewDesc[K, V] return an empty skipmap in descending order.
+func NewDesc[K cmp.Ordered, V any]() *Map[K, V] {
+	return newMap[K, V](orderDesc, nil)
+}
+
+// NewFunc[K, V] return an empty skipmap in the order of less, less must be a
+// strict weak ordering that consistent with ==. It's slower than New and
+// NewDesc since the keys are compared by calling less.
+func NewFunc[K cmp.Ordered, V any](less func(a, b K) bool) *Map[K, V] {
+	return newMap[K, V](orderFunc, less)
+}
+
+func newMap[K cmp.Ordered, V any](order order, less func(a, b K) bool) *Map[K, V] {
+	var (
+		key K
+		val V
+	)
+	h := newNode(key, 0, val, maxLevel)
 	h.flags.SetTrue(fullyLinked)
-	return &Int64Map{
+	return &Map[K, V]{
 		header:       h,
 		highestLevel: defaultHighestLevel,
+		order:        order,
+		less:         less,
+		prefixed:     order != orderFunc && reflect.TypeFor[K]().Kind() == reflect.String,
+	}
+}
+
+// score return score of key, see node.score
+func (s *Map[K, V]) score(key K) uint64 {
+	if !s.prefixed {
+		return 0
 	}
+	str := *(*string)(unsafe.Pointer(&key))
+	if len(str) >= 8 {
+		return binary.BigEndian.Uint64(unsafe.Slice(unsafe.StringData(str), 8))
+	}
+	var b [8]byte
+	copy(b[:], str)
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// lessthan return true if n is before key that has the score
+func (s *Map[K, V]) lessthan(n *node[K, V], score uint64, key K) bool {
+	switch s.order {
+	case orderAsc:
+		if n.score != score {
+			return n.score < score
+		}
+		return n.key < key
+	case orderDesc:
+		if n.score != score {
+			return n.score > score
+		}
+		return n.key > key
+	}
+	return s.less(n.key, key)
 }
 
 // findNode takes a key and two maximal-height arrays then searches exactly as in a sequential skipmap.
 // The returned preds and succs always satisfy preds[i] > key >= succs[i].
 // (without fullpath, if find the node will return immediately)
-func (s *Int64Map) findNode(key int64, preds *[maxLevel]*int64Node, succs *[maxLevel]*int64Node) *int64Node {
+func (s *Map[K, V]) findNode(key K, score uint64, preds *[maxLevel]*node[K, V], succs *[maxLevel]*node[K, V]) *node[K, V] {
 	x := s.header
 	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
 		succ := x.atomicLoadNext(i)
-		for succ != nil && succ.lessthan(key) {
+		for succ != nil && s.lessthan(succ, score, key) {
 			x = succ
 			succ = x.atomicLoadNext(i)
 		}
@@ -119,12 +188,12 @@ func (s *Int64Map) findNode(key int64, preds *[maxLevel]*int64Node, succs *[maxL
 
 // findNodeDelete takes a key and two maximal-height arrays then searches exactly as in a sequential skip-list.
 // The returned preds and succs always satisfy preds[i] > key >= succs[i].
-func (s *Int64Map) findNodeDelete(key int64, preds *[maxLevel]*int64Node, succs *[maxLevel]*int64Node) int {
+func (s *Map[K, V]) findNodeDelete(key K, score uint64, preds *[maxLevel]*node[K, V], succs *[maxLevel]*node[K, V]) int {
 	// lFound represents the index of the first layer at which it found a node.
 	lFound, x := -1, s.header
 	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
 		succ := x.atomicLoadNext(i)
-		for succ != nil && succ.lessthan(key) {
+		for succ != nil && s.lessthan(succ, score, key) {
 			x = succ
 			succ = x.atomicLoadNext(i)
 		}
@@ -139,8 +208,8 @@ func (s *Int64Map) findNodeDelete(key int64, preds *[maxLevel]*int64Node, succs
 	return lFound
 }
 
-func unlockInt64(preds [maxLevel]*int64Node, highestLevel int) {
-	var prevPred *int64Node
+func unlock[K cmp.Ordered, V any](preds [maxLevel]*node[K, V], highestLevel int) {
+	var prevPred *node[K, V]
 	for i := highestLevel; i >= 0; i-- {
 		if preds[i] != prevPred { // the node could be unlocked by previous loop
 			preds[i].mu.Unlock()
@@ -150,11 +219,12 @@ func unlockInt64(preds [maxLevel]*int64Node, highestLevel int) {
 }
 
 // Store sets the value for a key.
-func (s *Int64Map) Store(key int64, value any) {
+func (s *Map[K, V]) Store(key K, value V) {
+	score := s.score(key)
 	level := s.randomlevel()
-	var preds, succs [maxLevel]*int64Node
+	var preds, succs [maxLevel]*node[K, V]
 	for {
-		nodeFound := s.findNode(key, &preds, &succs)
+		nodeFound := s.findNode(key, score, &preds, &succs)
 		if nodeFound != nil { // indicating the key is already in the skip-list
 			if !nodeFound.flags.Get(marked) {
 				// We don't need to care about whether or not the node is fully linked,
@@ -171,7 +241,7 @@ func (s *Int64Map) Store(key int64, value any) {
 		var (
 			highestLocked        = -1 // the highest level being locked by this process
 			valid                = true
-			pred, succ, prevPred *int64Node
+			pred, succ, prevPred *node[K, V]
 		)
 		for layer := 0; valid && layer < level; layer++ {
 			pred = preds[layer]   // target node's previous node
@@ -188,23 +258,23 @@ func (s *Int64Map) Store(key int64, value any) {
 			valid = !pred.flags.Get(marked) && (succ == nil || !succ.flags.Get(marked)) && pred.loadNext(layer) == succ
 		}
 		if !valid {
-			unlockInt64(preds, highestLocked)
+			unlock(preds, highestLocked)
 			continue
 		}
 
-		nn := newInt64Node(key, value, level)
+		nn := newNode(key, score, value, level)
 		for layer := 0; layer < level; layer++ {
 			nn.storeNext(layer, succs[layer])
 			preds[layer].atomicStoreNext(layer, nn)
 		}
 		nn.flags.SetTrue(fullyLinked)
-		unlockInt64(preds, highestLocked)
+		unlock(preds, highestLocked)
 		atomic.AddInt64(&s.length, 1)
 		return
 	}
 }
 
-func (s *Int64Map) randomlevel() int {
+func (s *Map[K, V]) randomlevel() int {
 	// Generate random level.
 	level := randomLevel()
 	// Update highest level if possible.
@@ -220,41 +290,88 @@ func (s *Int64Map) randomlevel() int {
 	return level
 }
 
-// Load returns the value stored in the map for a key, or nil if no
+// Load returns the value stored in the map for a key, or the zero value if no
 // value is present.
 // The ok result indicates whether value was found in the map.
-func (s *Int64Map) Load(key int64) (value any, ok bool) {
+func (s *Map[K, V]) Load(key K) (value V, ok bool) {
+	var nex *node[K, V]
+	switch {
+	case s.prefixed || s.order == orderFunc:
+		nex = s.find(key)
+	case s.order == orderAsc:
+		nex = s.findAsc(key)
+	default:
+		nex = s.findDesc(key)
+	}
+	if nex != nil && nex.flags.MGet(fullyLinked|marked, fullyLinked) {
+		return nex.loadVal(), true
+	}
+	return value, false
+}
+
+// find return the highest node of key in the skip list, or nil if not found.
+func (s *Map[K, V]) find(key K) *node[K, V] {
+	score := s.score(key)
 	x := s.header
 	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
 		nex := x.atomicLoadNext(i)
-		for nex != nil && nex.lessthan(key) {
+		for nex != nil && s.lessthan(nex, score, key) {
 			x = nex
 			nex = x.atomicLoadNext(i)
 		}
-
-		// Check if the key already in the skip list.
 		if nex != nil && nex.equal(key) {
-			if nex.flags.MGet(fullyLinked|marked, fullyLinked) {
-				return nex.loadVal(), true
-			}
-			return nil, false
+			return nex
+		}
+	}
+	return nil
+}
+
+// findAsc is find of keys that compared directly in ascending order, the
+// comparison is inlined so it's as fast as the generated types.
+func (s *Map[K, V]) findAsc(key K) *node[K, V] {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		nex := x.atomicLoadNext(i)
+		for nex != nil && nex.key < key {
+			x = nex
+			nex = x.atomicLoadNext(i)
+		}
+		if nex != nil && nex.key == key {
+			return nex
 		}
 	}
-	return nil, false
+	return nil
+}
+
+// findDesc is findAsc in descending order.
+func (s *Map[K, V]) findDesc(key K) *node[K, V] {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		nex := x.atomicLoadNext(i)
+		for nex != nil && nex.key > key {
+			x = nex
+			nex = x.atomicLoadNext(i)
+		}
+		if nex != nil && nex.key == key {
+			return nex
+		}
+	}
+	return nil
 }
 
 // LoadAndDelete deletes the value for a key, returning the previous value if any.
 // The loaded result reports whether the key was present.
 // (Modified from Delete)
-func (s *Int64Map) LoadAndDelete(key int64) (value any, loaded bool) {
+func (s *Map[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	score := s.score(key)
 	var (
-		nodeToDelete *int64Node
+		nodeToDelete *node[K, V]
 		isMarked     bool // represents if this operation mark the node
 		topLayer     = -1
-		preds, succs [maxLevel]*int64Node
+		preds, succs [maxLevel]*node[K, V]
 	)
 	for {
-		lFound := s.findNodeDelete(key, &preds, &succs)
+		lFound := s.findNodeDelete(key, score, &preds, &succs)
 		if isMarked || // this process mark this node or we can find this node in the skip list
 			lFound != -1 && succs[lFound].flags.MGet(fullyLinked|marked, fullyLinked) && (int(succs[lFound].level)-1) == lFound {
 			if !isMarked { // we don't mark this node for now
@@ -265,7 +382,7 @@ func (s *Int64Map) LoadAndDelete(key int64) (value any, loaded bool) {
 					// The node is marked by another process,
 					// the physical deletion will be accomplished by another process.
 					nodeToDelete.mu.Unlock()
-					return nil, false
+					return value, false
 				}
 				nodeToDelete.flags.SetTrue(marked)
 				isMarked = true
@@ -274,7 +391,7 @@ func (s *Int64Map) LoadAndDelete(key int64) (value any, loaded bool) {
 			var (
 				highestLocked        = -1 // the highest level being locked by this process
 				valid                = true
-				pred, succ, prevPred *int64Node
+				pred, succ, prevPred *node[K, V]
 			)
 			for layer := 0; valid && (layer <= topLayer); layer++ {
 				pred, succ = preds[layer], succs[layer]
@@ -291,7 +408,7 @@ func (s *Int64Map) LoadAndDelete(key int64) (value any, loaded bool) {
 				valid = !pred.flags.Get(marked) && pred.loadNext(layer) == succ
 			}
 			if !valid {
-				unlockInt64(preds, highestLocked)
+				unlock(preds, highestLocked)
 				continue
 			}
 			for i := topLayer; i >= 0; i-- {
@@ -300,11 +417,11 @@ func (s *Int64Map) LoadAndDelete(key int64) (value any, loaded bool) {
 				preds[i].atomicStoreNext(i, nodeToDelete.loadNext(i))
 			}
 			nodeToDelete.mu.Unlock()
-			unlockInt64(preds, highestLocked)
+			unlock(preds, highestLocked)
 			atomic.AddInt64(&s.length, -1)
 			return nodeToDelete.loadVal(), true
 		}
-		return nil, false
+		return value, false
 	}
 }
 
@@ -312,14 +429,15 @@ func (s *Int64Map) LoadAndDelete(key int64) (value any, loaded bool) {
 // Otherwise, it stores and returns the given value.
 // The loaded result is true if the value was loaded, false if stored.
 // (Modified from Store)
-func (s *Int64Map) LoadOrStore(key int64, value any) (actual any, loaded bool) {
+func (s *Map[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	score := s.score(key)
 	var (
 		level        int
-		preds, succs [maxLevel]*int64Node
+		preds, succs [maxLevel]*node[K, V]
 		hl           = int(atomic.LoadInt64(&s.highestLevel))
 	)
 	for {
-		nodeFound := s.findNode(key, &preds, &succs)
+		nodeFound := s.findNode(key, score, &preds, &succs)
 		if nodeFound != nil { // indicating the key is already in the skip-list
 			if !nodeFound.flags.Get(marked) {
 				// We don't need to care about whether or not the node is fully linked,
@@ -335,7 +453,7 @@ func (s *Int64Map) LoadOrStore(key int64, value any) (actual any, loaded bool) {
 		var (
 			highestLocked        = -1 // the highest level being locked by this process
 			valid                = true
-			pred, succ, prevPred *int64Node
+			pred, succ, prevPred *node[K, V]
 		)
 		if level == 0 {
 			level = s.randomlevel()
@@ -362,17 +480,17 @@ func (s *Int64Map) LoadOrStore(key int64, value any) (actual any, loaded bool) {
 			valid = !pred.flags.Get(marked) && (succ == nil || !succ.flags.Get(marked)) && pred.loadNext(layer) == succ
 		}
 		if !valid {
-			unlockInt64(preds, highestLocked)
+			unlock(preds, highestLocked)
 			continue
 		}
 
-		nn := newInt64Node(key, value, level)
+		nn := newNode(key, score, value, level)
 		for layer := 0; layer < level; layer++ {
 			nn.storeNext(layer, succs[layer])
 			preds[layer].atomicStoreNext(layer, nn)
 		}
 		nn.flags.SetTrue(fullyLinked)
-		unlockInt64(preds, highestLocked)
+		unlock(preds, highestLocked)
 		atomic.AddInt64(&s.length, 1)
 		return value, false
 	}
@@ -382,14 +500,15 @@ func (s *Int64Map) LoadOrStore(key int64, value any) (actual any, loaded bool) {
 // Otherwise, it stores and returns the given value from f, f will only be called once.
 // The loaded result is true if the value was loaded, false if stored.
 // (Modified from LoadOrStore)
-func (s *Int64Map) LoadOrStoreLazy(key int64, f func() any) (actual any, loaded bool) {
+func (s *Map[K, V]) LoadOrStoreLazy(key K, f func() V) (actual V, loaded bool) {
+	score := s.score(key)
 	var (
 		level        int
-		preds, succs [maxLevel]*int64Node
+		preds, succs [maxLevel]*node[K, V]
 		hl           = int(atomic.LoadInt64(&s.highestLevel))
 	)
 	for {
-		nodeFound := s.findNode(key, &preds, &succs)
+		nodeFound := s.findNode(key, score, &preds, &succs)
 		if nodeFound != nil { // indicating the key is already in the skip-list
 			if !nodeFound.flags.Get(marked) {
 				// We don't need to care about whether or not the node is fully linked,
@@ -405,7 +524,7 @@ func (s *Int64Map) LoadOrStoreLazy(key int64, f func() any) (actual any, loaded
 		var (
 			highestLocked        = -1 // the highest level being locked by this process
 			valid                = true
-			pred, succ, prevPred *int64Node
+			pred, succ, prevPred *node[K, V]
 		)
 		if level == 0 {
 			level = s.randomlevel()
@@ -432,32 +551,33 @@ func (s *Int64Map) LoadOrStoreLazy(key int64, f func() any) (actual any, loaded
 			valid = !pred.flags.Get(marked) && pred.loadNext(layer) == succ && (succ == nil || !succ.flags.Get(marked))
 		}
 		if !valid {
-			unlockInt64(preds, highestLocked)
+			unlock(preds, highestLocked)
 			continue
 		}
 		value := f()
-		nn := newInt64Node(key, value, level)
+		nn := newNode(key, score, value, level)
 		for layer := 0; layer < level; layer++ {
 			nn.storeNext(layer, succs[layer])
 			preds[layer].atomicStoreNext(layer, nn)
 		}
 		nn.flags.SetTrue(fullyLinked)
-		unlockInt64(preds, highestLocked)
+		unlock(preds, highestLocked)
 		atomic.AddInt64(&s.length, 1)
 		return value, false
 	}
 }
 
 // Delete deletes the value for a key.
-func (s *Int64Map) Delete(key int64) bool {
+func (s *Map[K, V]) Delete(key K) bool {
+	score := s.score(key)
 	var (
-		nodeToDelete *int64Node
+		nodeToDelete *node[K, V]
 		isMarked     bool // represents if this operation mark the node
 		topLayer     = -1
-		preds, succs [maxLevel]*int64Node
+		preds, succs [maxLevel]*node[K, V]
 	)
 	for {
-		lFound := s.findNodeDelete(key, &preds, &succs)
+		lFound := s.findNodeDelete(key, score, &preds, &succs)
 		if isMarked || // this process mark this node or we can find this node in the skip list
 			lFound != -1 && succs[lFound].flags.MGet(fullyLinked|marked, fullyLinked) && (int(succs[lFound].level)-1) == lFound {
 			if !isMarked { // we don't mark this node for now
@@ -477,7 +597,7 @@ func (s *Int64Map) Delete(key int64) bool {
 			var (
 				highestLocked        = -1 // the highest level being locked by this process
 				valid                = true
-				pred, succ, prevPred *int64Node
+				pred, succ, prevPred *node[K, V]
 			)
 			for layer := 0; valid && (layer <= topLayer); layer++ {
 				pred, succ = preds[layer], succs[layer]
@@ -494,7 +614,7 @@ func (s *Int64Map) Delete(key int64) bool {
 				valid = !pred.flags.Get(marked) && pred.atomicLoadNext(layer) == succ
 			}
 			if !valid {
-				unlockInt64(preds, highestLocked)
+				unlock(preds, highestLocked)
 				continue
 			}
 			for i := topLayer; i >= 0; i-- {
@@ -503,7 +623,7 @@ func (s *Int64Map) Delete(key int64) bool {
 				preds[i].atomicStoreNext(i, nodeToDelete.loadNext(i))
 			}
 			nodeToDelete.mu.Unlock()
-			unlockInt64(preds, highestLocked)
+			unlock(preds, highestLocked)
 			atomic.AddInt64(&s.length, -1)
 			return true
 		}
@@ -518,7 +638,7 @@ func (s *Int64Map) Delete(key int64) bool {
 // contents: no key will be visited more than once, but if the value for any key
 // is stored or deleted concurrently, Range may reflect any mapping for that key
 // from any point during the Range call.
-func (s *Int64Map) Range(f func(key int64, value any) bool) {
+func (s *Map[K, V]) Range(f func(key K, value V) bool) {
 	x := s.header.atomicLoadNext(0)
 	for x != nil {
 		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
@@ -533,6 +653,6 @@ func (s *Int64Map) Range(f func(key int64, value any) bool) {
 }
 
 // Len return the length of this skipmap.
-func (s *Int64Map) Len() int {
+func (s *Map[K, V]) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
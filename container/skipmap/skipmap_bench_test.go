@@ -38,6 +38,16 @@ func BenchmarkStore(b *testing.B) {
 			}
 		})
 	})
+	b.Run("skipmap-typed", func(b *testing.B) {
+		l := New[int64, int64]()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				v := int64(fastrand.Uint32n(randN))
+				l.Store(v, v)
+			}
+		})
+	})
 	b.Run("sync.Map", func(b *testing.B) {
 		var l sync.Map
 		b.ResetTimer()
@@ -62,6 +72,18 @@ func BenchmarkLoad100Hits(b *testing.B) {
 			}
 		})
 	})
+	b.Run("skipmap-typed", func(b *testing.B) {
+		l := New[int64, int64]()
+		for i := 0; i < initsize; i++ {
+			l.Store(int64(i), int64(i))
+		}
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_, _ = l.Load(int64(fastrand.Uint32n(initsize)))
+			}
+		})
+	})
 	b.Run("sync.Map", func(b *testing.B) {
 		var l sync.Map
 		for i := 0; i < initsize; i++ {
@@ -18,6 +18,7 @@ import (
 	"math/rand"
 	"reflect"
 	"runtime"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -277,6 +278,84 @@ func TestSkipMapDesc(t *testing.T) {
 	})
 }
 
+func TestTypedMap(t *testing.T) {
+	m := New[string, int64]()
+	for _, k := range []string{"b", "c", "a"} {
+		m.Store(k, int64(k[0]))
+	}
+	if v, ok := m.Load("a"); !ok || v != 'a' {
+		t.Fatalf("want Load(a) = %d got %d %t", 'a', v, ok)
+	}
+	if v, ok := m.Load("d"); ok || v != 0 {
+		t.Fatalf("want zero value of missing key got %d %t", v, ok)
+	}
+	var calls int
+	for range 2 {
+		v, loaded := m.LoadOrStoreLazy("d", func() int64 {
+			calls++
+			return 'd'
+		})
+		if v != 'd' || calls != 1 {
+			t.Fatalf("want LoadOrStoreLazy(d) = %d with f called once got %d %t %d", 'd', v, loaded, calls)
+		}
+	}
+	var keys []string
+	m.Range(func(key string, _ int64) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c", "d"}) {
+		t.Fatalf("want keys in lexicographic order got %v", keys)
+	}
+
+	// keys share the prefix compared first
+	asc, desc := New[string, int](), NewDesc[string, int]()
+	want := []string{"", "a", "a\x00", "a\x00b", "abcdefgh", "abcdefgh0", "abcdefgh1", "b"}
+	for i, k := range want {
+		asc.Store(k, i)
+		desc.Store(k, i)
+	}
+	for _, m := range []*Map[string, int]{asc, desc} {
+		keys = keys[:0]
+		m.Range(func(key string, v int) bool {
+			keys = append(keys, key)
+			return want[v] == key
+		})
+		if m == desc {
+			slices.Reverse(keys)
+		}
+		if !reflect.DeepEqual(keys, want) {
+			t.Fatalf("want keys %q got %q", want, keys)
+		}
+	}
+
+	// order of comparator
+	byLen := NewFunc[string, struct{}](func(a, b string) bool {
+		return len(a) < len(b) || len(a) == len(b) && a < b
+	})
+	descFloat := NewDesc[float64, int]()
+	for _, k := range []string{"ccc", "a", "bb", "aa"} {
+		byLen.Store(k, struct{}{})
+		descFloat.Store(float64(len(k))/2, len(k))
+	}
+	keys = keys[:0]
+	byLen.Range(func(key string, _ struct{}) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if !reflect.DeepEqual(keys, []string{"a", "aa", "bb", "ccc"}) {
+		t.Fatalf("want keys in order of comparator got %v", keys)
+	}
+	var vals []int
+	descFloat.Range(func(_ float64, v int) bool {
+		vals = append(vals, v)
+		return true
+	})
+	if !reflect.DeepEqual(vals, []int{3, 2, 1}) || !byLen.Delete("aa") || byLen.Delete("aa") {
+		t.Fatalf("want values in descending order of keys got %v", vals)
+	}
+}
+
 /* Test from sync.Map */
 func TestConcurrentRange(t *testing.T) {
 	const mapSize = 1 << 10
@@ -0,0 +1,153 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package skipmap
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/alimy/tryst/internal/binenc"
+)
+
+// A snapshot of the map is written in the order of the map, the keys and
+// values must be bools, numbers, strings or byte slices, or implement
+// encoding.BinaryMarshaler and encoding.BinaryUnmarshaler. Values of other
+// types, like the any values of IntMap, are encoded by the functions given to
+// WriteToWith and ReadFromWith.
+
+// WriteTo write a snapshot of the map to w, it's safe under concurrent
+// mutation but not a consistent snapshot, like Range.
+func (s *Map[K, V]) WriteTo(w io.Writer) (int64, error) {
+	kc, vc, err := codecs[K, V]()
+	if err != nil {
+		return 0, err
+	}
+	return s.writeTo(w, kc, vc)
+}
+
+// WriteToWith is like WriteTo, but values are encoded by marshal. The
+// snapshot could be read by ReadFromWith with the matched unmarshal, or by
+// ReadFrom if V implement encoding.BinaryUnmarshaler.
+func (s *Map[K, V]) WriteToWith(w io.Writer, marshal func(V) ([]byte, error)) (int64, error) {
+	kc, err := keyCodec[K]()
+	if err != nil {
+		return 0, err
+	}
+	return s.writeTo(w, kc, binenc.FuncCodec(marshal, nil))
+}
+
+func (s *Map[K, V]) writeTo(w io.Writer, kc binenc.Codec[K], vc binenc.Codec[V]) (int64, error) {
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindMap, Key: kc.Tag, Value: vc.Tag})
+	s.Range(func(key K, value V) bool {
+		kc.Encode(e, key)
+		vc.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace entries of the map with the snapshot read from r, it must
+// not be called concurrently with other methods. The skiplist is built in
+// O(n) time if the snapshot is in the order of the map, like one written by
+// a map of the same order, otherwise the entries are stored one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Map[K, V]) ReadFrom(r io.Reader) (int64, error) {
+	kc, vc, err := codecs[K, V]()
+	if err != nil {
+		return 0, err
+	}
+	return s.readFrom(r, kc, vc)
+}
+
+// ReadFromWith is like ReadFrom, but values are decoded by unmarshal, see
+// WriteToWith.
+func (s *Map[K, V]) ReadFromWith(r io.Reader, unmarshal func([]byte) (V, error)) (int64, error) {
+	kc, err := keyCodec[K]()
+	if err != nil {
+		return 0, err
+	}
+	return s.readFrom(r, kc, binenc.FuncCodec(nil, unmarshal))
+}
+
+func (s *Map[K, V]) readFrom(r io.Reader, kc binenc.Codec[K], vc binenc.Codec[V]) (int64, error) {
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindMap, Key: kc.Tag, Value: vc.Tag})
+	ns := newMap[K, V](s.order, s.less)
+	var last [maxLevel]*node[K, V] // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		key, value := kc.Decode(d), vc.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		score := ns.score(key)
+		if sorted && last[0] != ns.header && !ns.lessthan(last[0], score, key) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Store(key, value)
+			continue
+		}
+		level := randomLevel()
+		nn := newNode(key, score, value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err := d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipmap: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Map[K, V]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Map[K, V]) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+func codecs[K cmp.Ordered, V any]() (binenc.Codec[K], binenc.Codec[V], error) {
+	kc, err := keyCodec[K]()
+	if err != nil {
+		return kc, binenc.Codec[V]{}, err
+	}
+	vc, err := binenc.CodecFor[V]()
+	if err != nil {
+		return kc, vc, fmt.Errorf("skipmap: value: %w", err)
+	}
+	return kc, vc, nil
+}
+
+func keyCodec[K cmp.Ordered]() (binenc.Codec[K], error) {
+	kc, err := binenc.CodecFor[K]()
+	if err != nil {
+		return kc, fmt.Errorf("skipmap: key: %w", err)
+	}
+	return kc, nil
+}
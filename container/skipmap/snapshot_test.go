@@ -0,0 +1,165 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package skipmap
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	const n = 5000
+	m := New[string, int64]()
+	for i := range n {
+		m.Store(strconv.Itoa(i), int64(i))
+	}
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		name string
+		m    *Map[string, int64]
+	}{
+		{"asc", New[string, int64]()},
+		{"desc", NewDesc[string, int64]()},
+		{"func", NewFunc[string, int64](func(a, b string) bool {
+			return len(a) < len(b) || len(a) == len(b) && a < b
+		})},
+	} {
+		c.m.Store("stale", -1)
+		if err := c.m.UnmarshalBinary(data); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if c.m.Len() != n {
+			t.Fatalf("%s: Len() = %d, want %d", c.name, c.m.Len(), n)
+		}
+		if _, ok := c.m.Load("stale"); ok {
+			t.Fatalf("%s: want stale entry replaced", c.name)
+		}
+		var keys []string
+		c.m.Range(func(key string, value int64) bool {
+			if strconv.Itoa(int(value)) != key {
+				t.Fatalf("%s: %s = %d", c.name, key, value)
+			}
+			keys = append(keys, key)
+			return true
+		})
+		if len(keys) != n {
+			t.Fatalf("%s: got %d keys", c.name, len(keys))
+		}
+		for i := 1; i < n; i++ {
+			if !c.m.lessthan(&node[string, int64]{key: keys[i-1], score: c.m.score(keys[i-1])}, c.m.score(keys[i]), keys[i]) {
+				t.Fatalf("%s: %q is not before %q", c.name, keys[i-1], keys[i])
+			}
+		}
+		// the map is usable after restore
+		c.m.Store("x", 1)
+		if !c.m.Delete("0") || c.m.Len() != n {
+			t.Fatalf("%s: Len() = %d after Store and Delete", c.name, c.m.Len())
+		}
+		if v, ok := c.m.Load("4999"); !ok || v != 4999 {
+			t.Fatalf("%s: Load(4999) = %d, %v", c.name, v, ok)
+		}
+	}
+}
+
+func TestSnapshotStream(t *testing.T) {
+	m := New[int, []byte]()
+	for i := range 100 {
+		m.Store(i, []byte(strconv.Itoa(i)))
+	}
+	var buf bytes.Buffer
+	n, err := m.WriteTo(&buf)
+	if err != nil || n != int64(buf.Len()) {
+		t.Fatalf("WriteTo() = %d, %v", n, err)
+	}
+	size := buf.Len()
+	buf.WriteString("trailing")
+
+	m2 := New[int, []byte]()
+	if n, err = m2.ReadFrom(&buf); err != nil || n != int64(size) {
+		t.Fatalf("ReadFrom() = %d, %v, want %d", n, err, size)
+	}
+	if buf.String() != "trailing" {
+		t.Fatalf("ReadFrom read beyond the snapshot")
+	}
+	if v, ok := m2.Load(42); !ok || string(v) != "42" {
+		t.Fatalf("Load(42) = %q, %v", v, ok)
+	}
+	if k, _, _ := m2.Max(); k != 99 {
+		t.Fatalf("Max() = %d", k)
+	}
+
+	// broken snapshot keeps the map unchanged
+	data, _ := m.MarshalBinary()
+	m3 := New[int, []byte]()
+	m3.Store(1, nil)
+	if err = m3.UnmarshalBinary(data[:len(data)/2]); err == nil || m3.Len() != 1 {
+		t.Fatalf("UnmarshalBinary() = %v, Len() = %d", err, m3.Len())
+	}
+	// mismatched types
+	if err = New[int, string]().UnmarshalBinary(data); err == nil {
+		t.Fatal("want error of mismatched types")
+	}
+	if _, err = NewInt().MarshalBinary(); err == nil {
+		t.Fatal("want error of any value")
+	}
+}
+
+func TestSnapshotWith(t *testing.T) {
+	m := NewInt64()
+	for i := range 100 {
+		m.Store(int64(i), "v"+strconv.Itoa(i))
+	}
+	marshal := func(v any) ([]byte, error) {
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("not a string")
+		}
+		return []byte(s), nil
+	}
+	unmarshal := func(data []byte) (any, error) {
+		return string(data), nil
+	}
+	var buf bytes.Buffer
+	if _, err := m.WriteToWith(&buf, marshal); err != nil {
+		t.Fatal(err)
+	}
+
+	m2 := NewInt64()
+	m2.Store(-1, "stale")
+	if _, err := m2.ReadFromWith(&buf, unmarshal); err != nil {
+		t.Fatal(err)
+	}
+	if m2.Len() != 100 {
+		t.Fatalf("Len() = %d, want 100", m2.Len())
+	}
+	m2.Range(func(key int64, value any) bool {
+		if value != "v"+strconv.Itoa(int(key)) {
+			t.Fatalf("%d = %v", key, value)
+		}
+		return true
+	})
+
+	// errors of marshal and unmarshal are returned
+	m.Store(100, 100)
+	if _, err := m.WriteToWith(io.Discard, marshal); err == nil {
+		t.Fatal("want error of marshal")
+	}
+	m.Delete(100)
+	buf.Reset()
+	if _, err := m.WriteToWith(&buf, marshal); err != nil {
+		t.Fatal(err)
+	}
+	fail := func([]byte) (any, error) { return nil, errors.New("bad value") }
+	if _, err := NewInt64().ReadFromWith(&buf, fail); err == nil {
+		t.Fatal("want error of unmarshal")
+	}
+}
@@ -14,12 +14,7 @@
 
 package skipmap
 
-import (
-	_ "unsafe" // for linkname
-
-	"github.com/alimy/tryst/internal/wyhash"
-	"github.com/alimy/tryst/lang/fastrand"
-)
+import "github.com/alimy/tryst/lang/fastrand"
 
 const (
 	maxLevel            = 16
@@ -27,13 +22,6 @@ const (
 	defaultHighestLevel = 3
 )
 
-func hash(s string) uint64 {
-	return wyhash.Sum64String(s)
-}
-
-//go:linkname cmpstring runtime.cmpstring
-func cmpstring(a, b string) int
-
 func randomLevel() int {
 	level := 1
 	for fastrand.Uint32n(1/p) == 0 {
@@ -0,0 +1,153 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package skipset
+
+import (
+	"iter"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestOrderedQueries(t *testing.T) {
+	s := NewInt()
+	if _, ok := s.Min(); ok {
+		t.Fatal("want no Min of empty set")
+	}
+	if _, ok := s.Max(); ok {
+		t.Fatal("want no Max of empty set")
+	}
+	for i := 10; i <= 50; i += 10 {
+		s.Add(i)
+	}
+	for _, c := range []struct {
+		name   string
+		fn     func(int) (int, bool)
+		value  int
+		want   int
+		wantOK bool
+	}{
+		{"Floor", s.Floor, 30, 30, true},
+		{"Floor", s.Floor, 35, 30, true},
+		{"Floor", s.Floor, 5, 0, false},
+		{"Ceiling", s.Ceiling, 35, 40, true},
+		{"Ceiling", s.Ceiling, 55, 0, false},
+	} {
+		if v, ok := c.fn(c.value); v != c.want || ok != c.wantOK {
+			t.Errorf("%s(%d) want %d %t got %d %t", c.name, c.value, c.want, c.wantOK, v, ok)
+		}
+	}
+	if v, _ := s.Min(); v != 10 {
+		t.Errorf("want Min 10 got %d", v)
+	}
+	if v, _ := s.Max(); v != 50 {
+		t.Errorf("want Max 50 got %d", v)
+	}
+	if values := slices.Collect(s.From(25)); !slices.Equal(values, []int{30, 40, 50}) {
+		t.Errorf("want From(25) [30 40 50] got %v", values)
+	}
+	if values := slices.Collect(s.Between(20, 40)); !slices.Equal(values, []int{20, 30}) {
+		t.Errorf("want Between(20, 40) [20 30] got %v", values)
+	}
+	var values []int
+	s.RangeBetween(10, 60, func(v int) bool {
+		values = append(values, v)
+		return len(values) < 2
+	})
+	if !slices.Equal(values, []int{10, 20}) {
+		t.Errorf("want RangeBetween stopped after 2 values got %v", values)
+	}
+	values = values[:0]
+	s.RangeFrom(40, func(v int) bool {
+		values = append(values, v)
+		return true
+	})
+	if !slices.Equal(values, []int{40, 50}) {
+		t.Errorf("want RangeFrom(40) [40 50] got %v", values)
+	}
+	if values := slices.Collect(s.All()); len(values) != 5 {
+		t.Errorf("want All 5 values got %v", values)
+	}
+	for want := 10; want <= 50; want += 10 {
+		if v, ok := s.PopMin(); v != want || !ok {
+			t.Errorf("want PopMin %d got %d %t", want, v, ok)
+		}
+	}
+	if _, ok := s.PopMin(); ok || s.Len() != 0 {
+		t.Error("want nothing to pop from empty set")
+	}
+}
+
+func TestOrderedQueriesDesc(t *testing.T) {
+	s := NewFloat64Desc()
+	for _, v := range []float64{1.5, 2.5, 3.5} {
+		s.Add(v)
+	}
+	if v, _ := s.Floor(2); v != 2.5 {
+		t.Errorf("want Floor(2) 2.5 got %v", v)
+	}
+	if v, _ := s.Ceiling(2); v != 1.5 {
+		t.Errorf("want Ceiling(2) 1.5 got %v", v)
+	}
+	if v, _ := s.Min(); v != 3.5 {
+		t.Errorf("want Min 3.5 got %v", v)
+	}
+	if values := slices.Collect(s.Between(3, 1)); !slices.Equal(values, []float64{2.5, 1.5}) {
+		t.Errorf("want Between(3, 1) [2.5 1.5] got %v", values)
+	}
+}
+
+func TestStringSetOrder(t *testing.T) {
+	s := NewString()
+	want := []string{"", "a", "a\x00", "a\x00b", "abcdefgh", "abcdefgh0", "abcdefgh1", "b"}
+	for _, i := range []int{3, 7, 0, 5, 1, 6, 2, 4} {
+		s.Add(want[i])
+	}
+	if values := slices.Collect(s.All()); !slices.Equal(values, want) {
+		t.Fatalf("want values in lexicographic order %q got %q", want, values)
+	}
+	if values := slices.Collect(s.Between("a\x00", "abcdefgh1")); !slices.Equal(values, want[2:6]) {
+		t.Errorf("want Between %q got %q", want[2:6], values)
+	}
+	if v, _ := s.Floor("abcdefgh00"); v != "abcdefgh0" {
+		t.Errorf("want Floor(abcdefgh00) abcdefgh0 got %q", v)
+	}
+}
+
+func TestConcurrentIter(t *testing.T) {
+	const n = 10000
+	s := NewString()
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range n {
+				v := strconv.Itoa(j*4 + i)
+				s.Add(v)
+				if j%2 == 0 {
+					s.Remove(v)
+				}
+			}
+		}()
+	}
+	for range 100 {
+		checkSorted(t, s.All())
+	}
+	wg.Wait()
+	if s.Len() != 2*n {
+		t.Fatalf("want %d values got %d", 2*n, s.Len())
+	}
+	checkSorted(t, s.All())
+}
+
+func checkSorted(t *testing.T, seq iter.Seq[string]) {
+	values := slices.Collect(seq)
+	if !sort.StringsAreSorted(values) {
+		t.Fatal("want values in order but not")
+	}
+}
@@ -18,9 +18,15 @@
 package skipset
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"iter"
 	"sync"
 	"sync/atomic"
 	"unsafe"
+
+	"github.com/alimy/tryst/internal/binenc"
 )
 
 // Int64Set represents a set based on skip list in ascending order.
@@ -73,6 +79,11 @@ func (n *int64Node) equal(value int64) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *int64Node) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewInt64 return an empty int64 skip set in ascending order.
 func NewInt64() *Int64Set {
 	h := newInt64Node(0, maxLevel)
@@ -307,7 +318,235 @@ func (s *Int64Set) Range(f func(value int64) bool) {
 	}
 }
 
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Int64Set) before(n *int64Node, value int64) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Int64Set) seek(value int64) *int64Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Int64Set) findLast(value int64, strict bool) *int64Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Int64Set) nextValid(x *int64Node) *int64Node {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Int64Set) prevValid(x *int64Node) *int64Node {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Int64Set) valueOf(x *int64Node) (value int64, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Int64Set) Min() (int64, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Int64Set) Max() (int64, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Int64Set) Floor(value int64) (int64, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Int64Set) Ceiling(value int64) (int64, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Int64Set) PopMin() (value int64, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *Int64Set) RangeFrom(value int64, f func(value int64) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Int64Set) RangeBetween(lo, hi int64, f func(value int64) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Int64Set) All() iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Int64Set) From(value int64) iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Int64Set) Between(lo, hi int64) iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
 // Len return the length of this skip set.
 func (s *Int64Set) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
+
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Int64Set) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[int64]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value int64) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Int64Set) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[int64]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewInt64()
+	var last [maxLevel]*int64Node // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newInt64Node(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Int64Set) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Int64Set) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package skipset
+
+import (
+	"bytes"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	const n = 3000
+	s := NewInt64()
+	for i := range n {
+		s.Add(int64(i * 3))
+	}
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s2 := NewInt64()
+	s2.Add(-1)
+	if err = s2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	got := slices.Collect(s2.All())
+	if len(got) != n || s2.Len() != n || !slices.IsSorted(got) || s2.Contains(-1) {
+		t.Fatalf("got %d values, Len() = %d", len(got), s2.Len())
+	}
+	if v, ok := s2.Floor(100); !ok || v != 99 {
+		t.Fatalf("Floor(100) = %d, %v", v, ok)
+	}
+	// the set is usable after restore
+	if !s2.Add(1) || !s2.Remove(0) || s2.Len() != n {
+		t.Fatalf("Len() = %d after Add and Remove", s2.Len())
+	}
+
+	// restored in a different order
+	desc := NewInt64Desc()
+	if err = desc.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	got = slices.Collect(desc.All())
+	if len(got) != n || got[0] != (n-1)*3 || got[n-1] != 0 {
+		t.Fatalf("got %d values from %d to %d", len(got), got[0], got[n-1])
+	}
+
+	// mismatched types
+	if err = NewInt32().UnmarshalBinary(data); err == nil {
+		t.Fatal("want error of mismatched types")
+	}
+	if err = s2.UnmarshalBinary(data[:len(data)-1]); err == nil || s2.Len() != n {
+		t.Fatalf("UnmarshalBinary() = %v, Len() = %d", err, s2.Len())
+	}
+}
+
+func TestStringSetSnapshot(t *testing.T) {
+	s := NewString()
+	for i := range 1000 {
+		s.Add("key-" + strconv.Itoa(i))
+	}
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s2 := NewString()
+	if _, err := s2.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(slices.Collect(s.All()), slices.Collect(s2.All())) {
+		t.Fatal("values are not equal")
+	}
+	if !s2.Contains("key-500") || s2.Contains("key-1000") {
+		t.Fatal("unexpected Contains")
+	}
+	if v, ok := s2.Ceiling("key-9"); !ok || v != "key-9" {
+		t.Fatalf("Ceiling(key-9) = %q, %v", v, ok)
+	}
+}
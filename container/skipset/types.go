@@ -16,9 +16,15 @@
 package skipset
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"iter"
 	"sync"
 	"sync/atomic"
 	"unsafe"
+
+	"github.com/alimy/tryst/internal/binenc"
 )
 
 // Float32Set represents a set based on skip list in ascending order.
@@ -71,6 +77,11 @@ func (n *float32Node) equal(value float32) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *float32Node) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewFloat32 return an empty float32 skip set in ascending order.
 func NewFloat32() *Float32Set {
 	h := newFloat32Node(0, maxLevel)
@@ -305,11 +316,239 @@ func (s *Float32Set) Range(f func(value float32) bool) {
 	}
 }
 
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Float32Set) before(n *float32Node, value float32) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Float32Set) seek(value float32) *float32Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Float32Set) findLast(value float32, strict bool) *float32Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Float32Set) nextValid(x *float32Node) *float32Node {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Float32Set) prevValid(x *float32Node) *float32Node {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Float32Set) valueOf(x *float32Node) (value float32, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Float32Set) Min() (float32, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Float32Set) Max() (float32, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Float32Set) Floor(value float32) (float32, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Float32Set) Ceiling(value float32) (float32, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Float32Set) PopMin() (value float32, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *Float32Set) RangeFrom(value float32, f func(value float32) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Float32Set) RangeBetween(lo, hi float32, f func(value float32) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Float32Set) All() iter.Seq[float32] {
+	return func(yield func(float32) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Float32Set) From(value float32) iter.Seq[float32] {
+	return func(yield func(float32) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Float32Set) Between(lo, hi float32) iter.Seq[float32] {
+	return func(yield func(float32) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
 // Len return the length of this skip set.
 func (s *Float32Set) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Float32Set) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[float32]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value float32) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Float32Set) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[float32]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewFloat32()
+	var last [maxLevel]*float32Node // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newFloat32Node(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Float32Set) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Float32Set) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // Float32SetDesc represents a set based on skip list in descending order.
 type Float32SetDesc struct {
 	header       *float32NodeDesc
@@ -360,6 +599,11 @@ func (n *float32NodeDesc) equal(value float32) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *float32NodeDesc) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewFloat32Desc return an empty float32 skip set in descending order.
 func NewFloat32Desc() *Float32SetDesc {
 	h := newFloat32NodeDesc(0, maxLevel)
@@ -594,11 +838,239 @@ func (s *Float32SetDesc) Range(f func(value float32) bool) {
 	}
 }
 
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Float32SetDesc) before(n *float32NodeDesc, value float32) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Float32SetDesc) seek(value float32) *float32NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Float32SetDesc) findLast(value float32, strict bool) *float32NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Float32SetDesc) nextValid(x *float32NodeDesc) *float32NodeDesc {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Float32SetDesc) prevValid(x *float32NodeDesc) *float32NodeDesc {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Float32SetDesc) valueOf(x *float32NodeDesc) (value float32, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Float32SetDesc) Min() (float32, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Float32SetDesc) Max() (float32, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Float32SetDesc) Floor(value float32) (float32, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Float32SetDesc) Ceiling(value float32) (float32, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Float32SetDesc) PopMin() (value float32, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *Float32SetDesc) RangeFrom(value float32, f func(value float32) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Float32SetDesc) RangeBetween(lo, hi float32, f func(value float32) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Float32SetDesc) All() iter.Seq[float32] {
+	return func(yield func(float32) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Float32SetDesc) From(value float32) iter.Seq[float32] {
+	return func(yield func(float32) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Float32SetDesc) Between(lo, hi float32) iter.Seq[float32] {
+	return func(yield func(float32) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
 // Len return the length of this skip set.
 func (s *Float32SetDesc) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Float32SetDesc) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[float32]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value float32) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Float32SetDesc) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[float32]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewFloat32Desc()
+	var last [maxLevel]*float32NodeDesc // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newFloat32NodeDesc(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Float32SetDesc) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Float32SetDesc) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // Float64Set represents a set based on skip list in ascending order.
 type Float64Set struct {
 	header       *float64Node
@@ -649,6 +1121,11 @@ func (n *float64Node) equal(value float64) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *float64Node) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewFloat64 return an empty float64 skip set in ascending order.
 func NewFloat64() *Float64Set {
 	h := newFloat64Node(0, maxLevel)
@@ -883,63 +1360,296 @@ func (s *Float64Set) Range(f func(value float64) bool) {
 	}
 }
 
-// Len return the length of this skip set.
-func (s *Float64Set) Len() int {
-	return int(atomic.LoadInt64(&s.length))
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Float64Set) before(n *float64Node, value float64) bool {
+	return n.lessthan(value)
 }
 
-// Float64SetDesc represents a set based on skip list in descending order.
-type Float64SetDesc struct {
-	header       *float64NodeDesc
-	length       int64
-	highestLevel int64 // highest level for now
+// seek return the first node that not before value, or nil
+func (s *Float64Set) seek(value float64) *float64Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
 }
 
-type float64NodeDesc struct {
-	value float64
-	next  optionalArray // [level]*float64NodeDesc
-	mu    sync.Mutex
-	flags bitflag
-	level uint32
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Float64Set) findLast(value float64, strict bool) *float64Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
 }
 
-func newFloat64NodeDesc(value float64, level int) *float64NodeDesc {
-	node := &float64NodeDesc{
-		value: value,
-		level: uint32(level),
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Float64Set) nextValid(x *float64Node) *float64Node {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
 	}
-	if level > op1 {
-		node.next.extra = new([op2]unsafe.Pointer)
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Float64Set) prevValid(x *float64Node) *float64Node {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
 	}
-	return node
+	if x == s.header {
+		return nil
+	}
+	return x
 }
 
-func (n *float64NodeDesc) loadNext(i int) *float64NodeDesc {
-	return (*float64NodeDesc)(n.next.load(i))
+// valueOf return the value of x if it's not nil
+func (s *Float64Set) valueOf(x *float64Node) (value float64, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
 }
 
-func (n *float64NodeDesc) storeNext(i int, node *float64NodeDesc) {
-	n.next.store(i, unsafe.Pointer(node))
+// Min return the first value in the order of the set.
+func (s *Float64Set) Min() (float64, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
 }
 
-func (n *float64NodeDesc) atomicLoadNext(i int) *float64NodeDesc {
-	return (*float64NodeDesc)(n.next.atomicLoad(i))
+// Max return the last value in the order of the set.
+func (s *Float64Set) Max() (float64, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
 }
 
-func (n *float64NodeDesc) atomicStoreNext(i int, node *float64NodeDesc) {
-	n.next.atomicStore(i, unsafe.Pointer(node))
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Float64Set) Floor(value float64) (float64, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
 }
 
-func (n *float64NodeDesc) lessthan(value float64) bool {
-	return n.value > value
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Float64Set) Ceiling(value float64) (float64, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
 }
 
-func (n *float64NodeDesc) equal(value float64) bool {
-	return n.value == value
+// PopMin remove the first value of the set and return it.
+func (s *Float64Set) PopMin() (value float64, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
 }
 
-// NewFloat64Desc return an empty float64 skip set in descending order.
-func NewFloat64Desc() *Float64SetDesc {
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *Float64Set) RangeFrom(value float64, f func(value float64) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Float64Set) RangeBetween(lo, hi float64, f func(value float64) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Float64Set) All() iter.Seq[float64] {
+	return func(yield func(float64) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Float64Set) From(value float64) iter.Seq[float64] {
+	return func(yield func(float64) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Float64Set) Between(lo, hi float64) iter.Seq[float64] {
+	return func(yield func(float64) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Len return the length of this skip set.
+func (s *Float64Set) Len() int {
+	return int(atomic.LoadInt64(&s.length))
+}
+
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Float64Set) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[float64]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value float64) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Float64Set) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[float64]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewFloat64()
+	var last [maxLevel]*float64Node // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newFloat64Node(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Float64Set) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Float64Set) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+// Float64SetDesc represents a set based on skip list in descending order.
+type Float64SetDesc struct {
+	header       *float64NodeDesc
+	length       int64
+	highestLevel int64 // highest level for now
+}
+
+type float64NodeDesc struct {
+	value float64
+	next  optionalArray // [level]*float64NodeDesc
+	mu    sync.Mutex
+	flags bitflag
+	level uint32
+}
+
+func newFloat64NodeDesc(value float64, level int) *float64NodeDesc {
+	node := &float64NodeDesc{
+		value: value,
+		level: uint32(level),
+	}
+	if level > op1 {
+		node.next.extra = new([op2]unsafe.Pointer)
+	}
+	return node
+}
+
+func (n *float64NodeDesc) loadNext(i int) *float64NodeDesc {
+	return (*float64NodeDesc)(n.next.load(i))
+}
+
+func (n *float64NodeDesc) storeNext(i int, node *float64NodeDesc) {
+	n.next.store(i, unsafe.Pointer(node))
+}
+
+func (n *float64NodeDesc) atomicLoadNext(i int) *float64NodeDesc {
+	return (*float64NodeDesc)(n.next.atomicLoad(i))
+}
+
+func (n *float64NodeDesc) atomicStoreNext(i int, node *float64NodeDesc) {
+	n.next.atomicStore(i, unsafe.Pointer(node))
+}
+
+func (n *float64NodeDesc) lessthan(value float64) bool {
+	return n.value > value
+}
+
+func (n *float64NodeDesc) equal(value float64) bool {
+	return n.value == value
+}
+
+// valid return true if n is fully linked and not marked
+func (n *float64NodeDesc) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
+// NewFloat64Desc return an empty float64 skip set in descending order.
+func NewFloat64Desc() *Float64SetDesc {
 	h := newFloat64NodeDesc(0, maxLevel)
 	h.flags.SetTrue(fullyLinked)
 	return &Float64SetDesc{
@@ -1172,11 +1882,239 @@ func (s *Float64SetDesc) Range(f func(value float64) bool) {
 	}
 }
 
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Float64SetDesc) before(n *float64NodeDesc, value float64) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Float64SetDesc) seek(value float64) *float64NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Float64SetDesc) findLast(value float64, strict bool) *float64NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Float64SetDesc) nextValid(x *float64NodeDesc) *float64NodeDesc {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Float64SetDesc) prevValid(x *float64NodeDesc) *float64NodeDesc {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Float64SetDesc) valueOf(x *float64NodeDesc) (value float64, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Float64SetDesc) Min() (float64, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Float64SetDesc) Max() (float64, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Float64SetDesc) Floor(value float64) (float64, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Float64SetDesc) Ceiling(value float64) (float64, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Float64SetDesc) PopMin() (value float64, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *Float64SetDesc) RangeFrom(value float64, f func(value float64) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Float64SetDesc) RangeBetween(lo, hi float64, f func(value float64) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Float64SetDesc) All() iter.Seq[float64] {
+	return func(yield func(float64) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Float64SetDesc) From(value float64) iter.Seq[float64] {
+	return func(yield func(float64) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Float64SetDesc) Between(lo, hi float64) iter.Seq[float64] {
+	return func(yield func(float64) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
 // Len return the length of this skip set.
 func (s *Float64SetDesc) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Float64SetDesc) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[float64]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value float64) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Float64SetDesc) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[float64]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewFloat64Desc()
+	var last [maxLevel]*float64NodeDesc // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newFloat64NodeDesc(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Float64SetDesc) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Float64SetDesc) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // Int64SetDesc represents a set based on skip list in descending order.
 type Int64SetDesc struct {
 	header       *int64NodeDesc
@@ -1227,6 +2165,11 @@ func (n *int64NodeDesc) equal(value int64) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *int64NodeDesc) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewInt64Desc return an empty int64 skip set in descending order.
 func NewInt64Desc() *Int64SetDesc {
 	h := newInt64NodeDesc(0, maxLevel)
@@ -1441,29 +2384,257 @@ func (s *Int64SetDesc) Remove(value int64) bool {
 			atomic.AddInt64(&s.length, -1)
 			return true
 		}
-		return false
+		return false
+	}
+}
+
+// Range calls f sequentially for each value present in the skip set.
+// If f returns false, range stops the iteration.
+func (s *Int64SetDesc) Range(f func(value int64) bool) {
+	x := s.header.atomicLoadNext(0)
+	for x != nil {
+		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
+			x = x.atomicLoadNext(0)
+			continue
+		}
+		if !f(x.value) {
+			break
+		}
+		x = x.atomicLoadNext(0)
+	}
+}
+
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Int64SetDesc) before(n *int64NodeDesc, value int64) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Int64SetDesc) seek(value int64) *int64NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Int64SetDesc) findLast(value int64, strict bool) *int64NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Int64SetDesc) nextValid(x *int64NodeDesc) *int64NodeDesc {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Int64SetDesc) prevValid(x *int64NodeDesc) *int64NodeDesc {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Int64SetDesc) valueOf(x *int64NodeDesc) (value int64, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Int64SetDesc) Min() (int64, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Int64SetDesc) Max() (int64, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Int64SetDesc) Floor(value int64) (int64, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Int64SetDesc) Ceiling(value int64) (int64, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Int64SetDesc) PopMin() (value int64, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *Int64SetDesc) RangeFrom(value int64, f func(value int64) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Int64SetDesc) RangeBetween(lo, hi int64, f func(value int64) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Int64SetDesc) All() iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Int64SetDesc) From(value int64) iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Int64SetDesc) Between(lo, hi int64) iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Len return the length of this skip set.
+func (s *Int64SetDesc) Len() int {
+	return int(atomic.LoadInt64(&s.length))
+}
+
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Int64SetDesc) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[int64]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value int64) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Int64SetDesc) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[int64]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewInt64Desc()
+	var last [maxLevel]*int64NodeDesc // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newInt64NodeDesc(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
 	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
 }
 
-// Range calls f sequentially for each value present in the skip set.
-// If f returns false, range stops the iteration.
-func (s *Int64SetDesc) Range(f func(value int64) bool) {
-	x := s.header.atomicLoadNext(0)
-	for x != nil {
-		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
-			x = x.atomicLoadNext(0)
-			continue
-		}
-		if !f(x.value) {
-			break
-		}
-		x = x.atomicLoadNext(0)
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Int64SetDesc) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
 	}
+	return buf.Bytes(), nil
 }
 
-// Len return the length of this skip set.
-func (s *Int64SetDesc) Len() int {
-	return int(atomic.LoadInt64(&s.length))
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Int64SetDesc) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
 }
 
 // Int32Set represents a set based on skip list in ascending order.
@@ -1516,6 +2687,11 @@ func (n *int32Node) equal(value int32) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *int32Node) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewInt32 return an empty int32 skip set in ascending order.
 func NewInt32() *Int32Set {
 	h := newInt32Node(0, maxLevel)
@@ -1750,11 +2926,239 @@ func (s *Int32Set) Range(f func(value int32) bool) {
 	}
 }
 
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Int32Set) before(n *int32Node, value int32) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Int32Set) seek(value int32) *int32Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Int32Set) findLast(value int32, strict bool) *int32Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Int32Set) nextValid(x *int32Node) *int32Node {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Int32Set) prevValid(x *int32Node) *int32Node {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Int32Set) valueOf(x *int32Node) (value int32, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Int32Set) Min() (int32, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Int32Set) Max() (int32, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Int32Set) Floor(value int32) (int32, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Int32Set) Ceiling(value int32) (int32, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Int32Set) PopMin() (value int32, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *Int32Set) RangeFrom(value int32, f func(value int32) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Int32Set) RangeBetween(lo, hi int32, f func(value int32) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Int32Set) All() iter.Seq[int32] {
+	return func(yield func(int32) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Int32Set) From(value int32) iter.Seq[int32] {
+	return func(yield func(int32) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Int32Set) Between(lo, hi int32) iter.Seq[int32] {
+	return func(yield func(int32) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
 // Len return the length of this skip set.
 func (s *Int32Set) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Int32Set) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[int32]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value int32) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Int32Set) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[int32]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewInt32()
+	var last [maxLevel]*int32Node // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newInt32Node(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Int32Set) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Int32Set) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // Int32SetDesc represents a set based on skip list in descending order.
 type Int32SetDesc struct {
 	header       *int32NodeDesc
@@ -1805,6 +3209,11 @@ func (n *int32NodeDesc) equal(value int32) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *int32NodeDesc) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewInt32Desc return an empty int32 skip set in descending order.
 func NewInt32Desc() *Int32SetDesc {
 	h := newInt32NodeDesc(0, maxLevel)
@@ -2039,11 +3448,239 @@ func (s *Int32SetDesc) Range(f func(value int32) bool) {
 	}
 }
 
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Int32SetDesc) before(n *int32NodeDesc, value int32) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Int32SetDesc) seek(value int32) *int32NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Int32SetDesc) findLast(value int32, strict bool) *int32NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Int32SetDesc) nextValid(x *int32NodeDesc) *int32NodeDesc {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Int32SetDesc) prevValid(x *int32NodeDesc) *int32NodeDesc {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Int32SetDesc) valueOf(x *int32NodeDesc) (value int32, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Int32SetDesc) Min() (int32, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Int32SetDesc) Max() (int32, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Int32SetDesc) Floor(value int32) (int32, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Int32SetDesc) Ceiling(value int32) (int32, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Int32SetDesc) PopMin() (value int32, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *Int32SetDesc) RangeFrom(value int32, f func(value int32) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Int32SetDesc) RangeBetween(lo, hi int32, f func(value int32) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Int32SetDesc) All() iter.Seq[int32] {
+	return func(yield func(int32) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Int32SetDesc) From(value int32) iter.Seq[int32] {
+	return func(yield func(int32) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Int32SetDesc) Between(lo, hi int32) iter.Seq[int32] {
+	return func(yield func(int32) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
 // Len return the length of this skip set.
 func (s *Int32SetDesc) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Int32SetDesc) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[int32]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value int32) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Int32SetDesc) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[int32]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewInt32Desc()
+	var last [maxLevel]*int32NodeDesc // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newInt32NodeDesc(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Int32SetDesc) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Int32SetDesc) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // Int16Set represents a set based on skip list in ascending order.
 type Int16Set struct {
 	header       *int16Node
@@ -2094,6 +3731,11 @@ func (n *int16Node) equal(value int16) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *int16Node) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewInt16 return an empty int16 skip set in ascending order.
 func NewInt16() *Int16Set {
 	h := newInt16Node(0, maxLevel)
@@ -2308,23 +3950,170 @@ func (s *Int16Set) Remove(value int16) bool {
 			atomic.AddInt64(&s.length, -1)
 			return true
 		}
-		return false
+		return false
+	}
+}
+
+// Range calls f sequentially for each value present in the skip set.
+// If f returns false, range stops the iteration.
+func (s *Int16Set) Range(f func(value int16) bool) {
+	x := s.header.atomicLoadNext(0)
+	for x != nil {
+		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
+			x = x.atomicLoadNext(0)
+			continue
+		}
+		if !f(x.value) {
+			break
+		}
+		x = x.atomicLoadNext(0)
+	}
+}
+
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Int16Set) before(n *int16Node, value int16) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Int16Set) seek(value int16) *int16Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Int16Set) findLast(value int16, strict bool) *int16Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Int16Set) nextValid(x *int16Node) *int16Node {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Int16Set) prevValid(x *int16Node) *int16Node {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Int16Set) valueOf(x *int16Node) (value int16, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Int16Set) Min() (int16, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Int16Set) Max() (int16, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
 	}
+	return s.valueOf(s.prevValid(x))
 }
 
-// Range calls f sequentially for each value present in the skip set.
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Int16Set) Floor(value int16) (int16, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Int16Set) Ceiling(value int16) (int16, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Int16Set) PopMin() (value int16, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
 // If f returns false, range stops the iteration.
-func (s *Int16Set) Range(f func(value int16) bool) {
-	x := s.header.atomicLoadNext(0)
-	for x != nil {
-		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
-			x = x.atomicLoadNext(0)
-			continue
+func (s *Int16Set) RangeFrom(value int16, f func(value int16) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Int16Set) RangeBetween(lo, hi int16, f func(value int16) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Int16Set) All() iter.Seq[int16] {
+	return func(yield func(int16) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Int16Set) From(value int16) iter.Seq[int16] {
+	return func(yield func(int16) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
 		}
-		if !f(x.value) {
-			break
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Int16Set) Between(lo, hi int16) iter.Seq[int16] {
+	return func(yield func(int16) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
 		}
-		x = x.atomicLoadNext(0)
 	}
 }
 
@@ -2333,6 +4122,87 @@ func (s *Int16Set) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Int16Set) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[int16]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value int16) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Int16Set) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[int16]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewInt16()
+	var last [maxLevel]*int16Node // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newInt16Node(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Int16Set) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Int16Set) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // Int16SetDesc represents a set based on skip list in descending order.
 type Int16SetDesc struct {
 	header       *int16NodeDesc
@@ -2383,6 +4253,11 @@ func (n *int16NodeDesc) equal(value int16) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *int16NodeDesc) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewInt16Desc return an empty int16 skip set in descending order.
 func NewInt16Desc() *Int16SetDesc {
 	h := newInt16NodeDesc(0, maxLevel)
@@ -2617,11 +4492,239 @@ func (s *Int16SetDesc) Range(f func(value int16) bool) {
 	}
 }
 
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Int16SetDesc) before(n *int16NodeDesc, value int16) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Int16SetDesc) seek(value int16) *int16NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Int16SetDesc) findLast(value int16, strict bool) *int16NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Int16SetDesc) nextValid(x *int16NodeDesc) *int16NodeDesc {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Int16SetDesc) prevValid(x *int16NodeDesc) *int16NodeDesc {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Int16SetDesc) valueOf(x *int16NodeDesc) (value int16, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Int16SetDesc) Min() (int16, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Int16SetDesc) Max() (int16, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Int16SetDesc) Floor(value int16) (int16, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Int16SetDesc) Ceiling(value int16) (int16, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Int16SetDesc) PopMin() (value int16, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *Int16SetDesc) RangeFrom(value int16, f func(value int16) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Int16SetDesc) RangeBetween(lo, hi int16, f func(value int16) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Int16SetDesc) All() iter.Seq[int16] {
+	return func(yield func(int16) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Int16SetDesc) From(value int16) iter.Seq[int16] {
+	return func(yield func(int16) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Int16SetDesc) Between(lo, hi int16) iter.Seq[int16] {
+	return func(yield func(int16) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
 // Len return the length of this skip set.
 func (s *Int16SetDesc) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Int16SetDesc) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[int16]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value int16) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Int16SetDesc) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[int16]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewInt16Desc()
+	var last [maxLevel]*int16NodeDesc // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newInt16NodeDesc(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Int16SetDesc) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Int16SetDesc) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // IntSet represents a set based on skip list in ascending order.
 type IntSet struct {
 	header       *intNode
@@ -2672,6 +4775,11 @@ func (n *intNode) equal(value int) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *intNode) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewInt return an empty int skip set in ascending order.
 func NewInt() *IntSet {
 	h := newIntNode(0, maxLevel)
@@ -2906,9 +5014,237 @@ func (s *IntSet) Range(f func(value int) bool) {
 	}
 }
 
-// Len return the length of this skip set.
-func (s *IntSet) Len() int {
-	return int(atomic.LoadInt64(&s.length))
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *IntSet) before(n *intNode, value int) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *IntSet) seek(value int) *intNode {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *IntSet) findLast(value int, strict bool) *intNode {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *IntSet) nextValid(x *intNode) *intNode {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *IntSet) prevValid(x *intNode) *intNode {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *IntSet) valueOf(x *intNode) (value int, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *IntSet) Min() (int, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *IntSet) Max() (int, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *IntSet) Floor(value int) (int, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *IntSet) Ceiling(value int) (int, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *IntSet) PopMin() (value int, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *IntSet) RangeFrom(value int, f func(value int) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *IntSet) RangeBetween(lo, hi int, f func(value int) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *IntSet) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *IntSet) From(value int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *IntSet) Between(lo, hi int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Len return the length of this skip set.
+func (s *IntSet) Len() int {
+	return int(atomic.LoadInt64(&s.length))
+}
+
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *IntSet) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[int]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value int) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *IntSet) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[int]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewInt()
+	var last [maxLevel]*intNode // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newIntNode(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *IntSet) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *IntSet) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
 }
 
 // IntSetDesc represents a set based on skip list in descending order.
@@ -2961,6 +5297,11 @@ func (n *intNodeDesc) equal(value int) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *intNodeDesc) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewIntDesc return an empty int skip set in descending order.
 func NewIntDesc() *IntSetDesc {
 	h := newIntNodeDesc(0, maxLevel)
@@ -3195,11 +5536,239 @@ func (s *IntSetDesc) Range(f func(value int) bool) {
 	}
 }
 
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *IntSetDesc) before(n *intNodeDesc, value int) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *IntSetDesc) seek(value int) *intNodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *IntSetDesc) findLast(value int, strict bool) *intNodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *IntSetDesc) nextValid(x *intNodeDesc) *intNodeDesc {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *IntSetDesc) prevValid(x *intNodeDesc) *intNodeDesc {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *IntSetDesc) valueOf(x *intNodeDesc) (value int, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *IntSetDesc) Min() (int, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *IntSetDesc) Max() (int, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *IntSetDesc) Floor(value int) (int, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *IntSetDesc) Ceiling(value int) (int, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *IntSetDesc) PopMin() (value int, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *IntSetDesc) RangeFrom(value int, f func(value int) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *IntSetDesc) RangeBetween(lo, hi int, f func(value int) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *IntSetDesc) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *IntSetDesc) From(value int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *IntSetDesc) Between(lo, hi int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
 // Len return the length of this skip set.
 func (s *IntSetDesc) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *IntSetDesc) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[int]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value int) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *IntSetDesc) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[int]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewIntDesc()
+	var last [maxLevel]*intNodeDesc // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newIntNodeDesc(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *IntSetDesc) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *IntSetDesc) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // Uint64Set represents a set based on skip list in ascending order.
 type Uint64Set struct {
 	header       *uint64Node
@@ -3250,6 +5819,11 @@ func (n *uint64Node) equal(value uint64) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *uint64Node) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewUint64 return an empty uint64 skip set in ascending order.
 func NewUint64() *Uint64Set {
 	h := newUuint64Node(0, maxLevel)
@@ -3484,11 +6058,239 @@ func (s *Uint64Set) Range(f func(value uint64) bool) {
 	}
 }
 
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Uint64Set) before(n *uint64Node, value uint64) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Uint64Set) seek(value uint64) *uint64Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Uint64Set) findLast(value uint64, strict bool) *uint64Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Uint64Set) nextValid(x *uint64Node) *uint64Node {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Uint64Set) prevValid(x *uint64Node) *uint64Node {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Uint64Set) valueOf(x *uint64Node) (value uint64, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Uint64Set) Min() (uint64, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Uint64Set) Max() (uint64, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Uint64Set) Floor(value uint64) (uint64, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Uint64Set) Ceiling(value uint64) (uint64, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Uint64Set) PopMin() (value uint64, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *Uint64Set) RangeFrom(value uint64, f func(value uint64) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Uint64Set) RangeBetween(lo, hi uint64, f func(value uint64) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Uint64Set) All() iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Uint64Set) From(value uint64) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Uint64Set) Between(lo, hi uint64) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
 // Len return the length of this skip set.
 func (s *Uint64Set) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Uint64Set) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[uint64]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value uint64) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Uint64Set) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[uint64]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewUint64()
+	var last [maxLevel]*uint64Node // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newUuint64Node(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Uint64Set) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Uint64Set) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // Uint64SetDesc represents a set based on skip list in descending order.
 type Uint64SetDesc struct {
 	header       *uint64NodeDesc
@@ -3539,6 +6341,11 @@ func (n *uint64NodeDesc) equal(value uint64) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *uint64NodeDesc) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewUint64Desc return an empty uint64 skip set in descending order.
 func NewUint64Desc() *Uint64SetDesc {
 	h := newUuint64NodeDescDesc(0, maxLevel)
@@ -3753,23 +6560,170 @@ func (s *Uint64SetDesc) Remove(value uint64) bool {
 			atomic.AddInt64(&s.length, -1)
 			return true
 		}
-		return false
+		return false
+	}
+}
+
+// Range calls f sequentially for each value present in the skip set.
+// If f returns false, range stops the iteration.
+func (s *Uint64SetDesc) Range(f func(value uint64) bool) {
+	x := s.header.atomicLoadNext(0)
+	for x != nil {
+		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
+			x = x.atomicLoadNext(0)
+			continue
+		}
+		if !f(x.value) {
+			break
+		}
+		x = x.atomicLoadNext(0)
+	}
+}
+
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Uint64SetDesc) before(n *uint64NodeDesc, value uint64) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Uint64SetDesc) seek(value uint64) *uint64NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Uint64SetDesc) findLast(value uint64, strict bool) *uint64NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Uint64SetDesc) nextValid(x *uint64NodeDesc) *uint64NodeDesc {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Uint64SetDesc) prevValid(x *uint64NodeDesc) *uint64NodeDesc {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Uint64SetDesc) valueOf(x *uint64NodeDesc) (value uint64, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Uint64SetDesc) Min() (uint64, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Uint64SetDesc) Max() (uint64, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Uint64SetDesc) Floor(value uint64) (uint64, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Uint64SetDesc) Ceiling(value uint64) (uint64, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Uint64SetDesc) PopMin() (value uint64, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
 	}
 }
 
-// Range calls f sequentially for each value present in the skip set.
+// RangeFrom calls f sequentially for each value that not before value.
 // If f returns false, range stops the iteration.
-func (s *Uint64SetDesc) Range(f func(value uint64) bool) {
-	x := s.header.atomicLoadNext(0)
-	for x != nil {
-		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
-			x = x.atomicLoadNext(0)
-			continue
+func (s *Uint64SetDesc) RangeFrom(value uint64, f func(value uint64) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Uint64SetDesc) RangeBetween(lo, hi uint64, f func(value uint64) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Uint64SetDesc) All() iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Uint64SetDesc) From(value uint64) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
 		}
-		if !f(x.value) {
-			break
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Uint64SetDesc) Between(lo, hi uint64) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
 		}
-		x = x.atomicLoadNext(0)
 	}
 }
 
@@ -3778,6 +6732,87 @@ func (s *Uint64SetDesc) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Uint64SetDesc) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[uint64]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value uint64) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Uint64SetDesc) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[uint64]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewUint64Desc()
+	var last [maxLevel]*uint64NodeDesc // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newUuint64NodeDescDesc(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Uint64SetDesc) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Uint64SetDesc) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // Uint32Set represents a set based on skip list in ascending order.
 type Uint32Set struct {
 	header       *uint32Node
@@ -3828,6 +6863,11 @@ func (n *uint32Node) equal(value uint32) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *uint32Node) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewUint32 return an empty uint32 skip set in ascending order.
 func NewUint32() *Uint32Set {
 	h := newUint32Node(0, maxLevel)
@@ -4062,11 +7102,239 @@ func (s *Uint32Set) Range(f func(value uint32) bool) {
 	}
 }
 
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Uint32Set) before(n *uint32Node, value uint32) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Uint32Set) seek(value uint32) *uint32Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Uint32Set) findLast(value uint32, strict bool) *uint32Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Uint32Set) nextValid(x *uint32Node) *uint32Node {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Uint32Set) prevValid(x *uint32Node) *uint32Node {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Uint32Set) valueOf(x *uint32Node) (value uint32, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Uint32Set) Min() (uint32, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Uint32Set) Max() (uint32, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Uint32Set) Floor(value uint32) (uint32, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Uint32Set) Ceiling(value uint32) (uint32, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Uint32Set) PopMin() (value uint32, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *Uint32Set) RangeFrom(value uint32, f func(value uint32) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Uint32Set) RangeBetween(lo, hi uint32, f func(value uint32) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Uint32Set) All() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Uint32Set) From(value uint32) iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Uint32Set) Between(lo, hi uint32) iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
 // Len return the length of this skip set.
 func (s *Uint32Set) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Uint32Set) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[uint32]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value uint32) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Uint32Set) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[uint32]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewUint32()
+	var last [maxLevel]*uint32Node // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newUint32Node(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Uint32Set) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Uint32Set) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // Uint32SetDesc represents a set based on skip list in descending order.
 type Uint32SetDesc struct {
 	header       *uint32NodeDesc
@@ -4117,6 +7385,11 @@ func (n *uint32NodeDesc) equal(value uint32) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *uint32NodeDesc) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewUint32Desc return an empty uint32 skip set in descending order.
 func NewUint32Desc() *Uint32SetDesc {
 	h := newUint32NodeDesc(0, maxLevel)
@@ -4331,29 +7604,257 @@ func (s *Uint32SetDesc) Remove(value uint32) bool {
 			atomic.AddInt64(&s.length, -1)
 			return true
 		}
-		return false
+		return false
+	}
+}
+
+// Range calls f sequentially for each value present in the skip set.
+// If f returns false, range stops the iteration.
+func (s *Uint32SetDesc) Range(f func(value uint32) bool) {
+	x := s.header.atomicLoadNext(0)
+	for x != nil {
+		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
+			x = x.atomicLoadNext(0)
+			continue
+		}
+		if !f(x.value) {
+			break
+		}
+		x = x.atomicLoadNext(0)
+	}
+}
+
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Uint32SetDesc) before(n *uint32NodeDesc, value uint32) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Uint32SetDesc) seek(value uint32) *uint32NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Uint32SetDesc) findLast(value uint32, strict bool) *uint32NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Uint32SetDesc) nextValid(x *uint32NodeDesc) *uint32NodeDesc {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Uint32SetDesc) prevValid(x *uint32NodeDesc) *uint32NodeDesc {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Uint32SetDesc) valueOf(x *uint32NodeDesc) (value uint32, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Uint32SetDesc) Min() (uint32, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Uint32SetDesc) Max() (uint32, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Uint32SetDesc) Floor(value uint32) (uint32, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Uint32SetDesc) Ceiling(value uint32) (uint32, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Uint32SetDesc) PopMin() (value uint32, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *Uint32SetDesc) RangeFrom(value uint32, f func(value uint32) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Uint32SetDesc) RangeBetween(lo, hi uint32, f func(value uint32) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Uint32SetDesc) All() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Uint32SetDesc) From(value uint32) iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Uint32SetDesc) Between(lo, hi uint32) iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Len return the length of this skip set.
+func (s *Uint32SetDesc) Len() int {
+	return int(atomic.LoadInt64(&s.length))
+}
+
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Uint32SetDesc) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[uint32]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value uint32) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Uint32SetDesc) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[uint32]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewUint32Desc()
+	var last [maxLevel]*uint32NodeDesc // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newUint32NodeDesc(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
 	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
 }
 
-// Range calls f sequentially for each value present in the skip set.
-// If f returns false, range stops the iteration.
-func (s *Uint32SetDesc) Range(f func(value uint32) bool) {
-	x := s.header.atomicLoadNext(0)
-	for x != nil {
-		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
-			x = x.atomicLoadNext(0)
-			continue
-		}
-		if !f(x.value) {
-			break
-		}
-		x = x.atomicLoadNext(0)
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Uint32SetDesc) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
 	}
+	return buf.Bytes(), nil
 }
 
-// Len return the length of this skip set.
-func (s *Uint32SetDesc) Len() int {
-	return int(atomic.LoadInt64(&s.length))
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Uint32SetDesc) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
 }
 
 // Uint16Set represents a set based on skip list in ascending order.
@@ -4406,6 +7907,11 @@ func (n *uint16Node) equal(value uint16) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *uint16Node) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewUint16 return an empty uint16 skip set in ascending order.
 func NewUint16() *Uint16Set {
 	h := newUint16Node(0, maxLevel)
@@ -4640,11 +8146,239 @@ func (s *Uint16Set) Range(f func(value uint16) bool) {
 	}
 }
 
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Uint16Set) before(n *uint16Node, value uint16) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Uint16Set) seek(value uint16) *uint16Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Uint16Set) findLast(value uint16, strict bool) *uint16Node {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Uint16Set) nextValid(x *uint16Node) *uint16Node {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Uint16Set) prevValid(x *uint16Node) *uint16Node {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Uint16Set) valueOf(x *uint16Node) (value uint16, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Uint16Set) Min() (uint16, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Uint16Set) Max() (uint16, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Uint16Set) Floor(value uint16) (uint16, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Uint16Set) Ceiling(value uint16) (uint16, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Uint16Set) PopMin() (value uint16, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *Uint16Set) RangeFrom(value uint16, f func(value uint16) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Uint16Set) RangeBetween(lo, hi uint16, f func(value uint16) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Uint16Set) All() iter.Seq[uint16] {
+	return func(yield func(uint16) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Uint16Set) From(value uint16) iter.Seq[uint16] {
+	return func(yield func(uint16) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Uint16Set) Between(lo, hi uint16) iter.Seq[uint16] {
+	return func(yield func(uint16) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
 // Len return the length of this skip set.
 func (s *Uint16Set) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Uint16Set) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[uint16]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value uint16) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Uint16Set) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[uint16]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewUint16()
+	var last [maxLevel]*uint16Node // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newUint16Node(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Uint16Set) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Uint16Set) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // Uint16SetDesc represents a set based on skip list in descending order.
 type Uint16SetDesc struct {
 	header       *uint16NodeDesc
@@ -4695,6 +8429,11 @@ func (n *uint16NodeDesc) equal(value uint16) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *uint16NodeDesc) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewUint16Desc return an empty uint16 skip set in descending order.
 func NewUint16Desc() *Uint16SetDesc {
 	h := newUint16NodeDesc(0, maxLevel)
@@ -4929,11 +8668,239 @@ func (s *Uint16SetDesc) Range(f func(value uint16) bool) {
 	}
 }
 
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *Uint16SetDesc) before(n *uint16NodeDesc, value uint16) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *Uint16SetDesc) seek(value uint16) *uint16NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *Uint16SetDesc) findLast(value uint16, strict bool) *uint16NodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *Uint16SetDesc) nextValid(x *uint16NodeDesc) *uint16NodeDesc {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *Uint16SetDesc) prevValid(x *uint16NodeDesc) *uint16NodeDesc {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *Uint16SetDesc) valueOf(x *uint16NodeDesc) (value uint16, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *Uint16SetDesc) Min() (uint16, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *Uint16SetDesc) Max() (uint16, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *Uint16SetDesc) Floor(value uint16) (uint16, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *Uint16SetDesc) Ceiling(value uint16) (uint16, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *Uint16SetDesc) PopMin() (value uint16, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *Uint16SetDesc) RangeFrom(value uint16, f func(value uint16) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *Uint16SetDesc) RangeBetween(lo, hi uint16, f func(value uint16) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *Uint16SetDesc) All() iter.Seq[uint16] {
+	return func(yield func(uint16) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *Uint16SetDesc) From(value uint16) iter.Seq[uint16] {
+	return func(yield func(uint16) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *Uint16SetDesc) Between(lo, hi uint16) iter.Seq[uint16] {
+	return func(yield func(uint16) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
 // Len return the length of this skip set.
 func (s *Uint16SetDesc) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *Uint16SetDesc) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[uint16]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value uint16) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *Uint16SetDesc) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[uint16]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewUint16Desc()
+	var last [maxLevel]*uint16NodeDesc // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newUint16NodeDesc(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *Uint16SetDesc) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *Uint16SetDesc) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // UintSet represents a set based on skip list in ascending order.
 type UintSet struct {
 	header       *uintNode
@@ -4984,6 +8951,11 @@ func (n *uintNode) equal(value uint) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *uintNode) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewUint return an empty uint skip set in ascending order.
 func NewUint() *UintSet {
 	h := newUintNode(0, maxLevel)
@@ -5198,23 +9170,170 @@ func (s *UintSet) Remove(value uint) bool {
 			atomic.AddInt64(&s.length, -1)
 			return true
 		}
-		return false
+		return false
+	}
+}
+
+// Range calls f sequentially for each value present in the skip set.
+// If f returns false, range stops the iteration.
+func (s *UintSet) Range(f func(value uint) bool) {
+	x := s.header.atomicLoadNext(0)
+	for x != nil {
+		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
+			x = x.atomicLoadNext(0)
+			continue
+		}
+		if !f(x.value) {
+			break
+		}
+		x = x.atomicLoadNext(0)
+	}
+}
+
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *UintSet) before(n *uintNode, value uint) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *UintSet) seek(value uint) *uintNode {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *UintSet) findLast(value uint, strict bool) *uintNode {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *UintSet) nextValid(x *uintNode) *uintNode {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *UintSet) prevValid(x *uintNode) *uintNode {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *UintSet) valueOf(x *uintNode) (value uint, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *UintSet) Min() (uint, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *UintSet) Max() (uint, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
 	}
+	return s.valueOf(s.prevValid(x))
 }
 
-// Range calls f sequentially for each value present in the skip set.
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *UintSet) Floor(value uint) (uint, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *UintSet) Ceiling(value uint) (uint, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *UintSet) PopMin() (value uint, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
 // If f returns false, range stops the iteration.
-func (s *UintSet) Range(f func(value uint) bool) {
-	x := s.header.atomicLoadNext(0)
-	for x != nil {
-		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
-			x = x.atomicLoadNext(0)
-			continue
+func (s *UintSet) RangeFrom(value uint, f func(value uint) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *UintSet) RangeBetween(lo, hi uint, f func(value uint) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *UintSet) All() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *UintSet) From(value uint) iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
 		}
-		if !f(x.value) {
-			break
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *UintSet) Between(lo, hi uint) iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
 		}
-		x = x.atomicLoadNext(0)
 	}
 }
 
@@ -5223,6 +9342,87 @@ func (s *UintSet) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *UintSet) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[uint]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value uint) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *UintSet) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[uint]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewUint()
+	var last [maxLevel]*uintNode // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newUintNode(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *UintSet) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *UintSet) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // UintSetDesc represents a set based on skip list in descending order.
 type UintSetDesc struct {
 	header       *uintNodeDesc
@@ -5273,6 +9473,11 @@ func (n *uintNodeDesc) equal(value uint) bool {
 	return n.value == value
 }
 
+// valid return true if n is fully linked and not marked
+func (n *uintNodeDesc) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
 // NewUintDesc return an empty uint skip set in descending order.
 func NewUintDesc() *UintSetDesc {
 	h := newUintNodeDesc(0, maxLevel)
@@ -5507,12 +9712,240 @@ func (s *UintSetDesc) Range(f func(value uint) bool) {
 	}
 }
 
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *UintSetDesc) before(n *uintNodeDesc, value uint) bool {
+	return n.lessthan(value)
+}
+
+// seek return the first node that not before value, or nil
+func (s *UintSetDesc) seek(value uint) *uintNodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.lessthan(value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *UintSetDesc) findLast(value uint, strict bool) *uintNodeDesc {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.lessthan(value) || !strict && succ.equal(value)) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *UintSetDesc) nextValid(x *uintNodeDesc) *uintNodeDesc {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *UintSetDesc) prevValid(x *uintNodeDesc) *uintNodeDesc {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *UintSetDesc) valueOf(x *uintNodeDesc) (value uint, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *UintSetDesc) Min() (uint, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *UintSetDesc) Max() (uint, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *UintSetDesc) Floor(value uint) (uint, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *UintSetDesc) Ceiling(value uint) (uint, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *UintSetDesc) PopMin() (value uint, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *UintSetDesc) RangeFrom(value uint, f func(value uint) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *UintSetDesc) RangeBetween(lo, hi uint, f func(value uint) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *UintSetDesc) All() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *UintSetDesc) From(value uint) iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *UintSetDesc) Between(lo, hi uint) iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
 // Len return the length of this skip set.
 func (s *UintSetDesc) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
-// StringSet represents a set based on skip list.
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *UintSetDesc) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[uint]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value uint) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *UintSetDesc) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[uint]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewUintDesc()
+	var last [maxLevel]*uintNodeDesc // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newUintNodeDesc(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *UintSetDesc) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *UintSetDesc) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+// StringSet represents a set based on skip list in ascending order.
 type StringSet struct {
 	header       *stringNode
 	length       int64
@@ -5530,7 +9963,7 @@ type stringNode struct {
 
 func newStringNode(value string, level int) *stringNode {
 	node := &stringNode{
-		score: hash(value),
+		score: prefix(value),
 		value: value,
 		level: uint32(level),
 	}
@@ -5556,7 +9989,12 @@ func (n *stringNode) atomicStoreNext(i int, node *stringNode) {
 	n.next.atomicStore(i, unsafe.Pointer(node))
 }
 
-// NewString return an empty string skip set.
+// valid return true if n is fully linked and not marked
+func (n *stringNode) valid() bool {
+	return n.flags.MGet(fullyLinked|marked, fullyLinked)
+}
+
+// NewString return an empty string skip set in ascending order.
 func NewString() *StringSet {
 	h := newStringNode("", maxLevel)
 	h.flags.SetTrue(fullyLinked)
@@ -5569,7 +10007,7 @@ func NewString() *StringSet {
 // findNodeRemove takes a value and two maximal-height arrays then searches exactly as in a sequential skip-list.
 // The returned preds and succs always satisfy preds[i] > value >= succs[i].
 func (s *StringSet) findNodeRemove(value string, preds *[maxLevel]*stringNode, succs *[maxLevel]*stringNode) int {
-	score := hash(value)
+	score := prefix(value)
 	// lFound represents the index of the first layer at which it found a node.
 	lFound, x := -1, s.header
 	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
@@ -5592,7 +10030,7 @@ func (s *StringSet) findNodeRemove(value string, preds *[maxLevel]*stringNode, s
 // findNodeAdd takes a value and two maximal-height arrays then searches exactly as in a sequential skip-set.
 // The returned preds and succs always satisfy preds[i] > value >= succs[i].
 func (s *StringSet) findNodeAdd(value string, preds *[maxLevel]*stringNode, succs *[maxLevel]*stringNode) int {
-	score := hash(value)
+	score := prefix(value)
 	x := s.header
 	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
 		succ := x.atomicLoadNext(i)
@@ -5697,7 +10135,7 @@ func (s *StringSet) randomlevel() int {
 
 // Contains check if the value is in the skip set.
 func (s *StringSet) Contains(value string) bool {
-	score := hash(value)
+	score := prefix(value)
 	x := s.header
 	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
 		nex := x.atomicLoadNext(i)
@@ -5793,11 +10231,242 @@ func (s *StringSet) Range(f func(value string) bool) {
 	}
 }
 
+// The ordered queries below follow the order of the set, "before" means less
+// than in an increasing set and greater than in a decreasing set. Like Range,
+// they are safe under concurrent mutation but not a consistent snapshot.
+
+// before return true if n is before value
+func (s *StringSet) before(n *stringNode, value string) bool {
+	score := prefix(value)
+	return n.cmp(score, value) < 0
+}
+
+// seek return the first node that not before value, or nil
+func (s *StringSet) seek(value string) *stringNode {
+	score := prefix(value)
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && succ.cmp(score, value) < 0 {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x.atomicLoadNext(0)
+}
+
+// findLast return the last node that not after value, or before value if
+// strict. The header is returned if there is no such node.
+func (s *StringSet) findLast(value string, strict bool) *stringNode {
+	score := prefix(value)
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && (succ.cmp(score, value) < 0 || !strict && succ.cmp(score, value) == 0) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
+
+// nextValid return x if it's valid, or the first valid node after x, or nil
+func (s *StringSet) nextValid(x *stringNode) *stringNode {
+	for x != nil && !x.valid() {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// prevValid return x if it's valid, or the last valid node before x, or nil
+func (s *StringSet) prevValid(x *stringNode) *stringNode {
+	for x != s.header && !x.valid() {
+		// x is being inserted or removed, it's treated as absent
+		x = s.findLast(x.value, true)
+	}
+	if x == s.header {
+		return nil
+	}
+	return x
+}
+
+// valueOf return the value of x if it's not nil
+func (s *StringSet) valueOf(x *stringNode) (value string, ok bool) {
+	if x == nil {
+		return value, false
+	}
+	return x.value, true
+}
+
+// Min return the first value in the order of the set.
+func (s *StringSet) Min() (string, bool) {
+	return s.valueOf(s.nextValid(s.header.atomicLoadNext(0)))
+}
+
+// Max return the last value in the order of the set.
+func (s *StringSet) Max() (string, bool) {
+	x := s.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		for succ := x.atomicLoadNext(i); succ != nil; succ = x.atomicLoadNext(i) {
+			x = succ
+		}
+	}
+	return s.valueOf(s.prevValid(x))
+}
+
+// Floor return the last value that not after value, that is the largest
+// value less than or equal to value in an increasing set.
+func (s *StringSet) Floor(value string) (string, bool) {
+	return s.valueOf(s.prevValid(s.findLast(value, false)))
+}
+
+// Ceiling return the first value that not before value, that is the smallest
+// value greater than or equal to value in an increasing set.
+func (s *StringSet) Ceiling(value string) (string, bool) {
+	return s.valueOf(s.nextValid(s.seek(value)))
+}
+
+// PopMin remove the first value of the set and return it.
+func (s *StringSet) PopMin() (value string, ok bool) {
+	for {
+		x := s.nextValid(s.header.atomicLoadNext(0))
+		if x == nil {
+			return value, false
+		}
+		// retry if it's removed by others
+		if s.Remove(x.value) {
+			return x.value, true
+		}
+	}
+}
+
+// RangeFrom calls f sequentially for each value that not before value.
+// If f returns false, range stops the iteration.
+func (s *StringSet) RangeFrom(value string, f func(value string) bool) {
+	s.From(value)(f)
+}
+
+// RangeBetween calls f sequentially for each value in [lo, hi) in the order
+// of the set. If f returns false, range stops the iteration.
+func (s *StringSet) RangeBetween(lo, hi string, f func(value string) bool) {
+	s.Between(lo, hi)(f)
+}
+
+// All return an iterator over values of the set.
+func (s *StringSet) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		s.Range(yield)
+	}
+}
+
+// From return an iterator over values that not before value.
+func (s *StringSet) From(value string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for x := s.seek(value); x != nil; x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Between return an iterator over values in [lo, hi) in the order of the set.
+func (s *StringSet) Between(lo, hi string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for x := s.seek(lo); x != nil && s.before(x, hi); x = x.atomicLoadNext(0) {
+			if x.valid() && !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
 // Len return the length of this skip set.
 func (s *StringSet) Len() int {
 	return int(atomic.LoadInt64(&s.length))
 }
 
+// WriteTo write a snapshot of the set to w in the order of the set, it's
+// safe under concurrent mutation but not a consistent snapshot, like Range.
+func (s *StringSet) WriteTo(w io.Writer) (int64, error) {
+	codec, err := binenc.CodecFor[string]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	s.Range(func(value string) bool {
+		codec.Encode(e, value)
+		e.Next()
+		return true
+	})
+	return e.Close()
+}
+
+// ReadFrom replace values of the set with the snapshot read from r, it must
+// not be called concurrently with other methods. The skip list is built in
+// O(n) time if the snapshot is in the order of the set, like one written by
+// a set of the same type, otherwise the values are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (s *StringSet) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := binenc.CodecFor[string]()
+	if err != nil {
+		return 0, fmt.Errorf("skipset: %w", err)
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSet, Key: codec.Tag})
+	ns := NewString()
+	var last [maxLevel]*stringNode // the last node of every level
+	for i := range last {
+		last[i] = ns.header
+	}
+	sorted := true
+	for d.Next() {
+		value := codec.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted && last[0] != ns.header && !ns.before(last[0], value) {
+			sorted = false
+		}
+		if !sorted {
+			ns.Add(value)
+			continue
+		}
+		level := randomLevel()
+		nn := newStringNode(value, level)
+		for i := 0; i < level; i++ {
+			last[i].storeNext(i, nn)
+			last[i] = nn
+		}
+		nn.flags.SetTrue(fullyLinked)
+		ns.length++
+		ns.highestLevel = max(ns.highestLevel, int64(level))
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("skipset: %w", err)
+	}
+	s.header = ns.header
+	atomic.StoreInt64(&s.highestLevel, atomic.LoadInt64(&ns.highestLevel))
+	atomic.StoreInt64(&s.length, atomic.LoadInt64(&ns.length))
+	return d.N(), nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, see WriteTo.
+func (s *StringSet) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, see ReadFrom.
+func (s *StringSet) UnmarshalBinary(data []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
 // Return 1 if n is bigger, 0 if equal, else -1.
 func (n *stringNode) cmp(score uint64, value string) int {
 	if n.score > score {
@@ -108,6 +108,11 @@ func replace(data string, upper string, desc bool) string {
 	data = strings.Replace(data, "int64Node", lower+"Node"+descstr, -1)
 	data = strings.Replace(data, "value int64", "value "+lower, -1)
 	data = strings.Replace(data, "int64 skip set", lower+" skip set", -1) // comment
+	data = strings.Replace(data, "lo, hi int64", "lo, hi "+lower, -1)
+	data = strings.Replace(data, "(int64, bool)", "("+lower+", bool)", -1)
+	data = strings.Replace(data, "iter.Seq[int64]", "iter.Seq["+lower+"]", -1)
+	data = strings.Replace(data, "func(int64) bool", "func("+lower+") bool", -1)
+	data = strings.Replace(data, "binenc.CodecFor[int64]", "binenc.CodecFor["+lower+"]", -1)
 
 	if desc {
 		// Special cases for DESC.
@@ -123,7 +128,7 @@ func replaceString(data string) string {
 		lower = "string"
 	)
 
-	// Add `score uint64` field.
+	// Add `score uint64` field, it's the prefix of value in the same order.
 	data = strings.Replace(data,
 		`type int64Node struct {
 	value int64`,
@@ -134,7 +139,7 @@ func replaceString(data string) string {
 	data = strings.Replace(data,
 		`&int64Node{`,
 		`&int64Node{
-		score: hash(value),`, -1)
+		score: prefix(value),`, -1)
 
 	// Refactor comparison.
 	data = data + "\n"
@@ -165,10 +170,13 @@ func (n *stringNode) cmp(score uint64, value string) int {
 	return n.value == value
 }`, "", -1)
 
-	// Add "score := hash(value)"
-	data = addLineAfter(data, "func (s *Int64Set) findNodeRemove", "score := hash(value)")
-	data = addLineAfter(data, "func (s *Int64Set) findNodeAdd", "score := hash(value)")
-	data = addLineAfter(data, "func (s *Int64Set) Contains", "score := hash(value)")
+	// Add "score := prefix(value)"
+	data = addLineAfter(data, "func (s *Int64Set) findNodeRemove", "score := prefix(value)")
+	data = addLineAfter(data, "func (s *Int64Set) findNodeAdd", "score := prefix(value)")
+	data = addLineAfter(data, "func (s *Int64Set) Contains", "score := prefix(value)")
+	data = addLineAfter(data, "func (s *Int64Set) before(", "score := prefix(value)")
+	data = addLineAfter(data, "func (s *Int64Set) seek(", "score := prefix(value)")
+	data = addLineAfter(data, "func (s *Int64Set) findLast(", "score := prefix(value)")
 
 	// Update new value "newInt64Node(0"
 	data = strings.Replace(data,
@@ -181,7 +189,11 @@ func (n *stringNode) cmp(score uint64, value string) int {
 	data = strings.Replace(data, "int64Node", lower+"Node", -1)
 	data = strings.Replace(data, "value int64", "value "+lower, -1)
 	data = strings.Replace(data, "int64 skip set", lower+" skip set", -1) // comment
-	data = strings.Replace(data, " in ascending order", "", -1)           // comment
+	data = strings.Replace(data, "lo, hi int64", "lo, hi "+lower, -1)
+	data = strings.Replace(data, "(int64, bool)", "("+lower+", bool)", -1)
+	data = strings.Replace(data, "iter.Seq[int64]", "iter.Seq["+lower+"]", -1)
+	data = strings.Replace(data, "func(int64) bool", "func("+lower+") bool", -1)
+	data = strings.Replace(data, "binenc.CodecFor[int64]", "binenc.CodecFor["+lower+"]", -1)
 
 	return data
 }
@@ -15,9 +15,9 @@
 package skipset
 
 import (
-	_ "unsafe" // for linkname
+	"encoding/binary"
+	"unsafe"
 
-	"github.com/alimy/tryst/internal/wyhash"
 	"github.com/alimy/tryst/lang/fastrand"
 )
 
@@ -27,8 +27,15 @@ const (
 	defaultHighestLevel = 3
 )
 
-func hash(s string) uint64 {
-	return wyhash.Sum64String(s)
+// prefix return the big-endian first 8 bytes of s, prefixes are in the same
+// order of strings so most comparisons of strings are of integers.
+func prefix(s string) uint64 {
+	if len(s) >= 8 {
+		return binary.BigEndian.Uint64(unsafe.Slice(unsafe.StringData(s), 8))
+	}
+	var b [8]byte
+	copy(b[:], s)
+	return binary.BigEndian.Uint64(b[:])
 }
 
 //go:linkname cmpstring runtime.cmpstring
@@ -0,0 +1,41 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package zset
+
+// Float64Node represents an element of Float64Set.
+type Float64Node = Node[string, float64]
+
+// Float64Set is a sorted set implementation with string value and float64 score.
+type Float64Set = SortedSet[string, float64]
+
+// NewFloat64 returns an empty string sorted set with float64 score.
+// strings are sorted in ascending order.
+func NewFloat64() *Float64Set {
+	return New[string, float64]()
+}
+
+// UnionFloat64 returns the union of given sorted sets, the resulting score of
+// a value is the sum of its scores in the sorted sets where it exists. An
+// empty sorted set is returned if zs is empty.
+//
+// UnionFloat64 is the replacement of ZUNION command of redis.
+func UnionFloat64(zs ...*Float64Set) *Float64Set {
+	if len(zs) == 0 {
+		return NewFloat64()
+	}
+	return Union(zs...)
+}
+
+// InterFloat64 returns the intersection of given sorted sets, the resulting
+// score of a value is the sum of its scores in the sorted sets where it exists.
+// An empty sorted set is returned if zs is empty.
+//
+// InterFloat64 is the replacement of ZINTER command of redis.
+func InterFloat64(zs ...*Float64Set) *Float64Set {
+	if len(zs) == 0 {
+		return NewFloat64()
+	}
+	return Inter(zs...)
+}
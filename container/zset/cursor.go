@@ -0,0 +1,109 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package zset
+
+import (
+	"cmp"
+)
+
+// Cursor is the position of paging a sorted set, it's the last element of
+// the previous page. The zero Cursor starts from the first element.
+//
+// Unlike rank or offset, a cursor is stable when the sorted set changes
+// between pages: the next page starts right after the element the cursor
+// points to even if it's removed, so elements are neither skipped nor
+// repeated unless their scores are updated. The cursor is plain data and
+// could be sent to clients to page a leaderboard.
+type Cursor[M comparable, S cmp.Ordered] struct {
+	Score S
+	Value M
+	// Started reports whether Score and Value are set.
+	Started bool
+	// Done reports whether the paging has reached the end.
+	Done bool
+}
+
+// Scan returns at most count elements after the cursor, ordered by score
+// from lowest to highest, and the cursor of the next page. All the rest
+// elements are returned if count is not positive.
+//
+// Locating the cursor is O(log(N)), so paging a large sorted set does not
+// rank from the head for every page like Range does.
+func (z *SortedSet[M, S]) Scan(c Cursor[M, S], count int) ([]Node[M, S], Cursor[M, S]) {
+	if c.Done {
+		return nil, c
+	}
+
+	z.mu.RLock()
+	defer z.mu.RUnlock()
+
+	x := z.list.header
+	if c.Started {
+		for i := z.list.highestLevel - 1; i >= 0; i-- {
+			next := x.loadNext(i)
+			for next != nil && z.list.lessEqual(next, c.Score, c.Value) {
+				x = next
+				next = x.loadNext(i)
+			}
+		}
+	}
+	x = x.loadNext(0)
+	res, next := collect(x, count, func(x *listNode[M, S]) *listNode[M, S] {
+		return x.loadNext(0)
+	})
+	return res, nextCursor(c, res, next)
+}
+
+// RevScan is like Scan, but elements are ordered by score from highest to
+// lowest.
+func (z *SortedSet[M, S]) RevScan(c Cursor[M, S], count int) ([]Node[M, S], Cursor[M, S]) {
+	if c.Done {
+		return nil, c
+	}
+
+	z.mu.RLock()
+	defer z.mu.RUnlock()
+
+	x := z.list.tail
+	if c.Started {
+		x = z.list.header
+		for i := z.list.highestLevel - 1; i >= 0; i-- {
+			next := x.loadNext(i)
+			for next != nil && z.list.lessThan(next, c.Score, c.Value) {
+				x = next
+				next = x.loadNext(i)
+			}
+		}
+		if x == z.list.header {
+			x = nil
+		}
+	}
+	res, next := collect(x, count, func(x *listNode[M, S]) *listNode[M, S] {
+		return x.prev
+	})
+	return res, nextCursor(c, res, next)
+}
+
+// collect returns at most count elements from x, and the node after them.
+func collect[M comparable, S cmp.Ordered](x *listNode[M, S], count int, step func(*listNode[M, S]) *listNode[M, S]) ([]Node[M, S], *listNode[M, S]) {
+	var res []Node[M, S]
+	for x != nil && (count <= 0 || len(res) < count) {
+		res = append(res, Node[M, S]{
+			Score: x.score,
+			Value: x.value,
+		})
+		x = step(x)
+	}
+	return res, x
+}
+
+func nextCursor[M comparable, S cmp.Ordered](c Cursor[M, S], res []Node[M, S], next *listNode[M, S]) Cursor[M, S] {
+	if len(res) > 0 {
+		last := res[len(res)-1]
+		c = Cursor[M, S]{Score: last.Score, Value: last.Value, Started: true}
+	}
+	c.Done = next == nil
+	return c
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package zset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScan(t *testing.T) {
+	z := New[int64, int64]()
+	for i := int64(0); i < 10; i++ {
+		z.Add(i/2, i)
+	}
+
+	var (
+		c     Cursor[int64, int64]
+		ns    []Node[int64, int64]
+		pages [][]int64
+	)
+	for !c.Done {
+		ns, c = z.Scan(c, 4)
+		var page []int64
+		for _, n := range ns {
+			page = append(page, n.Value)
+		}
+		pages = append(pages, page)
+	}
+	assert.Equal(t, [][]int64{{0, 1, 2, 3}, {4, 5, 6, 7}, {8, 9}}, pages)
+	ns, c = z.Scan(c, 4)
+	assert.Empty(t, ns)
+	assert.True(t, c.Done)
+
+	// the whole rest
+	ns, c = z.Scan(Cursor[int64, int64]{}, 0)
+	assert.Len(t, ns, 10)
+	assert.True(t, c.Done)
+
+	// empty set
+	ns, c = New[int64, int64]().Scan(Cursor[int64, int64]{}, 4)
+	assert.Empty(t, ns)
+	assert.True(t, c.Done)
+}
+
+func TestScanStable(t *testing.T) {
+	z := New[int64, int64]()
+	for i := int64(0); i < 10; i++ {
+		z.Add(i, i)
+	}
+	ns, c := z.Scan(Cursor[int64, int64]{}, 3)
+	assert.Equal(t, int64(2), ns[2].Value)
+	assert.Equal(t, Cursor[int64, int64]{Score: 2, Value: 2, Started: true}, c)
+
+	// changes before or at the cursor don't shift the next page
+	z.Remove(0)
+	z.Remove(2)
+	z.Add(-1, 100)
+	ns, c = z.Scan(c, 3)
+	assert.Equal(t, []Node[int64, int64]{{3, 3}, {4, 4}, {5, 5}}, ns)
+
+	// elements added after the cursor are returned
+	z.Add(5, 50)
+	ns, _ = z.Scan(c, 2)
+	assert.Equal(t, []Node[int64, int64]{{50, 5}, {6, 6}}, ns)
+}
+
+func TestRevScan(t *testing.T) {
+	z := NewFloat64()
+	for _, v := range []string{"a", "b", "c", "d", "e"} {
+		z.Add(1, v)
+	}
+	z.Add(2, "x")
+
+	var (
+		c     Cursor[string, float64]
+		ns    []Float64Node
+		pages [][]string
+	)
+	for !c.Done {
+		ns, c = z.RevScan(c, 2)
+		var page []string
+		for _, n := range ns {
+			page = append(page, n.Value)
+		}
+		pages = append(pages, page)
+	}
+	assert.Equal(t, [][]string{{"x", "e"}, {"d", "c"}, {"b", "a"}}, pages)
+
+	z.Remove("c")
+	ns, c = z.RevScan(Cursor[string, float64]{Score: 1, Value: "c", Started: true}, 0)
+	assert.Equal(t, []Float64Node{{"b", 1}, {"a", 1}}, ns)
+	assert.True(t, c.Done)
+}
@@ -19,3 +19,82 @@ type RangeOpt struct {
 	ExcludeMin bool
 	ExcludeMax bool
 }
+
+// LexRangeOpt describes the whether the min/max is exclusive or unbounded in
+// lex range, UnboundedMin and UnboundedMax are the - and + of ZRANGEBYLEX.
+type LexRangeOpt struct {
+	ExcludeMin   bool
+	ExcludeMax   bool
+	UnboundedMin bool
+	UnboundedMax bool
+}
+
+// LimitOpt is the LIMIT option of ZRANGEBYSCORE and ZRANGEBYLEX, Offset
+// elements of the range are skipped and at most Count elements are returned.
+// Like redis, nothing is returned if Offset is negative, and all the elements
+// after Offset are returned if Count is negative.
+type LimitOpt struct {
+	Offset int
+	Count  int
+}
+
+// noLimit returns all the elements of a range.
+var noLimit = LimitOpt{Count: -1}
+
+// empty returns whether nothing could be returned by the limit.
+func (opt LimitOpt) empty() bool {
+	return opt.Offset < 0 || opt.Count == 0
+}
+
+// full returns whether n elements have reached the limit.
+func (opt LimitOpt) full(n int) bool {
+	return opt.Count >= 0 && n >= opt.Count
+}
+
+// Number is a constraint that permits any integer or float type, which
+// scores could be weighted and summed.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Aggregate describes how the scores of a value in multiple sorted sets
+// are aggregated, it's the AGGREGATE option of ZUNION and ZINTER.
+type Aggregate uint8
+
+const (
+	// AggregateSum sums the scores, it's the default.
+	AggregateSum Aggregate = iota
+	// AggregateMin takes the minimum of the scores.
+	AggregateMin
+	// AggregateMax takes the maximum of the scores.
+	AggregateMax
+)
+
+// AggregateOpt describes the weights and the aggregate mode of union and
+// intersection.
+type AggregateOpt[S Number] struct {
+	// Weights multiplication factors of the scores in every sorted set,
+	// the missing weights are 1.
+	Weights   []S
+	Aggregate Aggregate
+}
+
+func (opt AggregateOpt[S]) weight(i int, score S) S {
+	if i < len(opt.Weights) {
+		return score * opt.Weights[i]
+	}
+	return score
+}
+
+func (opt AggregateOpt[S]) aggregate(a, b S) S {
+	switch opt.Aggregate {
+	case AggregateMin:
+		return min(a, b)
+	case AggregateMax:
+		return max(a, b)
+	default:
+		return a + b
+	}
+}
@@ -14,7 +14,7 @@
 package zset
 
 import (
-	"math"
+	"cmp"
 	"unsafe"
 
 	"github.com/alimy/tryst/lang/fastrand"
@@ -29,17 +29,17 @@ const (
 	probability = 0.25 // same to ZSKIPLIST_P, 1/4
 )
 
-// float64ListNode is node of float64List.
-type float64ListNode struct {
-	score float64 // key for sorting, which is allowed to be repeated
-	value string
-	prev  *float64ListNode // back pointer that only available at level 1
-	level int              // the length of optionalArray
+// listNode is node of list.
+type listNode[M comparable, S cmp.Ordered] struct {
+	score S // key for sorting, which is allowed to be repeated
+	value M
+	prev  *listNode[M, S] // back pointer that only available at level 1
+	level int             // the length of optionalArray
 	oparr optionalArray
 }
 
-func newFloat64ListNode(score float64, value string, level int) *float64ListNode {
-	node := &float64ListNode{
+func newListNode[M comparable, S cmp.Ordered](score S, value M, level int) *listNode[M, S] {
+	node := &listNode[M, S]{
 		score: score,
 		value: value,
 		level: level,
@@ -48,54 +48,36 @@ func newFloat64ListNode(score float64, value string, level int) *float64ListNode
 	return node
 }
 
-func (n *float64ListNode) loadNext(i int) *float64ListNode {
-	return (*float64ListNode)(n.oparr.loadNext(i))
+func (n *listNode[M, S]) loadNext(i int) *listNode[M, S] {
+	return (*listNode[M, S])(n.oparr.loadNext(i))
 }
 
-func (n *float64ListNode) storeNext(i int, node *float64ListNode) {
+func (n *listNode[M, S]) storeNext(i int, node *listNode[M, S]) {
 	n.oparr.storeNext(i, unsafe.Pointer(node))
 }
 
-func (n *float64ListNode) loadSpan(i int) int {
+func (n *listNode[M, S]) loadSpan(i int) int {
 	return n.oparr.loadSpan(i)
 }
 
-func (n *float64ListNode) storeSpan(i int, span int) {
+func (n *listNode[M, S]) storeSpan(i int, span int) {
 	n.oparr.storeSpan(i, span)
 }
 
-func (n *float64ListNode) loadNextAndSpan(i int) (*float64ListNode, int) {
+func (n *listNode[M, S]) loadNextAndSpan(i int) (*listNode[M, S], int) {
 	return n.loadNext(i), n.loadSpan(i)
 }
 
-func (n *float64ListNode) storeNextAndSpan(i int, next *float64ListNode, span int) {
+func (n *listNode[M, S]) storeNextAndSpan(i int, next *listNode[M, S], span int) {
 	n.storeNext(i, next)
 	n.storeSpan(i, span)
 }
 
-func (n *float64ListNode) lessThan(score float64, value string) bool {
-	if n.score < score {
-		return true
-	} else if n.score == score {
-		return n.value < value
-	}
-	return false
-}
-
-func (n *float64ListNode) lessEqual(score float64, value string) bool {
-	if n.score < score {
-		return true
-	} else if n.score == score {
-		return n.value <= value
-	}
-	return false
-}
-
-func (n *float64ListNode) equal(score float64, value string) bool {
+func (n *listNode[M, S]) equal(score S, value M) bool {
 	return n.value == value && n.score == score
 }
 
-// float64List is a specialized skip list implementation for sorted set.
+// list is a specialized skip list implementation for sorted set.
 //
 // It is almost implement the original
 // algorithm described by William Pugh in " Lists: A Probabilistic
@@ -105,26 +87,53 @@ func (n *float64ListNode) equal(score float64, value string) bool {
 // c) there is a back pointer, so it's a doubly linked list with the back
 // pointers being only at "level 1". This allows to traverse the list
 // from tail to head, useful for RevRange.
-type float64List struct {
-	header       *float64ListNode
-	tail         *float64ListNode
+type list[M comparable, S cmp.Ordered] struct {
+	header       *listNode[M, S]
+	tail         *listNode[M, S]
 	length       int
 	highestLevel int // highest level for now
+	compare      func(a, b M) int
 }
 
-func newFloat64List() *float64List {
-	l := &float64List{
-		header:       newFloat64ListNode(-math.MaxFloat64, "__HEADER", maxLevel), // FIXME:
+func newList[M comparable, S cmp.Ordered](compare func(a, b M) int) *list[M, S] {
+	var (
+		score S
+		value M
+	)
+	l := &list[M, S]{
+		header:       newListNode(score, value, maxLevel), // never compared
 		highestLevel: 1,
+		compare:      compare,
 	}
 	return l
 }
 
+// lessThan return true if n is ordered before the element by both score
+// and value.
+func (l *list[M, S]) lessThan(n *listNode[M, S], score S, value M) bool {
+	if n.score < score {
+		return true
+	} else if n.score == score {
+		return l.compare(n.value, value) < 0
+	}
+	return false
+}
+
+// lessEqual return true if n is the element or ordered before it.
+func (l *list[M, S]) lessEqual(n *listNode[M, S], score S, value M) bool {
+	if n.score < score {
+		return true
+	} else if n.score == score {
+		return l.compare(n.value, value) <= 0
+	}
+	return false
+}
+
 // Insert inserts a new node in the skiplist. Assumes the element does not already
 // exist (up to the caller to enforce that).
-func (l *float64List) Insert(score float64, value string) *float64ListNode {
+func (l *list[M, S]) Insert(score S, value M) *listNode[M, S] {
 	var (
-		update [maxLevel]*float64ListNode
+		update [maxLevel]*listNode[M, S]
 		rank   [maxLevel + 1]int // +1 for eliminating a boundary judgment
 	)
 
@@ -132,7 +141,7 @@ func (l *float64List) Insert(score float64, value string) *float64ListNode {
 	for i := l.highestLevel - 1; i >= 0; i-- {
 		rank[i] = rank[i+1] // also fine when i == maxLevel - 1
 		next := x.loadNext(i)
-		for next != nil && next.lessThan(score, value) {
+		for next != nil && l.lessThan(next, score, value) {
 			rank[i] += x.loadSpan(i)
 			x = next
 			next = x.loadNext(i)
@@ -154,7 +163,7 @@ func (l *float64List) Insert(score float64, value string) *float64ListNode {
 		}
 		l.highestLevel = level
 	}
-	x = newFloat64ListNode(score, value, level)
+	x = newListNode(score, value, level)
 	for i := 0; i < level; i++ {
 		// update --> x --> update.next
 		x.storeNext(i, update[i].loadNext(i))
@@ -184,7 +193,7 @@ func (l *float64List) Insert(score float64, value string) *float64ListNode {
 }
 
 // randomLevel returns a level between [1, maxLevel] for insertion.
-func (l *float64List) randomLevel() int {
+func (l *list[M, S]) randomLevel() int {
 	level := 1
 	for fastrand.Uint32n(1/probability) == 0 {
 		level++
@@ -200,20 +209,19 @@ func (l *float64List) randomLevel() int {
 //
 // NOTE: the rank is 1-based due to the span of l->header to the
 // first element.
-func (l *float64List) Rank(score float64, value string) int {
+func (l *list[M, S]) Rank(score S, value M) int {
 	rank := 0
 	x := l.header
 	for i := l.highestLevel - 1; i >= 0; i-- {
 		next := x.loadNext(i)
-		for next != nil && next.lessEqual(score, value) {
+		for next != nil && l.lessEqual(next, score, value) {
 			rank += x.loadSpan(i)
 			x = next
 			next = x.loadNext(i)
 		}
 
-		// x might be equal to l->header, so test if obj is non-nil
-		// TODO: Why not use if x != l.header?
-		if x.equal(score, value) {
+		// x might be equal to l->header, which holds zero score and value
+		if x != l.header && x.equal(score, value) {
 			return rank
 		}
 	}
@@ -222,7 +230,7 @@ func (l *float64List) Rank(score float64, value string) int {
 
 // deleteNode is a internal function for deleting node x in O(1) time by giving a
 // update position matrix.
-func (l *float64List) deleteNode(x *float64ListNode, update *[maxLevel]*float64ListNode) {
+func (l *list[M, S]) deleteNode(x *listNode[M, S], update *[maxLevel]*listNode[M, S]) {
 	for i := 0; i < l.highestLevel; i++ {
 		if update[i].loadNext(i) == x {
 			// Remove x, updaet[i].span = updaet[i].span + x.span - 1 (x removed).
@@ -249,13 +257,13 @@ func (l *float64List) deleteNode(x *float64ListNode, update *[maxLevel]*float64L
 
 // Delete deletes an element with matching score/element from the skiplist.
 // The deleted node is returned if the node was found, otherwise 0 is returned.
-func (l *float64List) Delete(score float64, value string) *float64ListNode {
-	var update [maxLevel]*float64ListNode
+func (l *list[M, S]) Delete(score S, value M) *listNode[M, S] {
+	var update [maxLevel]*listNode[M, S]
 
 	x := l.header
 	for i := l.highestLevel - 1; i >= 0; i-- {
 		next := x.loadNext(i)
-		for next != nil && next.lessThan(score, value) {
+		for next != nil && l.lessThan(next, score, value) {
 			x = next
 			next = x.loadNext(i)
 		}
@@ -281,13 +289,13 @@ func (l *float64List) Delete(score float64, value string) *float64ListNode {
 // element, which is more costly.
 //
 // The function returns the updated element skiplist node pointer.
-func (l *float64List) UpdateScore(oldScore float64, value string, newScore float64) *float64ListNode {
-	var update [maxLevel]*float64ListNode
+func (l *list[M, S]) UpdateScore(oldScore S, value M, newScore S) *listNode[M, S] {
+	var update [maxLevel]*listNode[M, S]
 
 	x := l.header
 	for i := l.highestLevel - 1; i >= 0; i-- {
 		next := x.loadNext(i)
-		for next != nil && next.lessThan(oldScore, value) {
+		for next != nil && l.lessThan(next, oldScore, value) {
 			x = next
 			next = x.loadNext(i)
 		}
@@ -315,7 +323,7 @@ func (l *float64List) UpdateScore(oldScore float64, value string, newScore float
 	return newNode
 }
 
-func greaterThanMin(value float64, min float64, ex bool) bool {
+func greaterThanMin[S cmp.Ordered](value S, min S, ex bool) bool {
 	if ex {
 		return value > min
 	} else {
@@ -323,7 +331,7 @@ func greaterThanMin(value float64, min float64, ex bool) bool {
 	}
 }
 
-func lessThanMax(value float64, max float64, ex bool) bool {
+func lessThanMax[S cmp.Ordered](value S, max S, ex bool) bool {
 	if ex {
 		return value < max
 	} else {
@@ -337,10 +345,10 @@ func lessThanMax(value float64, max float64, ex bool) bool {
 // When inclusive a score >= min && score <= max is deleted.
 //
 // This function returns count of deleted elements.
-func (l *float64List) DeleteRangeByScore(min, max float64, opt RangeOpt, dict map[string]float64) []Float64Node {
+func (l *list[M, S]) DeleteRangeByScore(min, max S, opt RangeOpt, dict map[M]S) []Node[M, S] {
 	var (
-		update  [maxLevel]*float64ListNode
-		removed []Float64Node
+		update  [maxLevel]*listNode[M, S]
+		removed []Node[M, S]
 	)
 
 	x := l.header
@@ -361,7 +369,7 @@ func (l *float64List) DeleteRangeByScore(min, max float64, opt RangeOpt, dict ma
 		next := x.loadNext(0)
 		l.deleteNode(x, &update)
 		delete(dict, x.value)
-		removed = append(removed, Float64Node{
+		removed = append(removed, Node[M, S]{
 			Value: x.value,
 			Score: x.score,
 		})
@@ -375,10 +383,10 @@ func (l *float64List) DeleteRangeByScore(min, max float64, opt RangeOpt, dict ma
 // Start and end are inclusive.
 //
 // NOTE: start and end need to be 1-based
-func (l *float64List) DeleteRangeByRank(start, end int, dict map[string]float64) []Float64Node {
+func (l *list[M, S]) DeleteRangeByRank(start, end int, dict map[M]S) []Node[M, S] {
 	var (
-		update    [maxLevel]*float64ListNode
-		removed   []Float64Node
+		update    [maxLevel]*listNode[M, S]
+		removed   []Node[M, S]
 		traversed int
 	)
 
@@ -400,7 +408,7 @@ func (l *float64List) DeleteRangeByRank(start, end int, dict map[string]float64)
 		next := x.loadNext(0)
 		l.deleteNode(x, &update)
 		delete(dict, x.value)
-		removed = append(removed, Float64Node{
+		removed = append(removed, Node[M, S]{
 			Value: x.value,
 			Score: x.score,
 		})
@@ -411,7 +419,7 @@ func (l *float64List) DeleteRangeByRank(start, end int, dict map[string]float64)
 }
 
 // GetNodeByRank finds an element by its rank. The rank argument needs to be 1-based.
-func (l *float64List) GetNodeByRank(rank int) *float64ListNode {
+func (l *list[M, S]) GetNodeByRank(rank int) *listNode[M, S] {
 	var traversed int
 
 	x := l.header
@@ -430,7 +438,7 @@ func (l *float64List) GetNodeByRank(rank int) *float64ListNode {
 }
 
 // FirstInRange finds the first node that is contained in the specified range.
-func (l *float64List) FirstInRange(min, max float64, opt RangeOpt) *float64ListNode {
+func (l *list[M, S]) FirstInRange(min, max S, opt RangeOpt) *listNode[M, S] {
 	if !l.IsInRange(min, max, opt) {
 		return nil
 	}
@@ -453,7 +461,7 @@ func (l *float64List) FirstInRange(min, max float64, opt RangeOpt) *float64ListN
 }
 
 // LastInRange finds the last node that is contained in the specified range.
-func (l *float64List) LastInRange(min, max float64, opt RangeOpt) *float64ListNode {
+func (l *list[M, S]) LastInRange(min, max S, opt RangeOpt) *listNode[M, S] {
 	if !l.IsInRange(min, max, opt) {
 		return nil
 	}
@@ -475,7 +483,7 @@ func (l *float64List) LastInRange(min, max float64, opt RangeOpt) *float64ListNo
 }
 
 // IsInRange returns whether there is a port of sorted set in given range.
-func (l *float64List) IsInRange(min, max float64, opt RangeOpt) bool {
+func (l *list[M, S]) IsInRange(min, max S, opt RangeOpt) bool {
 	// Test empty range.
 	if min > max || (min == max && (opt.ExcludeMin || opt.ExcludeMax)) {
 		return false
@@ -488,3 +496,198 @@ func (l *float64List) IsInRange(min, max float64, opt RangeOpt) bool {
 	}
 	return true
 }
+
+// Skip returns the node offset elements after x, or nil if there is no such
+// node. The node x must be in the skiplist or be nil.
+func (l *list[M, S]) Skip(x *listNode[M, S], offset int) *listNode[M, S] {
+	if x == nil || offset <= 0 {
+		return x
+	}
+	return l.GetNodeByRank(l.Rank(x.score, x.value) + offset)
+}
+
+// SkipBack returns the node offset elements before x, or nil if there is no
+// such node. The node x must be in the skiplist or be nil.
+func (l *list[M, S]) SkipBack(x *listNode[M, S], offset int) *listNode[M, S] {
+	if x == nil || offset <= 0 {
+		return x
+	}
+	rank := l.Rank(x.score, x.value) - offset
+	if rank < 1 {
+		return nil
+	}
+	return l.GetNodeByRank(rank)
+}
+
+func (l *list[M, S]) lexGreaterThanMin(value M, min M, opt LexRangeOpt) bool {
+	if opt.UnboundedMin {
+		return true
+	}
+	if opt.ExcludeMin {
+		return l.compare(value, min) > 0
+	} else {
+		return l.compare(value, min) >= 0
+	}
+}
+
+func (l *list[M, S]) lexLessThanMax(value M, max M, opt LexRangeOpt) bool {
+	if opt.UnboundedMax {
+		return true
+	}
+	if opt.ExcludeMax {
+		return l.compare(value, max) < 0
+	} else {
+		return l.compare(value, max) <= 0
+	}
+}
+
+// DeleteRangeByLex deletes all the elements with value between min and max
+// from the skiplist.
+// Both min and max can be inclusive, exclusive or unbounded (see LexRangeOpt).
+//
+// This function returns the deleted elements.
+func (l *list[M, S]) DeleteRangeByLex(min, max M, opt LexRangeOpt, dict map[M]S) []Node[M, S] {
+	var (
+		update  [maxLevel]*listNode[M, S]
+		removed []Node[M, S]
+	)
+
+	x := l.header
+	for i := l.highestLevel - 1; i >= 0; i-- {
+		next := x.loadNext(i)
+		for next != nil && !l.lexGreaterThanMin(next.value, min, opt) {
+			x = next
+			next = x.loadNext(i)
+		}
+		update[i] = x
+	}
+
+	// Current node is the last with value not greater than min.
+	x = x.loadNext(0)
+
+	// Delete nodes in range.
+	for x != nil && l.lexLessThanMax(x.value, max, opt) {
+		next := x.loadNext(0)
+		l.deleteNode(x, &update)
+		delete(dict, x.value)
+		removed = append(removed, Node[M, S]{
+			Value: x.value,
+			Score: x.score,
+		})
+		x = next
+	}
+
+	return removed
+}
+
+// FirstInLexRange finds the first node that is contained in the specified
+// lex range.
+func (l *list[M, S]) FirstInLexRange(min, max M, opt LexRangeOpt) *listNode[M, S] {
+	if !l.IsInLexRange(min, max, opt) {
+		return nil
+	}
+
+	x := l.header
+	for i := l.highestLevel - 1; i >= 0; i-- {
+		next := x.loadNext(i)
+		for next != nil && !l.lexGreaterThanMin(next.value, min, opt) {
+			x = next
+			next = x.loadNext(i)
+		}
+	}
+
+	// The next node MUST not be NULL (excluded by IsInLexRange).
+	x = x.loadNext(0)
+	if !l.lexLessThanMax(x.value, max, opt) {
+		return nil
+	}
+	return x
+}
+
+// LastInLexRange finds the last node that is contained in the specified
+// lex range.
+func (l *list[M, S]) LastInLexRange(min, max M, opt LexRangeOpt) *listNode[M, S] {
+	if !l.IsInLexRange(min, max, opt) {
+		return nil
+	}
+
+	x := l.header
+	for i := l.highestLevel - 1; i >= 0; i-- {
+		next := x.loadNext(i)
+		for next != nil && l.lexLessThanMax(next.value, max, opt) {
+			x = next
+			next = x.loadNext(i)
+		}
+	}
+
+	// The node x must not be NULL (excluded by IsInLexRange).
+	if !l.lexGreaterThanMin(x.value, min, opt) {
+		return nil
+	}
+	return x
+}
+
+// IsInLexRange returns whether there is a port of sorted set in given lex
+// range.
+func (l *list[M, S]) IsInLexRange(min, max M, opt LexRangeOpt) bool {
+	// Test empty range.
+	if !opt.UnboundedMin && !opt.UnboundedMax {
+		if c := l.compare(min, max); c > 0 || (c == 0 && (opt.ExcludeMin || opt.ExcludeMax)) {
+			return false
+		}
+	}
+	if l.tail == nil || !l.lexGreaterThanMin(l.tail.value, min, opt) {
+		return false
+	}
+	if next := l.header.loadNext(0); next == nil || !l.lexLessThanMax(next.value, max, opt) {
+		return false
+	}
+	return true
+}
+
+// listBuilder builds a skiplist from elements in order in O(n) time, by
+// appending them after the tail.
+type listBuilder[M comparable, S cmp.Ordered] struct {
+	l    *list[M, S]
+	last [maxLevel]*listNode[M, S] // the last node of every level
+	rank [maxLevel]int             // 1-based rank of the last nodes
+}
+
+// newListBuilder returns a builder of the empty skiplist l.
+func newListBuilder[M comparable, S cmp.Ordered](l *list[M, S]) *listBuilder[M, S] {
+	b := &listBuilder[M, S]{l: l}
+	for i := range b.last {
+		b.last[i] = l.header
+	}
+	return b
+}
+
+// Append appends a new element after the tail, it returns false if the
+// element is not after the tail.
+//
+// NOTE: spans of the last nodes are not updated until Finish is called.
+func (b *listBuilder[M, S]) Append(score S, value M) bool {
+	l := b.l
+	if l.tail != nil && !l.lessThan(l.tail, score, value) {
+		return false
+	}
+	l.length++
+	level := l.randomLevel()
+	x := newListNode(score, value, level)
+	for i := 0; i < level; i++ {
+		b.last[i].storeNextAndSpan(i, x, l.length-b.rank[i])
+		b.last[i], b.rank[i] = x, l.length
+	}
+	x.prev = l.tail
+	l.tail = x
+	l.highestLevel = max(l.highestLevel, level)
+	return true
+}
+
+// Finish updates spans of the last nodes, the skiplist is ready for other
+// operations after that.
+func (b *listBuilder[M, S]) Finish() {
+	for i := 0; i < b.l.highestLevel; i++ {
+		b.last[i].storeSpan(i, b.l.length-b.rank[i])
+	}
+}
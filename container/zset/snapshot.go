@@ -0,0 +1,117 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package zset
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/alimy/tryst/internal/binenc"
+)
+
+// A snapshot of the sorted set is written in the order of the sorted set,
+// the values and scores must be bools, numbers, strings or byte slices, or
+// implement encoding.BinaryMarshaler and encoding.BinaryUnmarshaler.
+
+// WriteTo writes a snapshot of the sorted set to w. It's a consistent
+// snapshot, the sorted set is locked for reading until it's written.
+func (z *SortedSet[M, S]) WriteTo(w io.Writer) (int64, error) {
+	vc, sc, err := codecs[M, S]()
+	if err != nil {
+		return 0, err
+	}
+
+	z.mu.RLock()
+	defer z.mu.RUnlock()
+
+	e := binenc.NewEncoder(w, binenc.Header{Kind: binenc.KindSortedSet, Key: vc.Tag, Value: sc.Tag})
+	for x := z.list.header.loadNext(0); x != nil; x = x.loadNext(0) {
+		vc.Encode(e, x.value)
+		sc.Encode(e, x.score)
+		e.Next()
+	}
+	return e.Close()
+}
+
+// ReadFrom replaces elements of the sorted set with the snapshot read from
+// r. The skiplist is built in O(n) time if the snapshot is in the order of
+// the sorted set, like one written by a sorted set of the same order,
+// otherwise the elements are added one by one.
+//
+// If r is not an io.ByteReader, it's buffered and more data may be read
+// beyond the end of the snapshot.
+func (z *SortedSet[M, S]) ReadFrom(r io.Reader) (int64, error) {
+	z.mu.RLock()
+	l := z.list
+	z.mu.RUnlock()
+	if l == nil {
+		return 0, errors.New("zset: ReadFrom on sorted set not created by New or NewFunc")
+	}
+	vc, sc, err := codecs[M, S]()
+	if err != nil {
+		return 0, err
+	}
+	d := binenc.NewDecoder(r, binenc.Header{Kind: binenc.KindSortedSet, Key: vc.Tag, Value: sc.Tag})
+	ns := NewFunc[M, S](l.compare)
+	b := newListBuilder(ns.list)
+	sorted := true
+	for d.Next() {
+		value, score := vc.Decode(d), sc.Decode(d)
+		if d.Err() != nil {
+			break
+		}
+		if sorted {
+			if _, ok := ns.dict[value]; !ok && b.Append(score, value) {
+				ns.dict[value] = score
+				continue
+			}
+			sorted = false
+			b.Finish()
+		}
+		ns.Add(score, value)
+	}
+	if err = d.Err(); err != nil {
+		return d.N(), fmt.Errorf("zset: %w", err)
+	}
+	if sorted {
+		b.Finish()
+	}
+
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	z.dict, z.list = ns.dict, ns.list
+	return d.N(), nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, see WriteTo.
+func (z *SortedSet[M, S]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := z.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, see ReadFrom.
+func (z *SortedSet[M, S]) UnmarshalBinary(data []byte) error {
+	_, err := z.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+func codecs[M comparable, S cmp.Ordered]() (binenc.Codec[M], binenc.Codec[S], error) {
+	vc, err := binenc.CodecFor[M]()
+	if err != nil {
+		return vc, binenc.Codec[S]{}, fmt.Errorf("zset: value: %w", err)
+	}
+	sc, err := binenc.CodecFor[S]()
+	if err != nil {
+		return vc, sc, fmt.Errorf("zset: score: %w", err)
+	}
+	return vc, sc, nil
+}
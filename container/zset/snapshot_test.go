@@ -0,0 +1,94 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package zset
+
+import (
+	"bytes"
+	"cmp"
+	"strconv"
+	"testing"
+
+	"github.com/alimy/tryst/lang/fastrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFloat64SetSnapshot(t *testing.T) {
+	const N = 3000
+	z := NewFloat64()
+	for i := 0; i < N; i++ {
+		// repeated scores are ordered by value
+		z.Add(float64(fastrand.Intn(100)), strconv.Itoa(i))
+	}
+	data, err := z.MarshalBinary()
+	assert.NoError(t, err)
+
+	z2 := NewFloat64()
+	z2.Add(1, "stale")
+	assert.NoError(t, z2.UnmarshalBinary(data))
+	assert.Equal(t, N, z2.Len())
+	assert.False(t, z2.Contains("stale"))
+	assert.Equal(t, z.Range(0, -1), z2.Range(0, -1))
+	assert.Equal(t, z.RevRange(0, -1), z2.RevRange(0, -1))
+	for i := 0; i < N; i += 97 {
+		v := strconv.Itoa(i)
+		assert.Equal(t, z.Rank(v), z2.Rank(v))
+	}
+	testInternalSpan(t, z2)
+	testIsSorted(t, z2)
+
+	// the sorted set is usable after restore
+	z2.Add(-1, "first")
+	z2.IncrBy(1000, "0")
+	z2.Remove("1")
+	assert.Equal(t, 0, z2.Rank("first"))
+	assert.Equal(t, 0, z2.RevRank("0"))
+	testInternalSpan(t, z2)
+}
+
+func TestSortedSetSnapshot(t *testing.T) {
+	z := New[int64, int64]()
+	for i := int64(0); i < 100; i++ {
+		z.Add(i%10, i)
+	}
+	var buf bytes.Buffer
+	n, err := z.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	data := bytes.Clone(buf.Bytes())
+
+	// restored in a different order of values
+	desc := NewFunc[int64, int64](func(a, b int64) int {
+		return cmp.Compare(b, a)
+	})
+	_, err = desc.ReadFrom(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, desc.Len())
+	assert.Equal(t, []Node[int64, int64]{{90, 0}, {80, 0}}, desc.Range(0, 1))
+	testInternalSpan(t, desc)
+
+	// broken snapshot keeps the sorted set unchanged
+	z2 := New[int64, int64]()
+	z2.Add(1, 1)
+	assert.Error(t, z2.UnmarshalBinary(data[:len(data)/2]))
+	assert.Equal(t, 1, z2.Len())
+	// mismatched types
+	assert.Error(t, NewFloat64().UnmarshalBinary(data))
+	assert.Error(t, (&SortedSet[int64, int64]{}).UnmarshalBinary(data))
+}
+
+func TestListBuilder(t *testing.T) {
+	l := newList[string, float64](cmp.Compare[string])
+	b := newListBuilder(l)
+	assert.True(t, b.Append(1, "a"))
+	assert.True(t, b.Append(1, "b"))
+	assert.False(t, b.Append(1, "b"))
+	assert.False(t, b.Append(0, "z"))
+	assert.True(t, b.Append(2, "a"))
+	b.Finish()
+	assert.Equal(t, 3, l.length)
+	assert.Equal(t, 2, l.Rank(1, "b"))
+	assert.Equal(t, "a", l.tail.value)
+	assert.Equal(t, "b", l.tail.prev.value)
+}
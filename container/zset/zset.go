@@ -23,73 +23,118 @@
 package zset
 
 import (
+	"cmp"
 	"sync"
 )
 
-// Float64Node represents an element of Float64Set.
-type Float64Node struct {
-	Value string
-	Score float64
+// Node represents an element of SortedSet.
+type Node[M comparable, S cmp.Ordered] struct {
+	Value M
+	Score S
 }
 
-// Float64Set is a sorted set implementation with string value and float64 score.
-type Float64Set struct {
+// SortedSet is a sorted set implementation with value of type M and score of
+// type S, like SortedSet[int64, float64] for a leaderboard of user IDs.
+type SortedSet[M comparable, S cmp.Ordered] struct {
 	mu   sync.RWMutex
-	dict map[string]float64
-	list *float64List
+	dict map[M]S
+	list *list[M, S]
 }
 
-// NewFloat64 returns an empty string sorted set with int score.
-// strings are sorted in ascending order.
-func NewFloat64() *Float64Set {
-	return &Float64Set{
-		dict: make(map[string]float64),
-		list: newFloat64List(),
+// New returns an empty sorted set, values of the same score are sorted in
+// ascending order.
+func New[M cmp.Ordered, S cmp.Ordered]() *SortedSet[M, S] {
+	return NewFunc[M, S](cmp.Compare[M])
+}
+
+// NewFunc returns an empty sorted set, values of the same score are sorted
+// by compare, which returns a negative number when a < b, a positive number
+// when a > b and zero when a == b.
+func NewFunc[M comparable, S cmp.Ordered](compare func(a, b M) int) *SortedSet[M, S] {
+	return &SortedSet[M, S]{
+		dict: make(map[M]S),
+		list: newList[M, S](compare),
 	}
 }
 
-// UnionFloat64 returns the union of given sorted sets, the resulting score of
+// Union returns the union of given sorted sets, the resulting score of
 // a value is the sum of its scores in the sorted sets where it exists.
+// Values of the result are sorted like zs[0], and nil is returned if zs is
+// empty.
+//
+// Union is the replacement of ZUNION command of redis.
+func Union[M comparable, S Number](zs ...*SortedSet[M, S]) *SortedSet[M, S] {
+	return UnionWithOpt(AggregateOpt[S]{}, zs...)
+}
+
+// UnionWithOpt is like Union, but the scores of every sorted set are
+// multiplied by the weight of it, and aggregated as opt.Aggregate.
 //
-// UnionFloat64 is the replacement of UNIONSTORE command of redis.
-func UnionFloat64(zs ...*Float64Set) *Float64Set {
-	dest := NewFloat64()
-	for _, z := range zs {
+// UnionWithOpt is the replacement of ZUNION command with WEIGHTS and
+// AGGREGATE options of redis.
+func UnionWithOpt[M comparable, S Number](opt AggregateOpt[S], zs ...*SortedSet[M, S]) *SortedSet[M, S] {
+	if len(zs) == 0 {
+		return nil
+	}
+	dest := newDest(zs)
+	for i, z := range zs {
 		for _, n := range z.Range(0, -1) {
-			dest.Add(n.Score, n.Value)
+			score := opt.weight(i, n.Score)
+			if old, ok := dest.Score(n.Value); ok {
+				score = opt.aggregate(old, score)
+			}
+			dest.Add(score, n.Value)
 		}
 	}
 	return dest
 }
 
-// InterFloat64 returns the intersection of given sorted sets, the resulting
+// Inter returns the intersection of given sorted sets, the resulting
 // score of a value is the sum of its scores in the sorted sets where it exists.
+// Values of the result are sorted like zs[0], and nil is returned if zs is
+// empty.
+//
+// Inter is the replacement of ZINTER command of redis.
+func Inter[M comparable, S Number](zs ...*SortedSet[M, S]) *SortedSet[M, S] {
+	return InterWithOpt(AggregateOpt[S]{}, zs...)
+}
+
+// InterWithOpt is like Inter, but the scores of every sorted set are
+// multiplied by the weight of it, and aggregated as opt.Aggregate.
 //
-// InterFloat64 is the replacement of INTERSTORE command of redis.
-func InterFloat64(zs ...*Float64Set) *Float64Set {
-	dest := NewFloat64()
+// InterWithOpt is the replacement of ZINTER command with WEIGHTS and
+// AGGREGATE options of redis.
+func InterWithOpt[M comparable, S Number](opt AggregateOpt[S], zs ...*SortedSet[M, S]) *SortedSet[M, S] {
 	if len(zs) == 0 {
-		return dest
+		return nil
 	}
+	dest := newDest(zs)
 	for _, n := range zs[0].Range(0, -1) {
-		ok := true
-		for _, z := range zs[1:] {
-			if !z.Contains(n.Value) {
-				ok = false
+		score, ok := opt.weight(0, n.Score), true
+		for i, z := range zs[1:] {
+			var s S
+			if s, ok = z.Score(n.Value); !ok {
 				break
 			}
+			score = opt.aggregate(score, opt.weight(i+1, s))
 		}
 		if ok {
-			dest.Add(n.Score, n.Value)
+			dest.Add(score, n.Value)
 		}
 	}
 	return dest
 }
 
-// Len returns the length of Float64Set.
+// newDest returns an empty sorted set that values are sorted like zs[0],
+// zs must not be empty.
+func newDest[M comparable, S Number](zs []*SortedSet[M, S]) *SortedSet[M, S] {
+	return NewFunc[M, S](zs[0].list.compare)
+}
+
+// Len returns the length of SortedSet.
 //
 // Len is the replacement of ZCARD command of redis.
-func (z *Float64Set) Len() int {
+func (z *SortedSet[M, S]) Len() int {
 	z.mu.RLock()
 	defer z.mu.RUnlock()
 
@@ -100,7 +145,7 @@ func (z *Float64Set) Len() int {
 // Returns true if the value is newly created.
 //
 // Add is the replacement of ZADD command of redis.
-func (z *Float64Set) Add(score float64, value string) bool {
+func (z *SortedSet[M, S]) Add(score S, value M) bool {
 	z.mu.Lock()
 	defer z.mu.Unlock()
 
@@ -122,16 +167,16 @@ func (z *Float64Set) Add(score float64, value string) bool {
 
 // Remove removes a value from the sorted set.
 // Returns score of the removed value and true if the node was found and deleted,
-// otherwise returns (0.0, false).
+// otherwise returns zero score and false.
 //
 // Remove is the replacement of ZREM command of redis.
-func (z *Float64Set) Remove(value string) (float64, bool) {
+func (z *SortedSet[M, S]) Remove(value M) (S, bool) {
 	z.mu.Lock()
 	defer z.mu.Unlock()
 
 	score, ok := z.dict[value]
 	if !ok {
-		return 0, false
+		return score, false
 	}
 	delete(z.dict, value)
 	z.list.Delete(score, value)
@@ -143,7 +188,7 @@ func (z *Float64Set) Remove(value string) (float64, bool) {
 // (as if its previous score was zero).
 //
 // IncrBy is the replacement of ZINCRBY command of redis.
-func (z *Float64Set) IncrBy(incr float64, value string) (float64, bool) {
+func (z *SortedSet[M, S]) IncrBy(incr S, value M) (S, bool) {
 	z.mu.Lock()
 	defer z.mu.Unlock()
 
@@ -163,7 +208,7 @@ func (z *Float64Set) IncrBy(incr float64, value string) (float64, bool) {
 }
 
 // Contains returns whether the value exists in sorted set.
-func (z *Float64Set) Contains(value string) bool {
+func (z *SortedSet[M, S]) Contains(value M) bool {
 	_, ok := z.Score(value)
 	return ok
 }
@@ -171,7 +216,7 @@ func (z *Float64Set) Contains(value string) bool {
 // Score returns the score of the value in the sorted set.
 //
 // Score is the replacement of ZSCORE command of redis.
-func (z *Float64Set) Score(value string) (float64, bool) {
+func (z *SortedSet[M, S]) Score(value M) (S, bool) {
 	z.mu.RLock()
 	defer z.mu.RUnlock()
 
@@ -186,7 +231,7 @@ func (z *Float64Set) Score(value string) (float64, bool) {
 // -1 is returned when value is not found.
 //
 // Rank is the replacement of ZRANK command of redis.
-func (z *Float64Set) Rank(value string) int {
+func (z *SortedSet[M, S]) Rank(value M) int {
 	z.mu.RLock()
 	defer z.mu.RUnlock()
 
@@ -205,7 +250,7 @@ func (z *Float64Set) Rank(value string) int {
 // -1 is returned when value is not found.
 //
 // RevRank is the replacement of ZREVRANK command of redis.
-func (z *Float64Set) RevRank(value string) int {
+func (z *SortedSet[M, S]) RevRank(value M) int {
 	z.mu.RLock()
 	defer z.mu.RUnlock()
 
@@ -214,18 +259,18 @@ func (z *Float64Set) RevRank(value string) int {
 		return -1
 	}
 	// NOTE: list.Rank returns 1-based rank.
-	return z.list.Rank(score, value) - 1
+	return z.list.length - z.list.Rank(score, value)
 }
 
 // Count returns the number of elements in the sorted set at element with a score
 // between min and max (including elements with score equal to min or max).
 //
 // Count is the replacement of ZCOUNT command of redis.
-func (z *Float64Set) Count(min, max float64) int {
+func (z *SortedSet[M, S]) Count(min, max S) int {
 	return z.CountWithOpt(min, max, RangeOpt{})
 }
 
-func (z *Float64Set) CountWithOpt(min, max float64, opt RangeOpt) int {
+func (z *SortedSet[M, S]) CountWithOpt(min, max S, opt RangeOpt) int {
 	z.mu.RLock()
 	defer z.mu.RUnlock()
 
@@ -250,7 +295,8 @@ func (z *Float64Set) CountWithOpt(min, max float64, opt RangeOpt) int {
 // and so on.
 //
 // The returned elements are ordered by score, from lowest to highest.
-// Elements with the same score are ordered lexicographically.
+// Elements with the same score are ordered lexicographically, or by the
+// compare function given to NewFunc.
 //
 // This function won't panic even when the given rank out of range.
 //
@@ -259,7 +305,7 @@ func (z *Float64Set) CountWithOpt(min, max float64, opt RangeOpt) int {
 // the gap of calls.
 //
 // Range is the replacement of ZRANGE command of redis.
-func (z *Float64Set) Range(start, stop int) []Float64Node {
+func (z *SortedSet[M, S]) Range(start, stop int) []Node[M, S] {
 	z.mu.RLock()
 	defer z.mu.RUnlock()
 
@@ -271,11 +317,11 @@ func (z *Float64Set) Range(start, stop int) []Float64Node {
 		stop = z.list.length + stop
 	}
 
-	var res []Float64Node
+	var res []Node[M, S]
 	x := z.list.GetNodeByRank(start + 1) // 0-based rank -> 1-based rank
 	for x != nil && start <= stop {
 		start++
-		res = append(res, Float64Node{
+		res = append(res, Node[M, S]{
 			Score: x.score,
 			Value: x.value,
 		})
@@ -289,18 +335,29 @@ func (z *Float64Set) Range(start, stop int) []Float64Node {
 // The elements are considered to be ordered from low to high scores.
 //
 // RangeByScore is the replacement of ZRANGEBYSCORE command of redis.
-func (z *Float64Set) RangeByScore(min, max float64) []Float64Node {
+func (z *SortedSet[M, S]) RangeByScore(min, max S) []Node[M, S] {
 	return z.RangeByScoreWithOpt(min, max, RangeOpt{})
 }
 
-func (z *Float64Set) RangeByScoreWithOpt(min, max float64, opt RangeOpt) []Float64Node {
+// RangeByScoreWithOpt is like RangeByScore, but min and max can be exclusive.
+func (z *SortedSet[M, S]) RangeByScoreWithOpt(min, max S, opt RangeOpt) []Node[M, S] {
+	return z.RangeByScoreWithLimit(min, max, opt, noLimit)
+}
+
+// RangeByScoreWithLimit is like RangeByScoreWithOpt, but the range is limited
+// by limit like the LIMIT option of ZRANGEBYSCORE.
+func (z *SortedSet[M, S]) RangeByScoreWithLimit(min, max S, opt RangeOpt, limit LimitOpt) []Node[M, S] {
+	if limit.empty() {
+		return nil
+	}
+
 	z.mu.RLock()
 	defer z.mu.RUnlock()
 
-	var res []Float64Node
-	x := z.list.FirstInRange(min, max, opt)
-	for x != nil && (x.score < max || (!opt.ExcludeMax && x.score == max)) {
-		res = append(res, Float64Node{
+	var res []Node[M, S]
+	x := z.list.Skip(z.list.FirstInRange(min, max, opt), limit.Offset)
+	for x != nil && (x.score < max || (!opt.ExcludeMax && x.score == max)) && !limit.full(len(res)) {
+		res = append(res, Node[M, S]{
 			Score: x.score,
 			Value: x.value,
 		})
@@ -324,7 +381,7 @@ func (z *Float64Set) RangeByScoreWithOpt(min, max float64, opt RangeOpt) []Float
 // the gap of calls.
 //
 // RevRange is the replacement of ZREVRANGE command of redis.
-func (z *Float64Set) RevRange(start, stop int) []Float64Node {
+func (z *SortedSet[M, S]) RevRange(start, stop int) []Node[M, S] {
 	z.mu.RLock()
 	defer z.mu.RUnlock()
 
@@ -336,11 +393,11 @@ func (z *Float64Set) RevRange(start, stop int) []Float64Node {
 		stop = z.list.length + stop
 	}
 
-	var res []Float64Node
+	var res []Node[M, S]
 	x := z.list.GetNodeByRank(z.list.length - start) // 0-based rank -> 1-based rank
 	for x != nil && start <= stop {
 		start++
-		res = append(res, Float64Node{
+		res = append(res, Node[M, S]{
 			Score: x.score,
 			Value: x.value,
 		})
@@ -354,18 +411,30 @@ func (z *Float64Set) RevRange(start, stop int) []Float64Node {
 // The elements are considered to be ordered from high to low scores.
 //
 // RevRangeByScore is the replacement of ZREVRANGEBYSCORE command of redis.
-func (z *Float64Set) RevRangeByScore(max, min float64) []Float64Node {
+func (z *SortedSet[M, S]) RevRangeByScore(max, min S) []Node[M, S] {
 	return z.RevRangeByScoreWithOpt(max, min, RangeOpt{})
 }
 
-func (z *Float64Set) RevRangeByScoreWithOpt(max, min float64, opt RangeOpt) []Float64Node {
+// RevRangeByScoreWithOpt is like RevRangeByScore, but min and max can be
+// exclusive.
+func (z *SortedSet[M, S]) RevRangeByScoreWithOpt(max, min S, opt RangeOpt) []Node[M, S] {
+	return z.RevRangeByScoreWithLimit(max, min, opt, noLimit)
+}
+
+// RevRangeByScoreWithLimit is like RevRangeByScoreWithOpt, but the range is
+// limited by limit like the LIMIT option of ZREVRANGEBYSCORE.
+func (z *SortedSet[M, S]) RevRangeByScoreWithLimit(max, min S, opt RangeOpt, limit LimitOpt) []Node[M, S] {
+	if limit.empty() {
+		return nil
+	}
+
 	z.mu.RLock()
 	defer z.mu.RUnlock()
 
-	var res []Float64Node
-	x := z.list.LastInRange(min, max, opt)
-	for x != nil && (x.score > min || (!opt.ExcludeMin && x.score == min)) {
-		res = append(res, Float64Node{
+	var res []Node[M, S]
+	x := z.list.SkipBack(z.list.LastInRange(min, max, opt), limit.Offset)
+	for x != nil && (x.score > min || (!opt.ExcludeMin && x.score == min)) && !limit.full(len(res)) {
+		res = append(res, Node[M, S]{
 			Score: x.score,
 			Value: x.value,
 		})
@@ -381,7 +450,7 @@ func (z *Float64Set) RevRangeByScoreWithOpt(max, min float64, opt RangeOpt) []Fl
 // and so on.
 //
 // RemoveRangeByRank is the replacement of ZREMRANGEBYRANK command of redis.
-func (z *Float64Set) RemoveRangeByRank(start, stop int) []Float64Node {
+func (z *SortedSet[M, S]) RemoveRangeByRank(start, stop int) []Node[M, S] {
 	z.mu.Lock()
 	defer z.mu.Unlock()
 
@@ -400,13 +469,137 @@ func (z *Float64Set) RemoveRangeByRank(start, stop int) []Float64Node {
 // between min and max (including elements with score equal to min or max).
 //
 // RemoveRangeByScore is the replacement of ZREMRANGEBYSCORE command of redis.
-func (z *Float64Set) RemoveRangeByScore(min, max float64) []Float64Node {
+func (z *SortedSet[M, S]) RemoveRangeByScore(min, max S) []Node[M, S] {
 	return z.RemoveRangeByScoreWithOpt(min, max, RangeOpt{})
 }
 
-func (z *Float64Set) RemoveRangeByScoreWithOpt(min, max float64, opt RangeOpt) []Float64Node {
+func (z *SortedSet[M, S]) RemoveRangeByScoreWithOpt(min, max S, opt RangeOpt) []Node[M, S] {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	return z.list.DeleteRangeByScore(min, max, opt, z.dict)
+}
+
+// RangeByLex returns all the elements in the sorted set with a value between
+// min and max (including elements with value equal to min or max).
+// The elements are ordered by value, so it's expected that all the elements
+// have the same score, otherwise the returned elements are unspecified.
+//
+// RangeByLex is the replacement of ZRANGEBYLEX command of redis.
+func (z *SortedSet[M, S]) RangeByLex(min, max M) []Node[M, S] {
+	return z.RangeByLexWithOpt(min, max, LexRangeOpt{})
+}
+
+// RangeByLexWithOpt is like RangeByLex, but min and max can be exclusive or
+// unbounded.
+func (z *SortedSet[M, S]) RangeByLexWithOpt(min, max M, opt LexRangeOpt) []Node[M, S] {
+	return z.RangeByLexWithLimit(min, max, opt, noLimit)
+}
+
+// RangeByLexWithLimit is like RangeByLexWithOpt, but the range is limited by
+// limit like the LIMIT option of ZRANGEBYLEX.
+func (z *SortedSet[M, S]) RangeByLexWithLimit(min, max M, opt LexRangeOpt, limit LimitOpt) []Node[M, S] {
+	if limit.empty() {
+		return nil
+	}
+
 	z.mu.RLock()
 	defer z.mu.RUnlock()
 
-	return z.list.DeleteRangeByScore(min, max, opt, z.dict)
+	var res []Node[M, S]
+	x := z.list.Skip(z.list.FirstInLexRange(min, max, opt), limit.Offset)
+	for x != nil && z.list.lexLessThanMax(x.value, max, opt) && !limit.full(len(res)) {
+		res = append(res, Node[M, S]{
+			Score: x.score,
+			Value: x.value,
+		})
+		x = x.loadNext(0)
+	}
+	return res
+}
+
+// RevRangeByLex returns all the elements in the sorted set with a value
+// between max and min (including elements with value equal to max or min).
+// The elements are ordered by value from high to low, so it's expected that
+// all the elements have the same score.
+//
+// RevRangeByLex is the replacement of ZREVRANGEBYLEX command of redis.
+func (z *SortedSet[M, S]) RevRangeByLex(max, min M) []Node[M, S] {
+	return z.RevRangeByLexWithOpt(max, min, LexRangeOpt{})
+}
+
+// RevRangeByLexWithOpt is like RevRangeByLex, but min and max can be exclusive
+// or unbounded.
+func (z *SortedSet[M, S]) RevRangeByLexWithOpt(max, min M, opt LexRangeOpt) []Node[M, S] {
+	return z.RevRangeByLexWithLimit(max, min, opt, noLimit)
+}
+
+// RevRangeByLexWithLimit is like RevRangeByLexWithOpt, but the range is
+// limited by limit like the LIMIT option of ZREVRANGEBYLEX.
+func (z *SortedSet[M, S]) RevRangeByLexWithLimit(max, min M, opt LexRangeOpt, limit LimitOpt) []Node[M, S] {
+	if limit.empty() {
+		return nil
+	}
+
+	z.mu.RLock()
+	defer z.mu.RUnlock()
+
+	var res []Node[M, S]
+	x := z.list.SkipBack(z.list.LastInLexRange(min, max, opt), limit.Offset)
+	for x != nil && z.list.lexGreaterThanMin(x.value, min, opt) && !limit.full(len(res)) {
+		res = append(res, Node[M, S]{
+			Score: x.score,
+			Value: x.value,
+		})
+		x = x.prev
+	}
+	return res
+}
+
+// LexCount returns the number of elements in the sorted set with a value
+// between min and max (including elements with value equal to min or max).
+// It's expected that all the elements have the same score.
+//
+// LexCount is the replacement of ZLEXCOUNT command of redis.
+func (z *SortedSet[M, S]) LexCount(min, max M) int {
+	return z.LexCountWithOpt(min, max, LexRangeOpt{})
+}
+
+// LexCountWithOpt is like LexCount, but min and max can be exclusive or
+// unbounded.
+func (z *SortedSet[M, S]) LexCountWithOpt(min, max M, opt LexRangeOpt) int {
+	z.mu.RLock()
+	defer z.mu.RUnlock()
+
+	first := z.list.FirstInLexRange(min, max, opt)
+	if first == nil {
+		return 0
+	}
+	// Sub 1 for 1-based rank.
+	firstRank := z.list.Rank(first.score, first.value) - 1
+	last := z.list.LastInLexRange(min, max, opt)
+	if last == nil {
+		return z.list.length - firstRank
+	}
+	// Sub 1 for 1-based rank.
+	lastRank := z.list.Rank(last.score, last.value) - 1
+	return lastRank - firstRank + 1
+}
+
+// RemoveRangeByLex removes all elements in the sorted set with a value
+// between min and max (including elements with value equal to min or max).
+// It's expected that all the elements have the same score.
+//
+// RemoveRangeByLex is the replacement of ZREMRANGEBYLEX command of redis.
+func (z *SortedSet[M, S]) RemoveRangeByLex(min, max M) []Node[M, S] {
+	return z.RemoveRangeByLexWithOpt(min, max, LexRangeOpt{})
+}
+
+// RemoveRangeByLexWithOpt is like RemoveRangeByLex, but min and max can be
+// exclusive or unbounded.
+func (z *SortedSet[M, S]) RemoveRangeByLexWithOpt(min, max M, opt LexRangeOpt) []Node[M, S] {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	return z.list.DeleteRangeByLex(min, max, opt, z.dict)
 }
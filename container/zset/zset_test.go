@@ -15,6 +15,7 @@
 package zset
 
 import (
+	"cmp"
 	"fmt"
 	"math/rand"
 	"sort"
@@ -218,7 +219,7 @@ func testIsSorted(t *testing.T, z *Float64Set) {
 	assert.True(t, sort.Float64sAreSorted(scores))
 }
 
-func testInternalSpan(t *testing.T, z *Float64Set) {
+func testInternalSpan[M comparable, S cmp.Ordered](t *testing.T, z *SortedSet[M, S]) {
 	l := z.list
 	for i := l.highestLevel - 1; i >= 0; i-- {
 		x := l.header
@@ -596,6 +597,22 @@ func TestUnionFloat64(t *testing.T) {
 	}
 }
 
+func TestUnionFloat64_Overlap(t *testing.T) {
+	z1, z2, z3 := NewFloat64(), NewFloat64(), NewFloat64()
+	z1.Add(1, "a")
+	z1.Add(2, "b")
+	z2.Add(10, "b")
+	z2.Add(3, "c")
+	z3.Add(100, "b")
+	// the scores of a value in more than one set are summed
+	assert.Equal(t, []Float64Node{
+		{Value: "a", Score: 1},
+		{Value: "c", Score: 3},
+		{Value: "b", Score: 112},
+	}, UnionFloat64(z1, z2, z3).Range(0, -1))
+	assert.Equal(t, []Float64Node{{Value: "b", Score: 112}}, InterFloat64(z1, z2, z3).Range(0, -1))
+}
+
 func TestUnionFloat64_Empty(t *testing.T) {
 	z := UnionFloat64()
 	assert.Zero(t, z.Len())
@@ -640,3 +657,180 @@ func TestInterFloat64_Simple(t *testing.T) {
 	z := InterFloat64(z1, z2, z3)
 	assert.Zero(t, z.Len())
 }
+
+func TestSortedSet(t *testing.T) {
+	z := New[int64, int64]()
+	for i := int64(1); i <= 10; i++ {
+		assert.True(t, z.Add(i%3, i))
+	}
+	assert.Equal(t, 10, z.Len())
+	// values of the same score are sorted in ascending order
+	var values []int64
+	for _, n := range z.Range(0, -1) {
+		values = append(values, n.Value)
+	}
+	assert.Equal(t, []int64{3, 6, 9, 1, 4, 7, 10, 2, 5, 8}, values)
+	assert.Equal(t, 0, z.Rank(3))
+	assert.Equal(t, 9, z.RevRank(3))
+	assert.Equal(t, 0, z.RevRank(8))
+	assert.Equal(t, -1, z.RevRank(11))
+
+	s, ok := z.IncrBy(5, 3)
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), s)
+	assert.Equal(t, Node[int64, int64]{Value: 3, Score: 5}, z.RevRange(0, 0)[0])
+	assert.Len(t, z.RangeByScoreWithOpt(1, 2, RangeOpt{ExcludeMax: true}), 4)
+	assert.Len(t, z.RemoveRangeByScore(0, 0), 2)
+	assert.Equal(t, 8, z.Len())
+	testInternalSpan(t, z)
+}
+
+func TestSortedSetFunc(t *testing.T) {
+	z := NewFunc[int64, float64](func(a, b int64) int {
+		return cmp.Compare(b, a)
+	})
+	for i := int64(1); i <= 5; i++ {
+		z.Add(1.5, i)
+	}
+	var values []int64
+	for _, n := range z.Range(0, -1) {
+		values = append(values, n.Value)
+	}
+	assert.Equal(t, []int64{5, 4, 3, 2, 1}, values)
+	assert.Equal(t, 4, z.Rank(1))
+
+	// the result sorts values like the first set
+	z2 := NewFunc[int64, float64](cmp.Compare[int64])
+	z2.Add(1, 1)
+	z2.Add(1, 2)
+	values = values[:0]
+	for _, n := range Union(z, z2).Range(0, -1) {
+		values = append(values, n.Value)
+	}
+	assert.Equal(t, []int64{5, 4, 3, 2, 1}, values)
+}
+
+func TestUnionFunc(t *testing.T) {
+	// point is comparable but not ordered
+	type point struct{ X, Y int }
+	compare := func(a, b point) int {
+		return cmp.Or(cmp.Compare(a.X, b.X), cmp.Compare(a.Y, b.Y))
+	}
+	z1, z2 := NewFunc[point, int](compare), NewFunc[point, int](compare)
+	z1.Add(1, point{1, 2})
+	z1.Add(1, point{0, 1})
+	z2.Add(2, point{1, 2})
+	assert.Equal(t, []Node[point, int]{
+		{Value: point{0, 1}, Score: 1},
+		{Value: point{1, 2}, Score: 3},
+	}, Union(z1, z2).Range(0, -1))
+	assert.Equal(t, []Node[point, int]{{Value: point{1, 2}, Score: 3}}, Inter(z1, z2).Range(0, -1))
+	assert.Nil(t, Union[point, int]())
+	assert.Nil(t, Inter[point, int]())
+}
+
+func TestUnionWithOpt(t *testing.T) {
+	z1, z2 := New[string, int64](), New[string, int64]()
+	z1.Add(1, "a")
+	z1.Add(2, "b")
+	z2.Add(10, "b")
+	z2.Add(20, "c")
+
+	for _, c := range []struct {
+		opt    AggregateOpt[int64]
+		expect []Node[string, int64]
+	}{
+		{AggregateOpt[int64]{}, []Node[string, int64]{{"a", 1}, {"b", 12}, {"c", 20}}},
+		{AggregateOpt[int64]{Weights: []int64{3}}, []Node[string, int64]{{"a", 3}, {"b", 16}, {"c", 20}}},
+		{AggregateOpt[int64]{Weights: []int64{1, 2}, Aggregate: AggregateMin}, []Node[string, int64]{{"a", 1}, {"b", 2}, {"c", 40}}},
+		{AggregateOpt[int64]{Aggregate: AggregateMax}, []Node[string, int64]{{"a", 1}, {"b", 10}, {"c", 20}}},
+	} {
+		assert.Equal(t, c.expect, UnionWithOpt(c.opt, z1, z2).Range(0, -1))
+	}
+}
+
+func TestInterWithOpt(t *testing.T) {
+	z1, z2, z3 := NewFloat64(), NewFloat64(), NewFloat64()
+	z1.Add(1, "a")
+	z1.Add(2, "b")
+	z2.Add(10, "b")
+	z2.Add(20, "c")
+	z3.Add(5, "b")
+
+	for _, c := range []struct {
+		opt    AggregateOpt[float64]
+		expect []Float64Node
+	}{
+		{AggregateOpt[float64]{}, []Float64Node{{"b", 17}}},
+		{AggregateOpt[float64]{Weights: []float64{0.5, 0.1, 2}}, []Float64Node{{"b", 12}}},
+		{AggregateOpt[float64]{Aggregate: AggregateMin}, []Float64Node{{"b", 2}}},
+		{AggregateOpt[float64]{Weights: []float64{10}, Aggregate: AggregateMax}, []Float64Node{{"b", 20}}},
+	} {
+		assert.Equal(t, c.expect, InterWithOpt(c.opt, z1, z2, z3).Range(0, -1))
+	}
+	assert.Zero(t, InterWithOpt(AggregateOpt[float64]{}, z1, NewFloat64()).Len())
+}
+
+func TestFloat64SetRangeByLex(t *testing.T) {
+	z := NewFloat64()
+	for _, v := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		z.Add(0, v)
+	}
+	values := func(ns []Float64Node) (res []string) {
+		for _, n := range ns {
+			res = append(res, n.Value)
+		}
+		return
+	}
+
+	assert.Equal(t, []string{"b", "c", "d"}, values(z.RangeByLex("b", "d")))
+	assert.Equal(t, []string{"c"}, values(z.RangeByLexWithOpt("b", "d", LexRangeOpt{ExcludeMin: true, ExcludeMax: true})))
+	assert.Equal(t, []string{"a", "b", "c"}, values(z.RangeByLexWithOpt("", "c", LexRangeOpt{UnboundedMin: true})))
+	assert.Equal(t, []string{"e", "f", "g"}, values(z.RangeByLexWithOpt("e", "", LexRangeOpt{UnboundedMax: true})))
+	assert.Equal(t, []string{"c", "d"}, values(z.RangeByLexWithLimit("", "", LexRangeOpt{UnboundedMin: true, UnboundedMax: true}, LimitOpt{Offset: 2, Count: 2})))
+	assert.Empty(t, z.RangeByLex("d", "b"))
+	assert.Empty(t, z.RangeByLexWithOpt("b", "b", LexRangeOpt{ExcludeMin: true}))
+	assert.Empty(t, z.RangeByLex("h", "z"))
+	assert.Empty(t, z.RangeByLexWithLimit("a", "g", LexRangeOpt{}, LimitOpt{Offset: 7, Count: -1}))
+
+	assert.Equal(t, []string{"d", "c", "b"}, values(z.RevRangeByLex("d", "b")))
+	assert.Equal(t, []string{"e", "d"}, values(z.RevRangeByLexWithLimit("", "b", LexRangeOpt{UnboundedMax: true}, LimitOpt{Offset: 2, Count: 2})))
+	assert.Empty(t, z.RevRangeByLexWithLimit("g", "a", LexRangeOpt{}, LimitOpt{Offset: 7, Count: -1}))
+
+	assert.Equal(t, 3, z.LexCount("b", "d"))
+	assert.Equal(t, 7, z.LexCountWithOpt("", "", LexRangeOpt{UnboundedMin: true, UnboundedMax: true}))
+	assert.Equal(t, 2, z.LexCountWithOpt("e", "", LexRangeOpt{ExcludeMin: true, UnboundedMax: true}))
+	assert.Zero(t, z.LexCount("x", "y"))
+
+	assert.Equal(t, []string{"b", "c"}, values(z.RemoveRangeByLexWithOpt("a", "d", LexRangeOpt{ExcludeMin: true, ExcludeMax: true})))
+	assert.Equal(t, []string{"a", "d", "e", "f", "g"}, values(z.Range(0, -1)))
+	assert.False(t, z.Contains("b"))
+	testInternalSpan(t, z)
+}
+
+func TestFloat64SetRangeByScoreLimit(t *testing.T) {
+	z := NewFloat64()
+	for i := 0; i < 10; i++ {
+		z.Add(float64(i), strconv.Itoa(i))
+	}
+	values := func(ns []Float64Node) (res []string) {
+		for _, n := range ns {
+			res = append(res, n.Value)
+		}
+		return
+	}
+
+	assert.Equal(t, []string{"3", "4", "5"}, values(z.RangeByScoreWithLimit(1, 8, RangeOpt{}, LimitOpt{Offset: 2, Count: 3})))
+	assert.Equal(t, []string{"7", "8"}, values(z.RangeByScoreWithLimit(1, 8, RangeOpt{}, LimitOpt{Offset: 6, Count: 3})))
+	assert.Equal(t, []string{"3", "4", "5", "6", "7"}, values(z.RangeByScoreWithLimit(1, 8, RangeOpt{ExcludeMax: true}, LimitOpt{Offset: 2, Count: -1})))
+	assert.Empty(t, z.RangeByScoreWithLimit(1, 8, RangeOpt{}, LimitOpt{Offset: 8, Count: -1}))
+	assert.Equal(t, []string{"6", "5", "4"}, values(z.RevRangeByScoreWithLimit(8, 1, RangeOpt{}, LimitOpt{Offset: 2, Count: 3})))
+	assert.Equal(t, []string{"2", "1"}, values(z.RevRangeByScoreWithLimit(8, 1, RangeOpt{}, LimitOpt{Offset: 6, Count: 3})))
+	assert.Empty(t, z.RevRangeByScoreWithLimit(8, 1, RangeOpt{}, LimitOpt{Offset: 8, Count: -1}))
+	// like redis, a negative offset or a zero count returns nothing
+	assert.Empty(t, z.RangeByScoreWithLimit(1, 8, RangeOpt{}, LimitOpt{Offset: -1, Count: 3}))
+	assert.Empty(t, z.RevRangeByScoreWithLimit(8, 1, RangeOpt{}, LimitOpt{Offset: -1, Count: -1}))
+	assert.Empty(t, z.RangeByLexWithLimit("1", "8", LexRangeOpt{}, LimitOpt{Offset: -2, Count: 3}))
+	assert.Empty(t, z.RangeByScoreWithLimit(1, 8, RangeOpt{}, LimitOpt{}))
+	assert.Len(t, z.RangeByScoreWithOpt(1, 8, RangeOpt{}), 8)
+}
@@ -0,0 +1,298 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alimy/tryst/auth"
+)
+
+const (
+	// HeaderSignatureKey key id of HMAC request signing
+	HeaderSignatureKey = "X-Signature-Key"
+	// HeaderSignatureTimestamp unix seconds of HMAC request signing
+	HeaderSignatureTimestamp = "X-Signature-Timestamp"
+	// HeaderSignatureNonce random nonce of HMAC request signing
+	HeaderSignatureNonce = "X-Signature-Nonce"
+	// HeaderSignature base64 HMAC-SHA256 signature of request
+	HeaderSignature = "X-Signature"
+
+	_defaultMaxSkew     = 5 * time.Minute
+	_defaultMaxBodySize = 4 << 20
+)
+
+var (
+	// ErrUnauthorized returned by authentication when credentials are missing or invalid
+	ErrUnauthorized = errors.New("http: unauthorized")
+
+	// ErrNonceReused returned by HMAC authentication when a nonce is replayed
+	ErrNonceReused = errors.New("http: signature nonce has been used")
+)
+
+type principalCtxKey struct{}
+
+// Middleware wrap a http.Handler to a new http.Handler
+type Middleware func(http.Handler) http.Handler
+
+// Principal authenticated identity of request
+type Principal struct {
+	// Subject user name or key id
+	Subject string
+	// Scheme authentication scheme like `Basic`, `Bearer` or `HMAC`
+	Scheme string
+	// Claims extra information of the principal given by verifier
+	Claims any
+}
+
+// PasswordLookup return the hashed password of username
+type PasswordLookup func(ctx context.Context, username string) (hashedPassword []byte, err error)
+
+// BearerVerifier verify bearer token and return the principal
+type BearerVerifier func(ctx context.Context, token string) (*Principal, error)
+
+// SecretLookup return the HMAC secret of key id
+type SecretLookup func(ctx context.Context, keyID string) (secret []byte, err error)
+
+// HMACAuthConf configure used to create HMAC request signing middleware
+type HMACAuthConf struct {
+	// Secrets lookup secret by key id, required
+	Secrets SecretLookup
+	// Nonces reject reused nonce in MaxSkew, use in-memory store if nil
+	Nonces auth.UsedCodeStore
+	// MaxSkew max difference between timestamp of request and server, default 5m
+	MaxSkew time.Duration
+	// MaxBodySize max body size to be signed, default 4MB
+	MaxBodySize int64
+	// Now return current time, use time.Now if nil
+	Now func() time.Time
+}
+
+// NewPrincipalContext return a copy of ctx with principal
+func NewPrincipalContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, p)
+}
+
+// PrincipalFrom return the authenticated principal in ctx
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalCtxKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// ClaimsFrom return the claims of authenticated principal in ctx as type T
+func ClaimsFrom[T any](ctx context.Context) (res T, ok bool) {
+	if p, exist := PrincipalFrom(ctx); exist {
+		res, ok = p.Claims.(T)
+	}
+	return
+}
+
+// Chain wrap handler with middlewares, the first middleware is the outermost.
+func Chain(h http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// BasicAuth authenticate request by HTTP Basic credentials, the password is compared
+// by provider with hashed password given by lookup. The password is compared with a
+// dummy hashed password if lookup failed, so unknown usernames can not be told from
+// the response time.
+func BasicAuth(realm string, provider auth.PasswordProvider, lookup PasswordLookup) Middleware {
+	challenge := `Basic realm="` + strings.ReplaceAll(realm, `"`, `\"`) + `", charset="UTF-8"`
+	dummyHash, err := provider.Generate([]byte(rand.Text()))
+	if err != nil {
+		panic("http: generate dummy hashed password of basic authentication: " + err.Error())
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			if !ok {
+				unauthorized(w, challenge)
+				return
+			}
+			hashedPassword, err := lookup(r.Context(), username)
+			if err != nil {
+				provider.Compare(dummyHash, []byte(password))
+				unauthorized(w, challenge)
+				return
+			}
+			if provider.Compare(hashedPassword, []byte(password)) != nil {
+				unauthorized(w, challenge)
+				return
+			}
+			p := &Principal{Subject: username, Scheme: "Basic"}
+			next.ServeHTTP(w, r.WithContext(NewPrincipalContext(r.Context(), p)))
+		})
+	}
+}
+
+// BearerAuth authenticate request by `Authorization: Bearer <token>` with verifier
+func BearerAuth(verifier BearerVerifier) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				unauthorized(w, "Bearer")
+				return
+			}
+			p, err := verifier(r.Context(), token)
+			if err != nil || p == nil {
+				unauthorized(w, `Bearer error="invalid_token"`)
+				return
+			}
+			if len(p.Scheme) == 0 {
+				p.Scheme = "Bearer"
+			}
+			next.ServeHTTP(w, r.WithContext(NewPrincipalContext(r.Context(), p)))
+		})
+	}
+}
+
+// HMACAuth authenticate request signed by SignRequest, the timestamp must be in
+// MaxSkew and the nonce can only be used once.
+func HMACAuth(conf *HMACAuthConf) Middleware {
+	if conf.Secrets == nil {
+		panic("http: nil secret lookup of HMAC authentication")
+	}
+	maxSkew, maxBodySize, nonces := conf.MaxSkew, conf.MaxBodySize, conf.Nonces
+	if maxSkew <= 0 {
+		maxSkew = _defaultMaxSkew
+	}
+	if maxBodySize <= 0 {
+		maxBodySize = _defaultMaxBodySize
+	}
+	if nonces == nil {
+		nonces = auth.NewMemoryUsedCodeStore()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+			if conf.Now != nil {
+				now = conf.Now()
+			}
+			keyID, err := verifyRequest(r, conf.Secrets, nonces, now, maxSkew, maxBodySize)
+			if err != nil {
+				unauthorized(w, "HMAC")
+				return
+			}
+			p := &Principal{Subject: keyID, Scheme: "HMAC"}
+			next.ServeHTTP(w, r.WithContext(NewPrincipalContext(r.Context(), p)))
+		})
+	}
+}
+
+// SignRequest sign req with HMAC-SHA256 by secret, the body of req will be read
+// and replaced by an in-memory copy.
+func SignRequest(req *http.Request, keyID string, secret []byte) error {
+	body, err := readBody(req, -1)
+	if err != nil {
+		return err
+	}
+	var nonce [16]byte
+	if _, err = rand.Read(nonce[:]); err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	nonceStr := hex.EncodeToString(nonce[:])
+	req.Header.Set(HeaderSignatureKey, keyID)
+	req.Header.Set(HeaderSignatureTimestamp, timestamp)
+	req.Header.Set(HeaderSignatureNonce, nonceStr)
+	req.Header.Set(HeaderSignature, signature(req, secret, timestamp, nonceStr, body))
+	return nil
+}
+
+func verifyRequest(r *http.Request, secrets SecretLookup, nonces auth.UsedCodeStore, now time.Time, maxSkew time.Duration, maxBodySize int64) (string, error) {
+	keyID, timestamp := r.Header.Get(HeaderSignatureKey), r.Header.Get(HeaderSignatureTimestamp)
+	nonce, sig := r.Header.Get(HeaderSignatureNonce), r.Header.Get(HeaderSignature)
+	if len(keyID) == 0 || len(timestamp) == 0 || len(nonce) == 0 || len(sig) == 0 {
+		return "", ErrUnauthorized
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", ErrUnauthorized
+	}
+	if skew := now.Sub(time.Unix(ts, 0)); skew > maxSkew || skew < -maxSkew {
+		return "", ErrUnauthorized
+	}
+	secret, err := secrets(r.Context(), keyID)
+	if err != nil || len(secret) == 0 {
+		return "", ErrUnauthorized
+	}
+	body, err := readBody(r, maxBodySize)
+	if err != nil {
+		return "", err
+	}
+	if !hmac.Equal([]byte(sig), []byte(signature(r, secret, timestamp, nonce, body))) {
+		return "", ErrUnauthorized
+	}
+	// nonce is checked after signature verified to avoid polluted by forged requests,
+	// and it's no need to remember nonce longer than the accepted time window.
+	if !nonces.MarkUsed(keyID+"/"+nonce, 2*maxSkew) {
+		return "", ErrNonceReused
+	}
+	return keyID, nil
+}
+
+// signature base64 HMAC-SHA256 of `method\nrequestURI\ntimestamp\nnonce\nhex(sha256(body))`
+func signature(r *http.Request, secret []byte, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, secret)
+	for _, item := range []string{r.Method, r.URL.RequestURI(), timestamp, nonce} {
+		io.WriteString(mac, item)
+		mac.Write([]byte{'\n'})
+	}
+	io.WriteString(mac, hex.EncodeToString(bodyHash[:]))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// readBody read all body of r and replace it by an in-memory copy, no limit if maxSize < 0
+func readBody(r *http.Request, maxSize int64) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	defer r.Body.Close()
+
+	reader := io.Reader(r.Body)
+	if maxSize >= 0 {
+		reader = io.LimitReader(r.Body, maxSize+1)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if maxSize >= 0 && int64(len(body)) > maxSize {
+		return nil, ErrUnauthorized
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	authorization := r.Header.Get("Authorization")
+	if len(authorization) < 7 || !strings.EqualFold(authorization[:7], "Bearer ") {
+		return "", false
+	}
+	token := strings.TrimSpace(authorization[7:])
+	return token, len(token) > 0
+}
+
+func unauthorized(w http.ResponseWriter, challenge string) {
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alimy/tryst/auth"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func principalHandler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, ok := PrincipalFrom(r.Context())
+		if !ok {
+			t.Errorf("PrincipalFrom() want principal in context")
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		io.WriteString(w, p.Scheme+":"+p.Subject+":"+string(body))
+	})
+}
+
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestBasicAuth(t *testing.T) {
+	provider := auth.NewBcryptPasswordProvider(bcrypt.MinCost)
+	hashed, _ := provider.Generate([]byte("secret"))
+	lookup := func(_ context.Context, username string) ([]byte, error) {
+		if username != "alimy" {
+			return nil, errors.New("not found")
+		}
+		return hashed, nil
+	}
+	h := BasicAuth("tryst", provider, lookup)(principalHandler(t))
+	for _, data := range []struct {
+		username string
+		password string
+		code     int
+	}{
+		{"alimy", "secret", http.StatusOK},
+		{"alimy", "wrong", http.StatusUnauthorized},
+		{"other", "secret", http.StatusUnauthorized},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.SetBasicAuth(data.username, data.password)
+		if w := serve(h, r); w.Code != data.code {
+			t.Errorf("BasicAuth(%s, %s) want %d got %d", data.username, data.password, data.code, w.Code)
+		}
+	}
+	w := serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusUnauthorized || !strings.HasPrefix(w.Header().Get("WWW-Authenticate"), `Basic realm="tryst"`) {
+		t.Errorf("BasicAuth() without credentials got %d %s", w.Code, w.Header().Get("WWW-Authenticate"))
+	}
+}
+
+// countingProvider count calls of Compare
+type countingProvider struct {
+	auth.PasswordProvider
+	compared int
+}
+
+func (p *countingProvider) Compare(hashedPassword, password []byte) error {
+	p.compared++
+	return p.PasswordProvider.Compare(hashedPassword, password)
+}
+
+func TestBasicAuthUnknownUser(t *testing.T) {
+	provider := &countingProvider{PasswordProvider: auth.NewBcryptPasswordProvider(bcrypt.MinCost)}
+	lookup := func(_ context.Context, username string) ([]byte, error) {
+		return nil, errors.New("not found")
+	}
+	h := BasicAuth("tryst", provider, lookup)(principalHandler(t))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.SetBasicAuth("other", "secret")
+	if w := serve(h, r); w.Code != http.StatusUnauthorized {
+		t.Errorf("BasicAuth() of unknown user want %d got %d", http.StatusUnauthorized, w.Code)
+	}
+	if provider.compared != 1 {
+		t.Errorf("BasicAuth() of unknown user want compare once got %d", provider.compared)
+	}
+}
+
+func TestBearerAuth(t *testing.T) {
+	verifier := func(_ context.Context, token string) (*Principal, error) {
+		if token != "good" {
+			return nil, errors.New("invalid token")
+		}
+		return &Principal{Subject: "alimy", Claims: []string{"admin"}}, nil
+	}
+	h := BearerAuth(verifier)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if roles, ok := ClaimsFrom[[]string](r.Context()); !ok || roles[0] != "admin" {
+			t.Errorf("ClaimsFrom() got %v %t", roles, ok)
+		}
+	}))
+	for header, code := range map[string]int{
+		"Bearer good": http.StatusOK,
+		"bearer good": http.StatusOK,
+		"Bearer bad":  http.StatusUnauthorized,
+		"Basic good":  http.StatusUnauthorized,
+		"":            http.StatusUnauthorized,
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", header)
+		if w := serve(h, r); w.Code != code {
+			t.Errorf("BearerAuth(%q) want %d got %d", header, code, w.Code)
+		}
+	}
+}
+
+func TestHMACAuth(t *testing.T) {
+	secrets := func(_ context.Context, keyID string) ([]byte, error) {
+		if keyID != "k1" {
+			return nil, errors.New("unknown key")
+		}
+		return []byte("secret"), nil
+	}
+	h := Chain(principalHandler(t), HMACAuth(&HMACAuthConf{Secrets: secrets}))
+	mux := NewConnectMux()
+	mux.Handle("/core.v1.AuthenticateService/", h)
+
+	r := httptest.NewRequest(http.MethodPost, "/core.v1.AuthenticateService/login?a=1", strings.NewReader("payload"))
+	if err := SignRequest(r, "k1", []byte("secret")); err != nil {
+		t.Fatalf("SignRequest() error: %s", err)
+	}
+	replay := r.Clone(context.Background())
+	replay.Body = io.NopCloser(strings.NewReader("payload"))
+	if w := serve(mux, r); w.Code != http.StatusOK || w.Body.String() != "HMAC:k1:payload" {
+		t.Errorf("HMACAuth() want 200 got %d %s", w.Code, w.Body.String())
+	}
+	if w := serve(mux, replay); w.Code != http.StatusUnauthorized {
+		t.Errorf("HMACAuth(replay) want 401 got %d", w.Code)
+	}
+
+	tampered := httptest.NewRequest(http.MethodPost, "/core.v1.AuthenticateService/login", strings.NewReader("payload"))
+	SignRequest(tampered, "k1", []byte("secret"))
+	tampered.Body = io.NopCloser(strings.NewReader("tampered"))
+	if w := serve(mux, tampered); w.Code != http.StatusUnauthorized {
+		t.Errorf("HMACAuth(tampered) want 401 got %d", w.Code)
+	}
+
+	unknown := httptest.NewRequest(http.MethodGet, "/core.v1.AuthenticateService/login", nil)
+	SignRequest(unknown, "k2", []byte("secret"))
+	if w := serve(mux, unknown); w.Code != http.StatusUnauthorized {
+		t.Errorf("HMACAuth(unknown key) want 401 got %d", w.Code)
+	}
+}
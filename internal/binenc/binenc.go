@@ -0,0 +1,486 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+// Package binenc provides the compact binary format of container snapshots.
+//
+// A snapshot is a header followed by entries in chunks:
+//
+//	snapshot = version kind key-tag value-tag { chunk } 0
+//	chunk    = count(uvarint, > 0) entry * count
+//
+// Integers are varints, floats are little-endian IEEE 754 bits, strings,
+// byte slices and values of encoding.BinaryMarshaler are prefixed by their
+// length. Chunks let a snapshot of a concurrent container be written in one
+// pass, without knowing the count of entries in advance.
+package binenc
+
+import (
+	"bufio"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+// Version of the format
+const Version byte = 1
+
+// Kinds of containers
+const (
+	KindMap       byte = 'm'
+	KindSet       byte = 's'
+	KindSortedSet byte = 'z'
+)
+
+// Tag type tag of keys and values
+type Tag byte
+
+const (
+	TagNone Tag = iota
+	TagBool
+	TagInt
+	TagInt8
+	TagInt16
+	TagInt32
+	TagInt64
+	TagUint
+	TagUint8
+	TagUint16
+	TagUint32
+	TagUint64
+	TagUintptr
+	TagFloat32
+	TagFloat64
+	TagString
+	TagBytes
+	TagBinary
+)
+
+const (
+	// _chunkSize max count of entries in a chunk
+	_chunkSize = 1024
+	// _readSize max size of a read when the length is not trusted
+	_readSize = 64 << 10
+)
+
+var (
+	// ErrCorrupted is returned when the snapshot is malformed
+	ErrCorrupted = errors.New("corrupted snapshot")
+
+	_binaryMarshalerType   = reflect.TypeFor[encoding.BinaryMarshaler]()
+	_binaryUnmarshalerType = reflect.TypeFor[encoding.BinaryUnmarshaler]()
+)
+
+// Header header of a snapshot
+type Header struct {
+	Kind  byte
+	Key   Tag
+	Value Tag
+}
+
+// Codec encode and decode values of type T
+type Codec[T any] struct {
+	Tag    Tag
+	Encode func(e *Encoder, v T)
+	Decode func(d *Decoder) T
+}
+
+// CodecFor return the codec of T, T must be a bool, number, string or byte
+// slice, or implement encoding.BinaryMarshaler and its pointer implement
+// encoding.BinaryUnmarshaler.
+func CodecFor[T any]() (Codec[T], error) {
+	t := reflect.TypeFor[T]()
+	if t.Implements(_binaryMarshalerType) && reflect.PointerTo(t).Implements(_binaryUnmarshalerType) {
+		return binaryCodec[T](), nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return Codec[T]{
+			Tag: TagBool,
+			Encode: func(e *Encoder, v T) {
+				e.buf = append(e.buf, 0)
+				if *(*bool)(unsafe.Pointer(&v)) {
+					e.buf[len(e.buf)-1] = 1
+				}
+			},
+			Decode: func(d *Decoder) (v T) {
+				switch d.byte() {
+				case 0:
+				case 1:
+					*(*bool)(unsafe.Pointer(&v)) = true
+				default:
+					d.fail(ErrCorrupted)
+				}
+				return
+			},
+		}, nil
+	case reflect.Int:
+		return signedCodec[T, int](TagInt), nil
+	case reflect.Int8:
+		return signedCodec[T, int8](TagInt8), nil
+	case reflect.Int16:
+		return signedCodec[T, int16](TagInt16), nil
+	case reflect.Int32:
+		return signedCodec[T, int32](TagInt32), nil
+	case reflect.Int64:
+		return signedCodec[T, int64](TagInt64), nil
+	case reflect.Uint:
+		return unsignedCodec[T, uint](TagUint), nil
+	case reflect.Uint8:
+		return unsignedCodec[T, uint8](TagUint8), nil
+	case reflect.Uint16:
+		return unsignedCodec[T, uint16](TagUint16), nil
+	case reflect.Uint32:
+		return unsignedCodec[T, uint32](TagUint32), nil
+	case reflect.Uint64:
+		return unsignedCodec[T, uint64](TagUint64), nil
+	case reflect.Uintptr:
+		return unsignedCodec[T, uintptr](TagUintptr), nil
+	case reflect.Float32:
+		return Codec[T]{
+			Tag: TagFloat32,
+			Encode: func(e *Encoder, v T) {
+				e.buf = binary.LittleEndian.AppendUint32(e.buf, math.Float32bits(*(*float32)(unsafe.Pointer(&v))))
+			},
+			Decode: func(d *Decoder) (v T) {
+				*(*float32)(unsafe.Pointer(&v)) = math.Float32frombits(binary.LittleEndian.Uint32(d.read(4)))
+				return
+			},
+		}, nil
+	case reflect.Float64:
+		return Codec[T]{
+			Tag: TagFloat64,
+			Encode: func(e *Encoder, v T) {
+				e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(*(*float64)(unsafe.Pointer(&v))))
+			},
+			Decode: func(d *Decoder) (v T) {
+				*(*float64)(unsafe.Pointer(&v)) = math.Float64frombits(binary.LittleEndian.Uint64(d.read(8)))
+				return
+			},
+		}, nil
+	case reflect.String:
+		return Codec[T]{
+			Tag: TagString,
+			Encode: func(e *Encoder, v T) {
+				e.string(*(*string)(unsafe.Pointer(&v)))
+			},
+			Decode: func(d *Decoder) (v T) {
+				*(*string)(unsafe.Pointer(&v)) = string(d.bytes())
+				return
+			},
+		}, nil
+	case reflect.Slice:
+		if t.Elem().Kind() != reflect.Uint8 {
+			break
+		}
+		return Codec[T]{
+			Tag: TagBytes,
+			Encode: func(e *Encoder, v T) {
+				e.bytes(*(*[]byte)(unsafe.Pointer(&v)))
+			},
+			Decode: func(d *Decoder) (v T) {
+				*(*[]byte)(unsafe.Pointer(&v)) = d.bytes()
+				return
+			},
+		}, nil
+	}
+	return Codec[T]{}, fmt.Errorf("unsupported type %s", t)
+}
+
+func signedCodec[T any, I int | int8 | int16 | int32 | int64](tag Tag) Codec[T] {
+	return Codec[T]{
+		Tag: tag,
+		Encode: func(e *Encoder, v T) {
+			e.buf = binary.AppendVarint(e.buf, int64(*(*I)(unsafe.Pointer(&v))))
+		},
+		Decode: func(d *Decoder) (v T) {
+			x := d.varint()
+			if int64(I(x)) != x {
+				d.fail(ErrCorrupted)
+			}
+			*(*I)(unsafe.Pointer(&v)) = I(x)
+			return
+		},
+	}
+}
+
+func unsignedCodec[T any, U uint | uint8 | uint16 | uint32 | uint64 | uintptr](tag Tag) Codec[T] {
+	return Codec[T]{
+		Tag: tag,
+		Encode: func(e *Encoder, v T) {
+			e.buf = binary.AppendUvarint(e.buf, uint64(*(*U)(unsafe.Pointer(&v))))
+		},
+		Decode: func(d *Decoder) (v T) {
+			x := d.uvarint()
+			if uint64(U(x)) != x {
+				d.fail(ErrCorrupted)
+			}
+			*(*U)(unsafe.Pointer(&v)) = U(x)
+			return
+		},
+	}
+}
+
+func binaryCodec[T any]() Codec[T] {
+	return FuncCodec(func(v T) ([]byte, error) {
+		return any(v).(encoding.BinaryMarshaler).MarshalBinary()
+	}, func(data []byte) (v T, err error) {
+		err = any(&v).(encoding.BinaryUnmarshaler).UnmarshalBinary(data)
+		return
+	})
+}
+
+// FuncCodec return the codec of T that values are encoded by marshal and
+// decoded by unmarshal, it's compatible with the codec of a type that
+// implement encoding.BinaryMarshaler.
+func FuncCodec[T any](marshal func(T) ([]byte, error), unmarshal func([]byte) (T, error)) Codec[T] {
+	return Codec[T]{
+		Tag: TagBinary,
+		Encode: func(e *Encoder, v T) {
+			data, err := marshal(v)
+			if err != nil {
+				e.fail(err)
+				return
+			}
+			e.bytes(data)
+		},
+		Decode: func(d *Decoder) (v T) {
+			data := d.bytes()
+			if d.err != nil {
+				return
+			}
+			v, err := unmarshal(data)
+			if err != nil {
+				d.fail(err)
+			}
+			return
+		},
+	}
+}
+
+// Encoder write a snapshot, entries are buffered and written by chunks
+type Encoder struct {
+	w     io.Writer
+	n     int64
+	err   error
+	buf   []byte
+	count int // count of entries in buf
+	start int // start of the current chunk in buf
+}
+
+// NewEncoder return an encoder that write the snapshot of header h to w
+func NewEncoder(w io.Writer, h Header) *Encoder {
+	e := &Encoder{w: w, buf: make([]byte, 0, 4096)}
+	e.buf = append(e.buf, Version, h.Kind, byte(h.Key), byte(h.Value))
+	e.start = len(e.buf)
+	return e
+}
+
+// Next end an entry
+func (e *Encoder) Next() {
+	if e.count++; e.count >= _chunkSize || len(e.buf) >= _readSize {
+		e.flush()
+	}
+}
+
+// Close write the rest entries and the end of snapshot, it return the count
+// of bytes written and the first error encountered.
+func (e *Encoder) Close() (int64, error) {
+	e.flush()
+	e.buf = append(e.buf[:0], 0)
+	e.write()
+	return e.n, e.err
+}
+
+func (e *Encoder) flush() {
+	if e.count > 0 {
+		var head [binary.MaxVarintLen64]byte
+		n := binary.PutUvarint(head[:], uint64(e.count))
+		// the chunk is prefixed with its count, space is made by moving
+		e.buf = append(e.buf, head[:n]...)
+		copy(e.buf[e.start+n:], e.buf[e.start:len(e.buf)-n])
+		copy(e.buf[e.start:], head[:n])
+	}
+	e.write()
+	e.count, e.start = 0, 0
+}
+
+func (e *Encoder) write() {
+	if e.err == nil && len(e.buf) > 0 {
+		n, err := e.w.Write(e.buf)
+		e.n += int64(n)
+		e.fail(err)
+	}
+	e.buf = e.buf[:0]
+}
+
+func (e *Encoder) bytes(b []byte) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *Encoder) string(s string) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *Encoder) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// Decoder read a snapshot
+type Decoder struct {
+	r    byteReader
+	n    int64
+	err  error
+	left uint64 // count of entries left in the current chunk
+	done bool
+	buf  [8]byte
+}
+
+// NewDecoder return a decoder that read the snapshot of header h from r.
+// If r is not an io.ByteReader it's buffered, and the decoder may read beyond
+// the end of the snapshot.
+func NewDecoder(r io.Reader, h Header) *Decoder {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	d := &Decoder{r: br}
+	header := d.read(4)
+	switch {
+	case d.err != nil:
+	case header[0] != Version:
+		d.fail(fmt.Errorf("unsupported snapshot version %d", header[0]))
+	case header[1] != h.Kind || Tag(header[2]) != h.Key || Tag(header[3]) != h.Value:
+		d.fail(fmt.Errorf("mismatched snapshot %q of types %d/%d, want %q of types %d/%d",
+			header[1], header[2], header[3], h.Kind, h.Key, h.Value))
+	}
+	return d
+}
+
+// Next prepare the next entry, it return false at the end of snapshot or
+// after an error.
+func (d *Decoder) Next() bool {
+	if d.err != nil || d.done {
+		return false
+	}
+	if d.left == 0 {
+		if d.left = d.uvarint(); d.err != nil {
+			return false
+		}
+		if d.left == 0 {
+			d.done = true
+			return false
+		}
+	}
+	d.left--
+	return true
+}
+
+// Err return the first error encountered
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// N return the count of bytes read
+func (d *Decoder) N() int64 {
+	return d.n
+}
+
+func (d *Decoder) fail(err error) {
+	if d.err == nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		d.err = err
+	}
+}
+
+// ReadByte implement io.ByteReader for reading varints
+func (d *Decoder) ReadByte() (byte, error) {
+	c, err := d.r.ReadByte()
+	if err == nil {
+		d.n++
+	}
+	return c, err
+}
+
+func (d *Decoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	c, err := d.ReadByte()
+	d.fail(err)
+	return c
+}
+
+func (d *Decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	x, err := binary.ReadUvarint(d)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		err = ErrCorrupted
+	}
+	d.fail(err)
+	return x
+}
+
+func (d *Decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	x, err := binary.ReadVarint(d)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		err = ErrCorrupted
+	}
+	d.fail(err)
+	return x
+}
+
+// read return the next n bytes, n is at most 8
+func (d *Decoder) read(n int) []byte {
+	b := d.buf[:n]
+	if d.err != nil {
+		clear(b)
+		return b
+	}
+	m, err := io.ReadFull(d.r, b)
+	d.n += int64(m)
+	d.fail(err)
+	return b
+}
+
+// bytes return bytes prefixed by the length, the length is not trusted so
+// large data is read piece by piece.
+func (d *Decoder) bytes() []byte {
+	size := d.uvarint()
+	if d.err != nil {
+		return nil
+	}
+	b := make([]byte, 0, min(size, _readSize))
+	for uint64(len(b)) < size {
+		n := int(min(size-uint64(len(b)), _readSize))
+		b = append(b, make([]byte, n)...)
+		m, err := io.ReadFull(d.r, b[len(b)-n:])
+		d.n += int64(m)
+		if err != nil {
+			d.fail(err)
+			return nil
+		}
+	}
+	return b
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package binenc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+)
+
+type level int8
+
+func roundTrip[T any](t *testing.T, values ...T) []T {
+	t.Helper()
+	c, err := CodecFor[T]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := Header{Kind: KindSet, Key: c.Tag}
+	var buf bytes.Buffer
+	e := NewEncoder(&buf, h)
+	for _, v := range values {
+		c.Encode(e, v)
+		e.Next()
+	}
+	n, err := e.Close()
+	if err != nil || n != int64(buf.Len()) {
+		t.Fatalf("Close() = %d, %v, want %d, nil", n, err, buf.Len())
+	}
+	size := buf.Len()
+	d := NewDecoder(&buf, h)
+	var res []T
+	for d.Next() {
+		res = append(res, c.Decode(d))
+	}
+	if d.Err() != nil || d.N() != int64(size) {
+		t.Fatalf("decode: %d, %v, want %d, nil", d.N(), d.Err(), size)
+	}
+	return res
+}
+
+func TestCodec(t *testing.T) {
+	if res := roundTrip(t, true, false); res[0] != true || res[1] != false {
+		t.Errorf("bool: %v", res)
+	}
+	if res := roundTrip(t, int64(math.MinInt64), -1, 0, math.MaxInt64); res[0] != math.MinInt64 || res[1] != -1 || res[3] != math.MaxInt64 {
+		t.Errorf("int64: %v", res)
+	}
+	if res := roundTrip(t, level(-3), level(127)); res[0] != -3 || res[1] != 127 {
+		t.Errorf("level: %v", res)
+	}
+	if res := roundTrip(t, uint16(0), math.MaxUint16); res[1] != math.MaxUint16 {
+		t.Errorf("uint16: %v", res)
+	}
+	if res := roundTrip(t, float32(1.5), float32(math.Inf(-1))); res[0] != 1.5 || !math.IsInf(float64(res[1]), -1) {
+		t.Errorf("float32: %v", res)
+	}
+	if res := roundTrip(t, 0.1, math.NaN()); res[0] != 0.1 || !math.IsNaN(res[1]) {
+		t.Errorf("float64: %v", res)
+	}
+	if res := roundTrip(t, "", "hello"); res[0] != "" || res[1] != "hello" {
+		t.Errorf("string: %v", res)
+	}
+	if res := roundTrip(t, []byte("abc"), nil); string(res[0]) != "abc" || len(res[1]) != 0 {
+		t.Errorf("bytes: %v", res)
+	}
+	now := time.Now()
+	if res := roundTrip(t, now); !res[0].Equal(now) {
+		t.Errorf("time: %v", res)
+	}
+	if _, err := CodecFor[any](); err == nil {
+		t.Error("CodecFor[any]() want error")
+	}
+	if _, err := CodecFor[struct{ A int }](); err == nil {
+		t.Error("CodecFor[struct]() want error")
+	}
+}
+
+func TestChunks(t *testing.T) {
+	const n = 3*_chunkSize + 7
+	values := make([]int, n)
+	for i := range values {
+		values[i] = i * 1000
+	}
+	res := roundTrip(t, values...)
+	if len(res) != n || res[n-1] != (n-1)*1000 {
+		t.Fatalf("got %d values", len(res))
+	}
+	if res := roundTrip[string](t); len(res) != 0 {
+		t.Fatalf("empty: %v", res)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	c, _ := CodecFor[string]()
+	h := Header{Kind: KindSet, Key: c.Tag}
+	var buf bytes.Buffer
+	e := NewEncoder(&buf, h)
+	for _, v := range []string{"alpha", "beta", "gamma"} {
+		c.Encode(e, v)
+		e.Next()
+	}
+	e.Close()
+	data := buf.Bytes()
+
+	decode := func(data []byte, h Header) error {
+		d := NewDecoder(bytes.NewReader(data), h)
+		for d.Next() {
+			c.Decode(d)
+		}
+		return d.Err()
+	}
+	if err := decode(data, h); err != nil {
+		t.Fatal(err)
+	}
+	for i := range len(data) {
+		if err := decode(data[:i], h); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("truncated at %d: %v", i, err)
+		}
+	}
+	if err := decode(data, Header{Kind: KindMap, Key: c.Tag}); err == nil {
+		t.Error("mismatched kind want error")
+	}
+	if err := decode(data, Header{Kind: KindSet, Key: TagBytes}); err == nil {
+		t.Error("mismatched tag want error")
+	}
+	bad := append([]byte{Version + 1}, data[1:]...)
+	if err := decode(bad, h); err == nil {
+		t.Error("unsupported version want error")
+	}
+
+	ic, _ := CodecFor[int8]()
+	var big bytes.Buffer
+	e = NewEncoder(&big, Header{Kind: KindSet, Key: TagInt8})
+	signedCodec[int64, int64](TagInt8).Encode(e, 1000)
+	e.Next()
+	e.Close()
+	d := NewDecoder(&big, Header{Kind: KindSet, Key: TagInt8})
+	for d.Next() {
+		ic.Decode(d)
+	}
+	if !errors.Is(d.Err(), ErrCorrupted) {
+		t.Errorf("overflow: %v", d.Err())
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+// Package singleflight provides a duplicate function call suppression mechanism
+// with typed key and value, like golang.org/x/sync/singleflight but the waiters
+// can give up waiting by context while the call continues for others.
+package singleflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// errGoexit returned to waiters if fn called runtime.Goexit
+var errGoexit = errors.New("singleflight: runtime.Goexit was called in fn")
+
+// panicError a panic of fn with the stack trace of the goroutine running fn, it's
+// panicked again in every waiter.
+type panicError struct {
+	value any
+	stack []byte
+}
+
+func (p *panicError) Error() string {
+	return fmt.Sprintf("%v\n\n%s", p.value, p.stack)
+}
+
+func (p *panicError) Unwrap() error {
+	err, _ := p.value.(error)
+	return err
+}
+
+// Call an in-flight or completed call
+type Call[V any] struct {
+	done  chan struct{}
+	val   V
+	err   error
+	panic *panicError
+}
+
+// Done return a channel that's closed when the call completed
+func (c *Call[V]) Done() <-chan struct{} {
+	return c.done
+}
+
+// Result return the result of call, it must be called after Done closed. It panics
+// with the value and stack trace if fn panicked.
+func (c *Call[V]) Result() (V, error) {
+	if c.panic != nil {
+		panic(c.panic)
+	}
+	return c.val, c.err
+}
+
+// Wait wait the call completed or ctx done, it panics like Result if fn panicked.
+func (c *Call[V]) Wait(ctx context.Context) (val V, err error) {
+	select {
+	case <-c.done:
+		return c.Result()
+	case <-ctx.Done():
+		return val, ctx.Err()
+	}
+}
+
+// Group represents a class of work and forms a namespace in which units of work
+// can be executed with duplicate suppression.
+type Group[K comparable, V any] struct {
+	mu sync.Mutex
+	m  map[K]*Call[V]
+}
+
+// DoCall start fn in a new goroutine if no call of key is in-flight, and return the
+// in-flight call of key. A panic of fn is recovered and panicked again in every
+// waiter by Wait or Result, like golang.org/x/sync/singleflight does.
+func (g *Group[K, V]) DoCall(key K, fn func() (V, error)) (c *Call[V], shared bool) {
+	g.mu.Lock()
+	if g.m == nil {
+		g.m = make(map[K]*Call[V])
+	}
+	if c, exist := g.m[key]; exist {
+		g.mu.Unlock()
+		return c, true
+	}
+	c = &Call[V]{done: make(chan struct{})}
+	g.m[key] = c
+	g.mu.Unlock()
+
+	go g.doCall(c, key, fn)
+	return c, false
+}
+
+// Do execute fn and wait the result, only one execution of key is in-flight at a
+// time. It return ctx.Err() if ctx done before the call completed but the call
+// continues for other waiters.
+func (g *Group[K, V]) Do(ctx context.Context, key K, fn func() (V, error)) (V, error) {
+	c, _ := g.DoCall(key, fn)
+	return c.Wait(ctx)
+}
+
+// Forget tells the group to forget about a key, future calls of key will execute
+// fn rather than waiting for an earlier call to complete.
+func (g *Group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	delete(g.m, key)
+	g.mu.Unlock()
+}
+
+func (g *Group[K, V]) doCall(c *Call[V], key K, fn func() (V, error)) {
+	normalReturn := false
+	defer func() {
+		if !normalReturn {
+			if r := recover(); r != nil {
+				c.panic = &panicError{value: r, stack: debug.Stack()}
+			} else {
+				c.err = errGoexit
+			}
+		}
+		g.mu.Lock()
+		if g.m[key] == c {
+			delete(g.m, key)
+		}
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.val, c.err = fn()
+	normalReturn = true
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package singleflight
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDo(t *testing.T) {
+	var g Group[int, string]
+	v, err := g.Do(context.Background(), 1, func() (string, error) {
+		return "bar", nil
+	})
+	if v != "bar" || err != nil {
+		t.Errorf("Do() want bar, nil got %s, %v", v, err)
+	}
+}
+
+func TestDoPanic(t *testing.T) {
+	var g Group[int, string]
+	release := make(chan struct{})
+	c, _ := g.DoCall(1, func() (string, error) {
+		<-release
+		panic("boom")
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				r := recover()
+				if pe, ok := r.(*panicError); !ok || pe.value != "boom" || !strings.Contains(pe.Error(), "singleflight") {
+					t.Errorf("Do() want re-panic with stack got %v", r)
+				}
+			}()
+			g.Do(context.Background(), 1, func() (string, error) {
+				return "", nil
+			})
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	<-c.Done()
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("Result() want re-panic got nil")
+			}
+		}()
+		c.Result()
+	}()
+	_, err := g.Do(context.Background(), 1, func() (string, error) {
+		runtime.Goexit()
+		return "", nil
+	})
+	if !errors.Is(err, errGoexit) {
+		t.Errorf("Do() want errGoexit got %v", err)
+	}
+}
+
+func TestDoDupSuppress(t *testing.T) {
+	var g Group[string, int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func() (int, error) {
+		calls.Add(1)
+		<-release
+		return 1, nil
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := g.Do(context.Background(), "key", fn); v != 1 || err != nil {
+				t.Errorf("Do() want 1, nil got %d, %v", v, err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn want called once got %d", n)
+	}
+}
+
+func TestDoContext(t *testing.T) {
+	var g Group[string, int]
+	release := make(chan struct{})
+	fn := func() (int, error) {
+		<-release
+		return 1, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := g.Do(ctx, "key", fn); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do() want DeadlineExceeded got %v", err)
+	}
+	c, shared := g.DoCall("key", fn)
+	if !shared {
+		t.Errorf("DoCall() want shared the in-flight call")
+	}
+	close(release)
+	if v, err := c.Wait(context.Background()); v != 1 || err != nil {
+		t.Errorf("Wait() want 1, nil got %d, %v", v, err)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package stringx
+
+import (
+	"unicode"
+)
+
+// HasLetter checks if the string contains any unicode letter.
+func HasLetter(s string) bool {
+	return hasFunc(s, unicode.IsLetter)
+}
+
+// HasUpper checks if the string contains any unicode upper case letter.
+func HasUpper(s string) bool {
+	return hasFunc(s, unicode.IsUpper)
+}
+
+// HasLower checks if the string contains any unicode lower case letter.
+func HasLower(s string) bool {
+	return hasFunc(s, unicode.IsLower)
+}
+
+// HasNumeric checks if the string contains any digit.
+func HasNumeric(s string) bool {
+	return hasFunc(s, unicode.IsDigit)
+}
+
+// HasSymbol checks if the string contains any character that is neither a letter nor a digit.
+func HasSymbol(s string) bool {
+	return hasFunc(s, func(v rune) bool {
+		return !isAlphanumeric(v)
+	})
+}
+
+func hasFunc(s string, f func(rune) bool) bool {
+	for _, v := range s {
+		if f(v) {
+			return true
+		}
+	}
+	return false
+}
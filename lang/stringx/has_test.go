@@ -0,0 +1,32 @@
+// Copyright 2026 Michael Li <alimy@niubiu.com>. All rights reserved.
+// Use of this source code is governed by Apache License 2.0 that
+// can be found in the LICENSE file.
+
+package stringx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHas(t *testing.T) {
+	is := assert.New(t)
+
+	is.False(HasLetter(""))
+	is.False(HasLetter("123 !"))
+	is.True(HasLetter("12a"))
+	is.True(HasLetter("bròwn"))
+
+	is.False(HasUpper("abc1"))
+	is.True(HasUpper("abC1"))
+	is.False(HasLower("ABC1"))
+	is.True(HasLower("ABc1"))
+
+	is.False(HasNumeric("abc"))
+	is.True(HasNumeric("ab3"))
+
+	is.False(HasSymbol("abc123"))
+	is.True(HasSymbol("abc 123"))
+	is.True(HasSymbol("abc#123"))
+}